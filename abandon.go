@@ -0,0 +1,32 @@
+package flaresolverr
+
+import "context"
+
+// abandonable reports whether the command runs in a temporary session when WithCancelInFlight is set.
+func abandonable(cmd *flaresolverrCommand) bool {
+	return (cmd.Cmd == CommandRequestget || cmd.Cmd == CommandRequestpost) && cmd.Session == ""
+}
+
+// runInTemporarySession runs the command in a session of its own, destroyed once done.
+// FlareSolverr cannot cancel a command: destroying its session is the only way
+// to stop the browser when the caller gives up.
+func (c *client) runInTemporarySession(ctx context.Context, cmd *flaresolverrCommand, o *requestOptions) (*Response, error) {
+	session := c.newUUID()
+	create := &flaresolverrCommand{Cmd: CommandSessionscreate, Session: session.String(), Proxy: cmd.Proxy}
+	if _, err := c.run(ctx, create, o); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		// the caller context may be done already
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout+c.timeoutPadding)
+		defer cancel()
+
+		destroy := &flaresolverrCommand{Cmd: CommandSessionsdestroy, Session: session.String()}
+		_, _ = c.run(ctx, destroy, o)
+	}()
+
+	request := *cmd
+	request.Session = session.String()
+	return c.run(ctx, &request, o)
+}
@@ -0,0 +1,58 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_client_WithCancelInFlight(t *testing.T) {
+	var mu sync.Mutex
+	var commands []string
+	destroyed := make(chan string, 1)
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		commands = append(commands, cmd.Cmd.String()+" "+cmd.Session)
+		mu.Unlock()
+
+		switch cmd.Cmd {
+		case CommandRequestget:
+			time.Sleep(200 * time.Millisecond)
+		case CommandSessionsdestroy:
+			destroyed <- cmd.Session
+		}
+		return http.StatusOK, &Response{Status: "ok"}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithCancelInFlight())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.Get(ctx, "https://example.com", uuid.Nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	var session string
+	select {
+	case session = <-destroyed:
+	case <-time.After(time.Second):
+		t.Fatal("the temporary session has not been destroyed")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"sessions.create " + session, "request.get " + session, "sessions.destroy " + session}
+	if len(commands) != len(want) {
+		t.Fatalf("commands = %v, want %v", commands, want)
+	}
+	for i := range want {
+		if commands[i] != want[i] {
+			t.Errorf("commands[%d] = %q, want %q", i, commands[i], want[i])
+		}
+	}
+}
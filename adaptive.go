@@ -0,0 +1,68 @@
+package flaresolverr
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// adaptiveWindow is the number of solve durations kept per domain.
+	adaptiveWindow = 100
+	// adaptiveMinSamples is the number of solves needed before adapting the timeout of a domain.
+	adaptiveMinSamples = 5
+	// adaptiveMargin is added to the 95th percentile of the solve durations.
+	adaptiveMargin = 5 * time.Second
+)
+
+// adaptiveTimeout derives the timeout of the requests to a domain from its recent solve durations,
+// see WithAdaptiveTimeout.
+type adaptiveTimeout struct {
+	mu      sync.Mutex
+	domains map[string]*durationWindow
+}
+
+// durationWindow holds the last solve durations of a domain.
+type durationWindow struct {
+	samples [adaptiveWindow]time.Duration
+	n, next int
+}
+
+func newAdaptiveTimeout() *adaptiveTimeout {
+	return &adaptiveTimeout{domains: make(map[string]*durationWindow)}
+}
+
+// observe records how long a request to u took to solve.
+func (a *adaptiveTimeout) observe(u string, d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	host := hostname(u)
+	w, ok := a.domains[host]
+	if !ok {
+		w = new(durationWindow)
+		a.domains[host] = w
+	}
+
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % adaptiveWindow
+	w.n = min(w.n+1, adaptiveWindow)
+}
+
+// timeout returns the 95th percentile of the solve durations of u plus a margin,
+// capped at limit, or limit until enough solves have been observed.
+func (a *adaptiveTimeout) timeout(u string, limit time.Duration) time.Duration {
+	a.mu.Lock()
+	w, ok := a.domains[hostname(u)]
+	if !ok || w.n < adaptiveMinSamples {
+		a.mu.Unlock()
+		return limit
+	}
+
+	samples := slices.Clone(w.samples[:w.n])
+	a.mu.Unlock()
+
+	slices.Sort(samples)
+	p95 := samples[(95*len(samples)+99)/100-1]
+	return min(p95+adaptiveMargin, limit)
+}
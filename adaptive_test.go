@@ -0,0 +1,94 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_adaptiveTimeout_timeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []time.Duration
+		u       string
+		want    time.Duration
+	}{
+		{
+			name: "Unknown domain",
+			u:    "https://example.org",
+			want: time.Minute,
+		},
+		{
+			name:    "Too few samples",
+			samples: []time.Duration{time.Second, time.Second},
+			u:       "https://example.com",
+			want:    time.Minute,
+		},
+		{
+			name:    "95th percentile",
+			samples: []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 2 * time.Second, 4 * time.Second, time.Second},
+			u:       "https://example.com/page",
+			want:    4*time.Second + adaptiveMargin,
+		},
+		{
+			name:    "Capped",
+			samples: []time.Duration{time.Minute, time.Minute, time.Minute, time.Minute, time.Minute},
+			u:       "https://example.com",
+			want:    time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newAdaptiveTimeout()
+			for _, d := range tt.samples {
+				a.observe("https://example.com", d)
+			}
+
+			if got := a.timeout(tt.u, time.Minute); got != tt.want {
+				t.Errorf("timeout() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_adaptiveTimeout_window(t *testing.T) {
+	a := newAdaptiveTimeout()
+	for i := 0; i < adaptiveWindow; i++ {
+		a.observe("https://example.com", 30*time.Second)
+	}
+
+	// the slow solves leave the window
+	for i := 0; i < adaptiveWindow; i++ {
+		a.observe("https://example.com", time.Second)
+	}
+
+	if got, want := a.timeout("https://example.com", time.Minute), time.Second+adaptiveMargin; got != want {
+		t.Errorf("timeout() = %s, want %s", got, want)
+	}
+}
+
+func Test_client_WithAdaptiveTimeout(t *testing.T) {
+	var got int
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		got = cmd.MaxTimeout
+		return http.StatusOK, &Response{Status: "ok"}
+	})
+	c := New(srv.baseURL, time.Minute, srv.httpClient, WithAdaptiveTimeout())
+
+	for i := 0; i <= adaptiveMinSamples; i++ {
+		if _, err := c.Get(context.Background(), "https://example.com", uuid.Nil); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+
+		if i < adaptiveMinSamples && got != int(time.Minute.Milliseconds()) {
+			t.Errorf("Get() maxTimeout = %d before enough solves, want the client timeout", got)
+		}
+	}
+
+	if got <= 0 || got > int((time.Second+adaptiveMargin).Milliseconds()) {
+		t.Errorf("Get() maxTimeout = %d, want the adapted timeout", got)
+	}
+}
@@ -0,0 +1,86 @@
+package flaresolverr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// AuditRecord is a line of the audit log, describing a command sent to FlareSolverr.
+type AuditRecord struct {
+	// Time is when the command started.
+	Time time.Time `json:"time"`
+
+	Command Command `json:"cmd"`
+
+	// Host is the target host. The full URL is not logged, URLHash is its SHA-256 to correlate commands.
+	Host    string `json:"host,omitempty"`
+	URLHash string `json:"urlHash,omitempty"`
+
+	Session   string `json:"session,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+
+	// Outcome is "ok" or "error", Error describes the failure.
+	Outcome string `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+
+	Attempts   int   `json:"attempts"`
+	DurationMs int64 `json:"durationMs"`
+}
+
+// auditLog appends a JSON line per command to a writer.
+type auditLog struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newAuditLog(w io.Writer) *auditLog {
+	return &auditLog{enc: json.NewEncoder(w)}
+}
+
+func newAuditRecord(cmd *flaresolverrCommand, o *requestOptions, start time.Time, elapsed time.Duration, attempts int, err error) *AuditRecord {
+	record := &AuditRecord{
+		Time:       start.UTC(),
+		Command:    cmd.Cmd,
+		Session:    cmd.Session,
+		RequestID:  o.requestID,
+		Outcome:    "ok",
+		Attempts:   attempts,
+		DurationMs: elapsed.Milliseconds(),
+	}
+
+	if cmd.URL != "" {
+		record.URLHash = urlHash(cmd.URL)
+		if u, err := url.Parse(cmd.URL); err == nil {
+			record.Host = u.Hostname()
+		}
+	}
+
+	if err != nil {
+		record.Outcome, record.Error = "error", err.Error()
+	}
+
+	return record
+}
+
+// urlHash returns the hex encoded SHA-256 of u.
+func urlHash(u string) string {
+	sum := sha256.Sum256([]byte(u))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *client) audit(ctx context.Context, record *AuditRecord) {
+	c.auditLog.mu.Lock()
+	err := c.auditLog.enc.Encode(record)
+	c.auditLog.mu.Unlock()
+
+	if err != nil {
+		c.log(ctx, slog.LevelWarn, "cannot write flaresolverr audit log", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,80 @@
+package flaresolverr
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
+)
+
+func Test_client_WithAuditLog(t *testing.T) {
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		if strings.Contains(cmd.URL, "/timeout") {
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: maximum timeout reached"}
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+
+	var log bytes.Buffer
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithAuditLog(&log), WithRetryPolicy(RetryPolicy{MaxAttempts: 2}))
+
+	ctx := context.Background()
+	session := uuid.New()
+	_, _ = c.CreateSession(ctx, session)
+	_, _ = c.PostWithOptions(ctx, "https://example.com/login", session, "password=secret", WithRequestID("job-1"))
+	_, _ = c.Get(ctx, "https://example.com/timeout?token=secret", uuid.Nil)
+
+	if strings.Contains(log.String(), "secret") {
+		t.Errorf("audit log contains the URL or post data: %s", log.String())
+	}
+
+	var got []AuditRecord
+	scanner := bufio.NewScanner(&log)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid audit line %q: %v", scanner.Text(), err)
+		}
+
+		if record.Time.IsZero() {
+			t.Errorf("audit record %+v has no time", record)
+		}
+		got = append(got, record)
+	}
+
+	want := []AuditRecord{
+		{Command: CommandSessionscreate, Session: session.String(), Outcome: "ok", Attempts: 1},
+		{
+			Command:   CommandRequestpost,
+			Host:      "example.com",
+			URLHash:   urlHash("https://example.com/login"),
+			Session:   session.String(),
+			RequestID: "job-1",
+			Outcome:   "ok",
+			Attempts:  1,
+		},
+		{
+			Command:  CommandRequestget,
+			Host:     "example.com",
+			URLHash:  urlHash("https://example.com/timeout?token=secret"),
+			Outcome:  "error",
+			Attempts: 2,
+		},
+	}
+
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(AuditRecord{}, "Time", "DurationMs", "Error")); diff != "" {
+		t.Errorf("audit log mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(got) == 3 && !strings.Contains(got[2].Error, ErrRequestTimeout.Error()) {
+		t.Errorf("audit error = %q, want %q", got[2].Error, ErrRequestTimeout)
+	}
+}
@@ -0,0 +1,79 @@
+package flaresolverr
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// BatchOption customizes a batch of requests.
+type BatchOption func(*batchOptions)
+
+type batchOptions struct {
+	concurrency int
+	session     uuid.UUID
+	request     []RequestOption
+}
+
+// WithBatchConcurrency limits the number of requests of a batch running at the same time.
+// Defaults to 4, values below 1 are ignored.
+func WithBatchConcurrency(n int) BatchOption {
+	return func(o *batchOptions) {
+		if n > 0 {
+			o.concurrency = n
+		}
+	}
+}
+
+// WithBatchSession sends every request of a batch within the given session.
+func WithBatchSession(session uuid.UUID) BatchOption {
+	return func(o *batchOptions) {
+		o.session = session
+	}
+}
+
+// WithBatchRequestOptions applies opts to every request of a batch.
+func WithBatchRequestOptions(opts ...RequestOption) BatchOption {
+	return func(o *batchOptions) {
+		o.request = append(o.request, opts...)
+	}
+}
+
+// GetBatch gets every URL of urls, running a bounded number of requests at the same time.
+// Responses and errors are returned in the order of urls: for each URL,
+// either its response or its error is set.
+func (c *client) GetBatch(ctx context.Context, urls []string, opts ...BatchOption) ([]*Response, []error) {
+	o := &batchOptions{concurrency: 4}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	responses := make([]*Response, len(urls))
+	errs := make([]error, len(urls))
+	slots := make(chan struct{}, o.concurrency)
+
+	var wg sync.WaitGroup
+	for i, u := range urls {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			for ; i < len(urls); i++ {
+				errs[i] = ctx.Err()
+			}
+			wg.Wait()
+			return responses, errs
+		}
+
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			responses[i], errs[i] = c.GetWithOptions(ctx, u, o.session, o.request...)
+		}(i, u)
+	}
+	wg.Wait()
+
+	return responses, errs
+}
@@ -0,0 +1,53 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func Test_client_GetBatch(t *testing.T) {
+	var mu sync.Mutex
+	var running, maxRunning int
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+
+		if strings.HasSuffix(cmd.URL, "/timeout") {
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: maximum timeout reached"}
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+
+	urls := []string{"https://example.com/1", "https://example.com/timeout", "https://example.com/3", "https://example.com/4"}
+	responses, errs := c.GetBatch(context.Background(), urls, WithBatchConcurrency(2))
+
+	for i, u := range urls {
+		if u == "https://example.com/timeout" {
+			if !errors.Is(errs[i], ErrRequestTimeout) || responses[i] != nil {
+				t.Errorf("GetBatch()[%d] = %v, %v, want error %v", i, responses[i], errs[i], ErrRequestTimeout)
+			}
+			continue
+		}
+
+		if errs[i] != nil || responses[i].Solution.URL != u {
+			t.Errorf("GetBatch()[%d] = %v, %v, want %s", i, responses[i], errs[i], u)
+		}
+	}
+
+	if maxRunning > 2 {
+		t.Errorf("GetBatch() ran %d requests at the same time, want at most 2", maxRunning)
+	}
+}
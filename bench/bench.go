@@ -0,0 +1,152 @@
+// Package bench measures the throughput and latency of a FlareSolverr client under concurrency.
+//
+// Run it against a flaresolverrtest server to measure the client overhead alone,
+// or against a live FlareSolverr to measure the whole stack:
+//
+//	srv := flaresolverrtest.NewServer(flaresolverrtest.WithLatency(10 * time.Millisecond))
+//	defer srv.Close()
+//
+//	c := flaresolverr.New(srv.Endpoint(), time.Minute, srv.Client())
+//	report, err := bench.Run(ctx, c, bench.Config{Concurrency: 32, Requests: 10000})
+//	fmt.Println(report)
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidConfig when neither Config.Requests nor Config.Duration is set.
+var ErrInvalidConfig = errors.New("invalid config: requests or duration is required")
+
+// Config configures a load test.
+type Config struct {
+	// URL is the URL requested, https://example.com by default.
+	URL string
+
+	// Concurrency is the number of requests running at the same time, 1 by default.
+	Concurrency int
+
+	// Requests stops the load test after this many requests.
+	Requests int
+
+	// Duration stops the load test after this long.
+	Duration time.Duration
+
+	// Options are passed to every request.
+	Options []flaresolverr.RequestOption
+}
+
+// Report holds the load test results.
+type Report struct {
+	Requests int
+	Errors   int
+	Elapsed  time.Duration
+
+	// Throughput is the number of requests per second.
+	Throughput float64
+
+	// Latency percentiles of the requests.
+	Min, P50, P90, P99, Max time.Duration
+}
+
+// String formats the report on a single line.
+func (r *Report) String() string {
+	return fmt.Sprintf("%d requests (%d errors) in %s, %.1f req/s, latency min=%s p50=%s p90=%s p99=%s max=%s",
+		r.Requests, r.Errors, r.Elapsed.Round(time.Millisecond), r.Throughput, r.Min, r.P50, r.P90, r.P99, r.Max)
+}
+
+// Run sends Get requests with c until cfg.Requests requests are sent, cfg.Duration elapsed,
+// or ctx is done, whichever comes first, and reports their latency.
+func Run(ctx context.Context, c flaresolverr.Requester, cfg Config) (*Report, error) {
+	if cfg.Requests <= 0 && cfg.Duration <= 0 {
+		return nil, ErrInvalidConfig
+	}
+
+	if cfg.URL == "" {
+		cfg.URL = "https://example.com"
+	}
+
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	var (
+		sent   atomic.Int64
+		errs   atomic.Int64
+		mu     sync.Mutex
+		timing []time.Duration
+		wg     sync.WaitGroup
+	)
+
+	start := time.Now()
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var local []time.Duration
+			for ctx.Err() == nil && (cfg.Requests <= 0 || sent.Add(1) <= int64(cfg.Requests)) {
+				begin := time.Now()
+				_, err := c.GetWithOptions(ctx, cfg.URL, uuid.Nil, cfg.Options...)
+				if err != nil && ctx.Err() != nil {
+					// cut by the end of the load test
+					break
+				}
+
+				local = append(local, time.Since(begin))
+				if err != nil {
+					errs.Add(1)
+				}
+			}
+
+			mu.Lock()
+			timing = append(timing, local...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return newReport(timing, int(errs.Load()), time.Since(start)), nil
+}
+
+func newReport(timing []time.Duration, errs int, elapsed time.Duration) *Report {
+	report := &Report{Requests: len(timing), Errors: errs, Elapsed: elapsed}
+	if len(timing) == 0 {
+		return report
+	}
+
+	sort.Slice(timing, func(i, j int) bool { return timing[i] < timing[j] })
+	report.Throughput = float64(len(timing)) / elapsed.Seconds()
+	report.Min = timing[0]
+	report.P50 = percentile(timing, 50)
+	report.P90 = percentile(timing, 90)
+	report.P99 = percentile(timing, 99)
+	report.Max = timing[len(timing)-1]
+
+	return report
+}
+
+// percentile returns the p-th percentile of the sorted timing, using the nearest rank.
+func percentile(timing []time.Duration, p int) time.Duration {
+	rank := (p*len(timing) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return timing[rank-1]
+}
@@ -0,0 +1,159 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+	"github.com/SkYNewZ/go-flaresolverr/flaresolverrtest"
+	"github.com/google/uuid"
+)
+
+func newClient(tb testing.TB, opts ...flaresolverrtest.Option) (*flaresolverrtest.Server, flaresolverr.Client) {
+	tb.Helper()
+	srv := flaresolverrtest.NewServer(opts...)
+	tb.Cleanup(srv.Close)
+
+	return srv, flaresolverr.New(srv.Endpoint(), time.Minute, srv.Client())
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		handler flaresolverrtest.HandlerFunc
+		want    int
+		errs    int
+		wantErr error
+	}{
+		{
+			name: "requests",
+			cfg:  Config{Concurrency: 4, Requests: 50},
+			want: 50,
+		},
+		{
+			name: "errors",
+			cfg:  Config{Concurrency: 2, Requests: 10},
+			handler: func(cmd *flaresolverrtest.Command) (int, *flaresolverr.Response) {
+				return http.StatusInternalServerError, &flaresolverr.Response{Status: "error", Message: "Error: boom"}
+			},
+			want: 10,
+			errs: 10,
+		},
+		{
+			name:    "invalid config",
+			cfg:     Config{Concurrency: 2},
+			wantErr: ErrInvalidConfig,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []flaresolverrtest.Option
+			if tt.handler != nil {
+				opts = append(opts, flaresolverrtest.WithHandler(tt.handler))
+			}
+			srv, c := newClient(t, opts...)
+
+			report, err := Run(context.Background(), c, tt.cfg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if report.Requests != tt.want || report.Errors != tt.errs {
+				t.Errorf("Run() = %d requests, %d errors, want %d, %d", report.Requests, report.Errors, tt.want, tt.errs)
+			}
+
+			if got := srv.Commands(); got != int64(tt.want) {
+				t.Errorf("server received %d commands, want %d", got, tt.want)
+			}
+
+			if report.Min > report.P50 || report.P50 > report.P99 || report.P99 > report.Max {
+				t.Errorf("Run() latency percentiles are not ordered: %s", report)
+			}
+		})
+	}
+}
+
+func TestRun_duration(t *testing.T) {
+	_, c := newClient(t, flaresolverrtest.WithLatency(5*time.Millisecond))
+
+	report, err := Run(context.Background(), c, Config{Concurrency: 4, Duration: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if report.Requests == 0 || report.Errors != 0 {
+		t.Errorf("Run() = %s, want requests without errors", report)
+	}
+}
+
+func Test_percentile(t *testing.T) {
+	timing := make([]time.Duration, 100)
+	for i := range timing {
+		timing[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	for p, want := range map[int]time.Duration{0: time.Millisecond, 50: 50 * time.Millisecond, 99: 99 * time.Millisecond, 100: 100 * time.Millisecond} {
+		if got := percentile(timing, p); got != want {
+			t.Errorf("percentile(%d) = %s, want %s", p, got, want)
+		}
+	}
+}
+
+// benchmarkGet measures a Get answered with a page of size bytes.
+func benchmarkGet(b *testing.B, size int) {
+	_, c := newClient(b, flaresolverrtest.WithHandler(flaresolverrtest.Page(strings.Repeat("x", size))))
+
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.SetBytes(int64(size))
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := c.Get(ctx, "https://example.com", uuid.Nil); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
+
+func BenchmarkGet_1KB(b *testing.B)  { benchmarkGet(b, 1<<10) }
+func BenchmarkGet_64KB(b *testing.B) { benchmarkGet(b, 64<<10) }
+func BenchmarkGet_1MB(b *testing.B)  { benchmarkGet(b, 1<<20) }
+
+// BenchmarkPost measures the command encoding with a large form.
+func BenchmarkPost(b *testing.B) {
+	_, c := newClient(b)
+	data := "q=" + strings.Repeat("x", 64<<10)
+
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Post(ctx, "https://example.com", uuid.Nil, data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRun reports the load test throughput and latency as benchmark metrics.
+func BenchmarkRun(b *testing.B) {
+	_, c := newClient(b, flaresolverrtest.WithLatency(time.Millisecond))
+
+	b.ResetTimer()
+	report, err := Run(context.Background(), c, Config{Concurrency: 32, Requests: b.N})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportMetric(report.Throughput, "req/s")
+	b.ReportMetric(float64(report.P99.Microseconds()), "p99-µs")
+}
@@ -0,0 +1,39 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func benchmarkGet(b *testing.B, opts ...Option) {
+	page := strings.Repeat("<p>content</p>", 1<<12)
+	srv := newTestServer(b, func(cmd *flaresolverrCommand) (int, *Response) {
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Response: page}}
+	})
+	for _, opt := range opts {
+		opt(srv)
+	}
+
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := srv.Get(ctx, "https://example.com", uuid.Nil); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
+
+func BenchmarkClient_Get(b *testing.B) {
+	benchmarkGet(b)
+}
+
+func BenchmarkClient_Get_codec(b *testing.B) {
+	benchmarkGet(b, WithJSONCodec(stdCodec{}))
+}
@@ -0,0 +1,54 @@
+// Package betav1 is an experimental client for the in-development FlareSolverr APIs.
+//
+// It tracks endpoints and fields of nightly FlareSolverr servers which are not stable yet,
+// and has no compatibility guarantees: anything may change or be removed at any release.
+// The stable commands, session handling and helpers of the flaresolverr package
+// are available unchanged through the embedded flaresolverr.Client.
+package betav1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+)
+
+// ErrCommandFailed when the server answers a beta command with an error status.
+var ErrCommandFailed = errors.New("beta command failed")
+
+// Client is a flaresolverr.Client able to send beta commands.
+type Client struct {
+	flaresolverr.Client
+}
+
+// New creates a beta client. baseURL, timeout, httpClient and opts behave as in flaresolverr.New,
+// and apply to the beta commands too.
+func New(baseURL string, timeout time.Duration, httpClient *http.Client, opts ...flaresolverr.Option) *Client {
+	return &Client{Client: flaresolverr.New(baseURL, timeout, httpClient, opts...)}
+}
+
+// Response is the answer to a beta command.
+// Fields not known by the stable client are kept in Extra.
+type Response struct {
+	flaresolverr.Response
+}
+
+// Do sends a beta command with arbitrary parameters, such as commands or fields
+// only available on nightly servers. cmd does not need to be known by the client,
+// see Command.IsValid. maxTimeout defaults to the client timeout.
+// The command is sent as the stable ones, see flaresolverr.SendCommand.
+func (c *Client) Do(ctx context.Context, cmd flaresolverr.Command, params map[string]any) (*Response, error) {
+	response, err := flaresolverr.SendCommand(ctx, c.Client, cmd, params)
+	if err != nil {
+		var e *flaresolverr.Error
+		if errors.As(err, &e) && e.StatusCode != 0 && e.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%s: %w: %w", cmd, ErrCommandFailed, err)
+		}
+		return nil, err
+	}
+
+	return &Response{Response: *response}, nil
+}
@@ -0,0 +1,84 @@
+package betav1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+)
+
+func TestClient_Do(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		if body["cmd"] != "request.screenshot" {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"status":"error","message":"Request parameter 'cmd' is invalid."}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"status":"ok","message":"","screenshot":"Zm9v","solution":{"url":"` + body["url"].(string) + `"}}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, time.Second, srv.Client())
+
+	tests := []struct {
+		name    string
+		cmd     flaresolverr.Command
+		wantErr error
+	}{
+		{
+			name: "Beta command",
+			cmd:  "request.screenshot",
+		},
+		{
+			name:    "Unknown command",
+			cmd:     "request.foo",
+			wantErr: ErrCommandFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := c.Do(context.Background(), tt.cmd, map[string]any{"url": "https://example.com"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if resp.Status != "ok" || resp.Solution.URL != "https://example.com" {
+				t.Errorf("Do() = %+v", resp.Response)
+			}
+
+			if string(resp.Extra["screenshot"]) != `"Zm9v"` {
+				t.Errorf("Do() extra = %v, want the screenshot field", resp.Extra)
+			}
+		})
+	}
+}
+
+func TestClient_Do_clientOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"status":"ok","message":""}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, time.Second, srv.Client(), flaresolverr.WithBearerToken("token"))
+	if _, err := c.Do(context.Background(), "request.screenshot", nil); err != nil {
+		t.Errorf("Do() error = %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package flaresolverr
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// responseCache is a least recently used cache of responses, expiring after ttl.
+type responseCache struct {
+	ttl        time.Duration
+	maxEntries int
+	clock      Clock
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type cacheEntry struct {
+	key      string
+	response *Response
+	expires  time.Time
+}
+
+func newResponseCache(ttl time.Duration, maxEntries int) *responseCache {
+	return &responseCache{ttl: ttl, maxEntries: maxEntries, clock: systemClock, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// cacheKey identifies the requests returning the same page.
+// The session and proxy are part of it: pages differ between logged in sessions and proxy locations.
+func cacheKey(cmd *flaresolverrCommand, o *requestOptions) string {
+	return strings.Join([]string{cmd.Cmd.String(), cmd.URL, cmd.Session, cmd.Proxy, cmd.PostData, cmd.UserAgent, strconv.Itoa(cmd.WaitInSeconds), cmd.WaitForSelector, strconv.FormatBool(cmd.ReturnScreenshot), strconv.FormatBool(cmd.ReturnBase64), variantKey(cmd, o)}, "\x00")
+}
+
+// variantKey identifies the cookies and extra fields of a request, which change the page returned.
+// The cookie expiry does not.
+func variantKey(cmd *flaresolverrCommand, o *requestOptions) string {
+	if len(cmd.Cookies) == 0 && len(o.extra) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, cookie := range cmd.Cookies {
+		fmt.Fprintf(&b, "%q=%q;%q;%q;", cookie.Name, cookie.Value, cookie.Domain, cookie.Path)
+	}
+
+	// encoding/json sorts the keys, an invalid field fails the command anyway
+	extra, _ := json.Marshal(o.extra)
+	b.Write(extra)
+	return b.String()
+}
+
+// cacheable reports whether the command response can be cached.
+func cacheable(cmd *flaresolverrCommand) bool {
+	return (cmd.Cmd == CommandRequestget || cmd.Cmd == CommandRequestpost) && !cmd.ReturnOnlyCookies
+}
+
+func (c *responseCache) get(key string) *Response {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if c.clock.Now().After(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.response
+}
+
+func (c *responseCache) put(key string, response *Response) {
+	c.putFor(key, response, 0)
+}
+
+// putFor caches response for ttl, or the cache TTL if zero.
+func (c *responseCache) putFor(key string, response *Response, ttl time.Duration) {
+	if ttl == 0 {
+		ttl = c.ttl
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, response: response, expires: c.clock.Now().Add(ttl)}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
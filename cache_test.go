@@ -0,0 +1,165 @@
+package flaresolverr
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_responseCache(t *testing.T) {
+	cache := newResponseCache(50*time.Millisecond, 2)
+	a, b, c := &Response{Message: "a"}, &Response{Message: "b"}, &Response{Message: "c"}
+
+	cache.put("a", a)
+	cache.put("b", b)
+	cache.get("a")
+	cache.put("c", c)
+
+	if got := cache.get("b"); got != nil {
+		t.Errorf("get(b) = %v, want the least recently used entry evicted", got)
+	}
+
+	if got := cache.get("a"); got != a {
+		t.Errorf("get(a) = %v, want %v", got, a)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if got := cache.get("c"); got != nil {
+		t.Errorf("get(c) = %v, want the entry expired", got)
+	}
+}
+
+func Test_client_WithResponseCache(t *testing.T) {
+	var commands atomic.Int32
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		commands.Add(1)
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Response: cmd.PostData}}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithResponseCache(time.Minute, 10))
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := c.Get(ctx, "https://example.com", uuid.Nil); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+
+	resp, err := c.Post(ctx, "https://example.com", uuid.Nil, "foo=bar")
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+
+	if resp.Solution.Response != "foo=bar" {
+		t.Errorf("Post() = %q, want the POST response", resp.Solution.Response)
+	}
+
+	if got := commands.Load(); got != 2 {
+		t.Errorf("sent %d commands, want 2", got)
+	}
+}
+
+func Test_client_WithResponseCache_base64(t *testing.T) {
+	var commands atomic.Int32
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		commands.Add(1)
+		time.Sleep(50 * time.Millisecond)
+
+		response := "plain"
+		if cmd.ReturnBase64 {
+			response = base64.StdEncoding.EncodeToString([]byte("base64"))
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Response: response}}
+	})
+
+	profile := ProfileFlareSolverr
+	profile.Base64Response = true
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithProfile(profile), WithResponseCache(time.Minute, 10), WithSingleflight())
+
+	variants := map[string][]RequestOption{"plain": nil, "base64": {WithBase64Response()}}
+	for i := 0; i < 2; i++ {
+		// the variants are sent at the same time, then answered from the cache
+		var wg sync.WaitGroup
+		for want, opts := range variants {
+			wg.Add(1)
+			go func(want string, opts []RequestOption) {
+				defer wg.Done()
+
+				resp, err := c.GetWithOptions(context.Background(), "https://example.com", uuid.Nil, opts...)
+				if err != nil {
+					t.Errorf("Get() error = %v", err)
+					return
+				}
+				if resp.Solution.Response != want {
+					t.Errorf("Get() body = %q, want %q", resp.Solution.Response, want)
+				}
+			}(want, opts)
+		}
+		wg.Wait()
+	}
+
+	if got := commands.Load(); got != 2 {
+		t.Errorf("sent %d commands, want one per variant", got)
+	}
+}
+
+func Test_client_WithResponseCache_keys(t *testing.T) {
+	var commands atomic.Int32
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		commands.Add(1)
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Response: cmd.Session + " " + cmd.Proxy}}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithResponseCache(time.Minute, 10))
+
+	session := uuid.New()
+	tests := []struct {
+		name    string
+		session uuid.UUID
+		opts    []RequestOption
+		want    string
+	}{
+		{name: "None", want: " "},
+		{name: "Session", session: session, want: session.String() + " "},
+		{name: "Proxy", opts: []RequestOption{WithProxy("http://a:8080")}, want: " http://a:8080"},
+		{name: "Other proxy", opts: []RequestOption{WithProxy("http://b:8080")}, want: " http://b:8080"},
+		{name: "Extra fields", opts: []RequestOption{WithExtraFields(map[string]any{"tabs": 1})}, want: " "},
+		{name: "Other extra fields", opts: []RequestOption{WithExtraFields(map[string]any{"tabs": 2})}, want: " "},
+	}
+	for i := 0; i < 2; i++ {
+		for _, tt := range tests {
+			resp, err := c.GetWithOptions(context.Background(), "https://example.com", tt.session, tt.opts...)
+			if err != nil {
+				t.Fatalf("%s: Get() error = %v", tt.name, err)
+			}
+			if resp.Solution.Response != tt.want {
+				t.Errorf("%s: Get() body = %q, want %q", tt.name, resp.Solution.Response, tt.want)
+			}
+		}
+	}
+
+	if got := commands.Load(); got != int32(len(tests)) {
+		t.Errorf("sent %d commands, want %d", got, len(tests))
+	}
+}
+
+func Test_cacheKey(t *testing.T) {
+	get := func(cookies ...commandCookie) *flaresolverrCommand {
+		return &flaresolverrCommand{Cmd: CommandRequestget, URL: "https://example.com", Cookies: cookies}
+	}
+	o := new(requestOptions)
+
+	if cacheKey(get(), o) == cacheKey(get(commandCookie{Name: "a", Value: "1"}), o) {
+		t.Errorf("cacheKey() ignores the cookies")
+	}
+	if cacheKey(get(commandCookie{Name: "a", Value: "1"}), o) == cacheKey(get(commandCookie{Name: "a", Value: "2"}), o) {
+		t.Errorf("cacheKey() ignores the cookie values")
+	}
+	if cacheKey(get(commandCookie{Name: "a", Value: "1", Expiry: 1}), o) != cacheKey(get(commandCookie{Name: "a", Value: "1", Expiry: 2}), o) {
+		t.Errorf("cacheKey() depends on the cookie expiry")
+	}
+}
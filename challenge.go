@@ -0,0 +1,105 @@
+package flaresolverr
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Vendor is a challenge vendor.
+type Vendor string
+
+const (
+	VendorCloudflare Vendor = "cloudflare"
+	VendorDDoSGuard  Vendor = "ddos-guard"
+)
+
+// vendorOf returns the challenge vendor named in a message, or an empty vendor.
+func vendorOf(message string) Vendor {
+	switch message = strings.ToLower(message); {
+	case strings.Contains(message, "ddos-guard"):
+		return VendorDDoSGuard
+	case strings.Contains(message, "cloudflare"), strings.Contains(message, "turnstile"):
+		return VendorCloudflare
+	default:
+		return ""
+	}
+}
+
+// challengeMarkers are found in the body of challenge pages, by vendor.
+var challengeMarkers = map[Vendor][]string{
+	VendorCloudflare: {
+		"<title>Just a moment...</title>",
+		"<title>Attention Required! | Cloudflare</title>",
+		"cf-browser-verification",
+		"window._cf_chl_opt",
+		"/cdn-cgi/challenge-platform/",
+	},
+	VendorDDoSGuard: {
+		"<title>DDoS-Guard</title>",
+		"check.ddos-guard.net",
+		"/.well-known/ddos-guard/",
+	},
+}
+
+// challengeSniffLen is the number of body bytes looked at for challenge markers.
+const challengeSniffLen = 64 << 10
+
+// IsChallenge reports whether resp is a challenge page, which must be solved through FlareSolverr.
+// The beginning of the body is inspected for challenge markers,
+// resp.Body is replaced to still return the whole body.
+func IsChallenge(resp *http.Response) bool {
+	return ChallengeVendor(resp) != ""
+}
+
+// ChallengeVendor returns the vendor of the challenge page resp, or an empty vendor
+// if resp is not a challenge page. It inspects resp like IsChallenge.
+func ChallengeVendor(resp *http.Response) Vendor {
+	if resp.Header.Get("cf-mitigated") == "challenge" {
+		return VendorCloudflare
+	}
+
+	switch resp.StatusCode {
+	case http.StatusForbidden, http.StatusServiceUnavailable, http.StatusTooManyRequests:
+	default:
+		return ""
+	}
+
+	server := strings.ToLower(resp.Header.Get("Server"))
+	for _, vendor := range []Vendor{VendorCloudflare, VendorDDoSGuard} {
+		if strings.Contains(server, string(vendor)) && bodyContainsAny(resp, challengeMarkers[vendor]) {
+			return vendor
+		}
+	}
+
+	return ""
+}
+
+// bodyContainsAny reports whether the beginning of the response body contains one of markers,
+// and replaces the body by an equivalent one.
+func bodyContainsAny(resp *http.Response, markers []string) bool {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return false
+	}
+
+	head, err := io.ReadAll(io.LimitReader(resp.Body, challengeSniffLen))
+	resp.Body = &replayBody{Reader: io.MultiReader(bytes.NewReader(head), resp.Body), Closer: resp.Body}
+	if err != nil {
+		return false
+	}
+
+	for _, marker := range markers {
+		if bytes.Contains(head, []byte(marker)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// replayBody returns the sniffed bytes before the rest of a body.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,73 @@
+package flaresolverr
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestChallengeVendor(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header http.Header
+		body   string
+		want   Vendor
+	}{
+		{
+			name:   "Managed challenge",
+			status: http.StatusForbidden,
+			header: http.Header{"Cf-Mitigated": {"challenge"}},
+			want:   VendorCloudflare,
+		},
+		{
+			name:   "Challenge page",
+			status: http.StatusServiceUnavailable,
+			header: http.Header{"Server": {"cloudflare"}},
+			body:   "<html><head><title>Just a moment...</title></head></html>",
+			want:   VendorCloudflare,
+		},
+		{
+			name:   "DDoS-Guard challenge page",
+			status: http.StatusForbidden,
+			header: http.Header{"Server": {"ddos-guard"}},
+			body:   "<html><head><title>DDoS-Guard</title></head></html>",
+			want:   VendorDDoSGuard,
+		},
+		{
+			name:   "Cloudflare error page",
+			status: http.StatusServiceUnavailable,
+			header: http.Header{"Server": {"cloudflare"}},
+			body:   "<html><head><title>Service unavailable</title></head></html>",
+			want:   "",
+		},
+		{
+			name:   "Origin forbidden",
+			status: http.StatusForbidden,
+			header: http.Header{"Server": {"nginx"}},
+			body:   "<title>Just a moment...</title>",
+			want:   "",
+		},
+		{
+			name:   "Success",
+			status: http.StatusOK,
+			header: http.Header{"Server": {"cloudflare"}},
+			body:   "<title>Just a moment...</title>",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: tt.header, Body: io.NopCloser(strings.NewReader(tt.body))}
+			if got := ChallengeVendor(resp); got != tt.want {
+				t.Errorf("ChallengeVendor() = %q, want %q", got, tt.want)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.body {
+				t.Errorf("ChallengeVendor() body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
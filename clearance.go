@@ -0,0 +1,132 @@
+package flaresolverr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Clearance holds what is needed to reach a challenge protected website
+// without FlareSolverr: the solved cookies and the user agent they were issued for.
+// Cloudflare rejects clearance cookies used with a different user agent.
+type Clearance struct {
+	// URL is the URL the challenge was solved for.
+	URL       string
+	UserAgent string
+	Cookies   []*http.Cookie
+}
+
+// Clearance returns the solved cookies and user agent.
+func (s *ResponseSolution) Clearance() *Clearance {
+	return &Clearance{
+		URL:       s.URL,
+		UserAgent: s.UserAgent,
+		Cookies:   s.HTTPCookies(),
+	}
+}
+
+// solveClearance solves the challenge protecting u, without retrieving the page content.
+func (c *client) solveClearance(ctx context.Context, u string, o *requestOptions) (*Clearance, error) {
+	if c.flight == nil {
+		return c.clearance(ctx, u, o)
+	}
+
+	return share(ctx, c.flight, clearanceKey(u, o), func(ctx context.Context) (*Clearance, error) {
+		return c.clearance(ctx, u, o)
+	})
+}
+
+func (c *client) clearance(ctx context.Context, u string, o *requestOptions) (*Clearance, error) {
+	cmd := &flaresolverrCommand{
+		Cmd:               CommandRequestget,
+		URL:               u,
+		Session:           o.sessionFor(uuid.Nil),
+		ReturnOnlyCookies: true,
+		Proxy:             o.proxy,
+	}
+
+	response, err := c.do(ctx, cmd, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Solution == nil {
+		return &Clearance{URL: u}, nil
+	}
+
+	return response.Solution.Clearance(), nil
+}
+
+// NewRequest builds a request pre-populated with the solved cookies and user agent.
+func (s *ResponseSolution) NewRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	return s.Clearance().NewRequest(ctx, method, url, body)
+}
+
+// NewRequest builds a request pre-populated with the clearance cookies and user agent.
+func (c *Clearance) NewRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Apply(req)
+	return req, nil
+}
+
+// Apply sets the clearance user agent on req, and adds the clearance cookies
+// matching its host which are not already set.
+func (c *Clearance) Apply(req *http.Request) {
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	for _, cookie := range c.Cookies {
+		if !cookieMatchesHost(cookie, req.URL.Hostname()) {
+			continue
+		}
+
+		if _, err := req.Cookie(cookie.Name); err == nil {
+			continue
+		}
+
+		req.AddCookie(cookie)
+	}
+}
+
+// Transport returns an http.RoundTripper applying the clearance to every request.
+// Uses http.DefaultTransport if base is nil.
+func (c *Clearance) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &clearanceTransport{clearance: c, base: base}
+}
+
+type clearanceTransport struct {
+	clearance *Clearance
+	base      http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *clearanceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the request
+	req = req.Clone(req.Context())
+	t.clearance.Apply(req)
+	return t.base.RoundTrip(req)
+}
+
+// cookieMatchesHost reports whether the cookie domain matches host.
+// Cookies without domain match every host.
+func cookieMatchesHost(cookie *http.Cookie, host string) bool {
+	domain := strings.ToLower(strings.TrimPrefix(cookie.Domain, "."))
+	if domain == "" {
+		return true
+	}
+
+	host = strings.ToLower(host)
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
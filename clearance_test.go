@@ -0,0 +1,102 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClearance_Apply(t *testing.T) {
+	clearance := &Clearance{
+		UserAgent: "foo",
+		Cookies: []*http.Cookie{
+			{Name: "cf_clearance", Value: "bar", Domain: ".example.com"},
+			{Name: "other", Value: "baz", Domain: "other.com"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		cookies     []*http.Cookie
+		wantCookies string
+	}{
+		{
+			name:        "Matching domain",
+			url:         "https://example.com/",
+			wantCookies: "cf_clearance=bar",
+		},
+		{
+			name:        "Matching subdomain",
+			url:         "https://www.example.com/",
+			wantCookies: "cf_clearance=bar",
+		},
+		{
+			name:        "Other domain",
+			url:         "https://notexample.com/",
+			wantCookies: "",
+		},
+		{
+			name:        "Existing cookie is kept",
+			url:         "https://example.com/",
+			cookies:     []*http.Cookie{{Name: "cf_clearance", Value: "qux"}},
+			wantCookies: "cf_clearance=qux",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, http.NoBody)
+			req.Header.Set("User-Agent", "Go-http-client/1.1")
+			for _, cookie := range tt.cookies {
+				req.AddCookie(cookie)
+			}
+
+			clearance.Apply(req)
+			if got := req.UserAgent(); got != "foo" {
+				t.Errorf("Apply() user agent = %v, want %v", got, "foo")
+			}
+
+			if got := req.Header.Get("Cookie"); got != tt.wantCookies {
+				t.Errorf("Apply() cookies = %v, want %v", got, tt.wantCookies)
+			}
+		})
+	}
+}
+
+func TestClearance_Transport(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, _ := r.Cookie("cf_clearance")
+		got = append(got, r.UserAgent(), cookie.String())
+	}))
+	defer srv.Close()
+
+	solution := &ResponseSolution{
+		URL:       srv.URL,
+		UserAgent: "foo",
+		Cookies:   []Cookie{{Name: "cf_clearance", Value: "bar", Session: true}},
+	}
+	httpClient := &http.Client{Transport: solution.Clearance().Transport(nil)}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if diff := cmp.Diff([]string{"foo", "cf_clearance=bar"}, got); diff != "" {
+		t.Errorf("Transport() mismatch (-want +got):\n%s", diff)
+	}
+
+	if req.Header.Get("Cookie") != "" {
+		t.Errorf("Transport() modified the original request")
+	}
+}
@@ -1,24 +1,53 @@
 package flaresolverr
 
-//go:generate go run github.com/vburenin/ifacemaker@v1.2.0 --file=client.go --struct=client --iface=Client --pkg=flaresolverr -y "Client interface describes wrapped Flaresolverr client." --doc=true --output=client.gen.go
-
 import (
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"runtime/trace"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
 )
 
 var (
 	// ErrRequestTimeout when timeout reached before flaresolverr can answer.
 	ErrRequestTimeout = errors.New("maximum timeout reached")
 
+	// ErrCaptchaDetected when the challenge requires a captcha FlareSolverr cannot solve.
+	ErrCaptchaDetected = errors.New("captcha detected")
+
+	// ErrCustomCaptchaDetected when the challenge is a captcha requiring a custom captcha solver,
+	// which is not configured on the FlareSolverr server.
+	ErrCustomCaptchaDetected = errors.New("custom captcha detected")
+
+	// ErrDDoSGuardDetected when a DDoS-Guard challenge could not be solved.
+	ErrDDoSGuardDetected = errors.New("DDoS-Guard challenge detected")
+
+	// ErrAccessDenied when the target blocked the browser, e.g. because its IP address is banned.
+	ErrAccessDenied = errors.New("access denied")
+
+	// ErrSessionNotFound when the session does not exist on the FlareSolverr server,
+	// e.g. because it has been restarted.
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrInvalidBaseURL when the FlareSolverr endpoint is not a valid HTTP URL.
+	ErrInvalidBaseURL = errors.New("invalid base URL")
+
+	// ErrInvalidServerResponse when the server answered with something else than JSON,
+	// such as the HTML error page of a reverse proxy.
+	ErrInvalidServerResponse = errors.New("invalid FlareSolverr response")
+
 	// ErrUnexpectedError .
 	ErrUnexpectedError = errors.New("unexpected error from FlareSolverr server")
 )
@@ -27,11 +56,75 @@ type client struct {
 	baseURL    string
 	httpClient *http.Client
 	timeout    time.Duration
+	sessions   sessionRegistry
+	stats      clientStats
+	logger     *slog.Logger
+	header     http.Header
+	retry      RetryPolicy
+
+	// timeoutPadding is added to timeout to wait for the FlareSolverr answer.
+	timeoutPadding time.Duration
+
+	spillThreshold int64
+	spillDir       string
+
+	errorSuppressor *suppressor
+	rawResponses    bool
+
+	downloadLimiter *rate.Limiter
+	downloadSlots   chan struct{}
+
+	politeness *politeness
+	flight     *singleflight.Group
+	cache      *responseCache
+	clearances *clearanceCache
+
+	cancelInFlight   bool
+	recreateSessions bool
+	compression      *compression
+
+	profile        *ServerProfile
+	keepAlive      *keepAlive
+	restarts       *restartDetector
+	hooks          *Hooks
+	codec          JSONCodec
+	domainSessions *domainSessions
+	inFlight       *inFlightGuard
+	adaptive       *adaptiveTimeout
+	strictDecoding bool
+	auditLog       *auditLog
+	redaction      RedactionPolicy
+
+	// rateLimitBudget is the total time waited for rate limited commands, see WithRateLimitRetry.
+	rateLimitBudget time.Duration
+
+	// failOnHTTPError converts the 4xx and 5xx target statuses to errors, see FailOnHTTPError.
+	failOnHTTPError bool
+
+	// challengeRetries is the number of retries with a fresh session, see WithChallengeRetry.
+	challengeRetries int
+
+	// proxies rotates the requests over proxies, see WithProxyRotation.
+	proxies *proxyRotation
+
+	// clock tells the time to the client and its subsystems, see WithClock.
+	clock Clock
+
+	// domains configures the requests to some domains, see WithDomainConfig.
+	domains map[string]DomainConfig
+
+	// uuidSource generates the IDs of the sessions created by the client, see WithUUIDSource.
+	uuidSource func() uuid.UUID
+
+	// apiClient reaches FlareSolverr when it cannot be reached with httpClient.
+	apiClient *http.Client
 }
 
 // New creates a Flaresolverr client.
 // Uses the default http client if not provided.
-func New(baseURL string, timeout time.Duration, httpClient *http.Client) Client {
+// The client is safe for concurrent use, share a single one rather than creating one per goroutine:
+// the sessions, caches and limits it keeps are per client.
+func New(baseURL string, timeout time.Duration, httpClient *http.Client, opts ...Option) Client {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
@@ -41,18 +134,102 @@ func New(baseURL string, timeout time.Duration, httpClient *http.Client) Client
 		timeout = time.Millisecond * 60000
 	}
 
-	return &client{baseURL: baseURL, httpClient: httpClient, timeout: timeout}
+	c := &client{baseURL: baseURL, httpClient: httpClient, timeout: timeout, timeoutPadding: 10 * time.Second, clock: systemClock}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.useDomainConfigs()
+	c.useClock()
+
+	return c
+}
+
+// NewWithValidation is like New, but checks baseURL is an HTTP URL and normalizes it:
+// the /v1 API path is appended when missing, e.g. "http://127.0.0.1:8191" becomes "http://127.0.0.1:8191/v1".
+// Errors wrap ErrInvalidBaseURL.
+func NewWithValidation(baseURL string, timeout time.Duration, httpClient *http.Client, opts ...Option) (Client, error) {
+	endpoint, err := normalizeBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(endpoint, timeout, httpClient, opts...), nil
+}
+
+func normalizeBaseURL(baseURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(baseURL))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidBaseURL, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%w: %q: scheme must be http or https", ErrInvalidBaseURL, baseURL)
+	}
+
+	if u.Host == "" {
+		return "", fmt.Errorf("%w: %q: missing host", ErrInvalidBaseURL, baseURL)
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	if !strings.HasSuffix(u.Path, "/v1") {
+		u.Path += "/v1"
+	}
+	u.RawPath = ""
+
+	return u.String(), nil
 }
 
 type Response struct {
-	Status         string            `json:"status"`
+	Status         Status            `json:"status"`
 	Message        string            `json:"message"`
 	StartTimestamp int64             `json:"startTimestamp"`
 	EndTimestamp   int64             `json:"endTimestamp"`
 	Version        string            `json:"version"`
 	Session        string            `json:"session"`
-	Sessions       []uuid.UUID       `json:"sessions"`
+	Sessions       SessionList       `json:"sessions"`
 	Solution       *ResponseSolution `json:"solution"`
+
+	// OtherSessions are the sessions returned by ListSessions whose ID is not a UUID,
+	// which Sessions cannot hold. See SessionIDs for every session.
+	OtherSessions []SessionID `json:"-"`
+
+	// Raw is the response body as returned by FlareSolverr, when WithRawResponses is used.
+	Raw json.RawMessage `json:"-"`
+
+	// Extra holds the top-level fields not modeled by Response, such as fields added
+	// by newer servers or forks, see WithStrictDecoding to reject them instead.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// StartTime returns when FlareSolverr started processing the command.
+func (r *Response) StartTime() time.Time {
+	return time.UnixMilli(r.StartTimestamp)
+}
+
+// EndTime returns when FlareSolverr finished processing the command.
+func (r *Response) EndTime() time.Time {
+	return time.UnixMilli(r.EndTimestamp)
+}
+
+// Duration returns how long FlareSolverr took to process the command.
+func (r *Response) Duration() time.Duration {
+	return time.Duration(r.EndTimestamp-r.StartTimestamp) * time.Millisecond
+}
+
+// ChallengeSolved reports whether FlareSolverr had to solve a challenge,
+// as opposed to the page not being protected.
+func (r *Response) ChallengeSolved() bool {
+	return strings.Contains(strings.ToLower(r.Message), "challenge solved")
+}
+
+// SolveDuration returns how long FlareSolverr took to solve the challenge,
+// or zero if there was none to solve.
+func (r *Response) SolveDuration() time.Duration {
+	if !r.ChallengeSolved() {
+		return 0
+	}
+
+	return r.Duration()
 }
 
 type ResponseSolution struct {
@@ -75,32 +252,182 @@ type ResponseSolution struct {
 		CfRay               string `json:"cf-ray"`
 		ContentEncoding     string `json:"content-encoding"`
 		AltSvc              string `json:"alt-svc"`
+		RetryAfter          string `json:"retry-after"`
 	} `json:"headers"`
-	Response string `json:"response"`
-	Cookies  []struct {
-		Name     string  `json:"name"`
-		Value    string  `json:"value"`
-		Domain   string  `json:"domain"`
-		Path     string  `json:"path"`
-		Expires  float64 `json:"expires"`
-		Size     int     `json:"size"`
-		HTTPOnly bool    `json:"httpOnly"`
-		Secure   bool    `json:"secure"`
-		Session  bool    `json:"session"`
-		SameSite string  `json:"sameSite,omitempty"`
-	} `json:"cookies"`
-	UserAgent string `json:"userAgent"`
+	Response  string   `json:"response"`
+	Cookies   []Cookie `json:"cookies"`
+	UserAgent string   `json:"userAgent"`
+
+	// ScreenshotBase64 is the base64 PNG screenshot of the page, see WithScreenshot and Screenshot.
+	ScreenshotBase64 string `json:"screenshot,omitempty"`
+
+	document document
+	spilled  *spilledBody
+	clock    Clock // of the client which received the solution, see RetryAfter
+}
+
+// Cookie is a cookie set by the target website during the challenge resolution.
+type Cookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  float64 `json:"expires"`
+	Size     int     `json:"size"`
+	HTTPOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+	Session  bool    `json:"session"`
+	SameSite string  `json:"sameSite,omitempty"`
+
+	// Expiry is the expiration in seconds reported by FlareSolverr v3 instead of Expires.
+	Expiry int64 `json:"expiry,omitempty"`
+}
+
+// ExpiresAt returns when the cookie expires, or the zero time for session cookies.
+// It reads Expires, or Expiry for the cookies returned by FlareSolverr v3.
+func (c Cookie) ExpiresAt() time.Time {
+	switch {
+	// session cookies are reported with a negative expiration
+	case c.Session:
+		return time.Time{}
+	case c.Expires > 0:
+		return time.Unix(0, int64(c.Expires*float64(time.Second)))
+	case c.Expiry > 0:
+		return time.Unix(c.Expiry, 0)
+	default:
+		return time.Time{}
+	}
+}
+
+// IsExpired reports whether the cookie is expired at now. Session cookies never expire.
+func (c Cookie) IsExpired(now time.Time) bool {
+	expires := c.ExpiresAt()
+	return !expires.IsZero() && !now.Before(expires)
+}
+
+// HTTPCookie converts the cookie to be used with net/http.
+func (c Cookie) HTTPCookie() *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		HttpOnly: c.HTTPOnly,
+		Secure:   c.Secure,
+	}
+
+	cookie.Expires = c.ExpiresAt()
+
+	switch strings.ToLower(c.SameSite) {
+	case "strict":
+		cookie.SameSite = http.SameSiteStrictMode
+	case "lax":
+		cookie.SameSite = http.SameSiteLaxMode
+	case "none":
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+
+	return cookie
+}
+
+// HTTPCookies returns the solution cookies to be used with net/http.
+func (s *ResponseSolution) HTTPCookies() []*http.Cookie {
+	cookies := make([]*http.Cookie, 0, len(s.Cookies))
+	for _, cookie := range s.Cookies {
+		cookies = append(cookies, cookie.HTTPCookie())
+	}
+
+	return cookies
 }
 
 type flaresolverrCommand struct {
-	Cmd               command `json:"cmd"`
-	URL               string  `json:"url"`
-	Session           string  `json:"session,omitempty"`
-	MaxTimeout        int     `json:"maxTimeout"`
-	Cookies           []any   `json:"cookies,omitempty"`
-	ReturnOnlyCookies bool    `json:"returnOnlyCookies,omitempty"`
-	Proxy             string  `json:"proxy,omitempty"`
-	PostData          string  `json:"postData,omitempty"`
+	Cmd               Command         `json:"cmd"`
+	URL               string          `json:"url"`
+	Session           string          `json:"session,omitempty"`
+	MaxTimeout        int             `json:"maxTimeout"`
+	Cookies           []commandCookie `json:"cookies,omitempty"`
+	ReturnOnlyCookies bool            `json:"returnOnlyCookies,omitempty"`
+	Proxy             string          `json:"proxy,omitempty"`
+	PostData          string          `json:"postData,omitempty"`
+	UserAgent         string          `json:"userAgent,omitempty"`
+	WaitInSeconds     int             `json:"waitInSeconds,omitempty"`
+	WaitForSelector   string          `json:"waitForSelector,omitempty"`
+	ReturnScreenshot  bool            `json:"returnScreenshot,omitempty"`
+	ReturnBase64      bool            `json:"returnBase64,omitempty"`
+
+	// extra holds fields the client does not model, see WithExtraFields.
+	extra map[string]any
+}
+
+// MarshalJSON merges the extra fields into the command.
+func (c *flaresolverrCommand) MarshalJSON() ([]byte, error) {
+	type plain flaresolverrCommand
+	data, err := json.Marshal((*plain)(c))
+	if err != nil || len(c.extra) == 0 {
+		return data, err
+	}
+
+	fields := make(map[string]any, len(c.extra))
+	for key, value := range c.extra {
+		fields[key] = value
+	}
+
+	// the modeled fields win over the extra ones
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(fields)
+}
+
+// commandCookie is a cookie set in the browser, in the WebDriver format.
+type commandCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Domain   string `json:"domain,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Expiry   int64  `json:"expiry,omitempty"`
+	SameSite string `json:"sameSite,omitempty"`
+}
+
+func newCommandCookies(cookies []*http.Cookie, now time.Time) []commandCookie {
+	if len(cookies) == 0 {
+		return nil
+	}
+
+	result := make([]commandCookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		c := commandCookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HttpOnly,
+		}
+
+		switch {
+		case cookie.MaxAge > 0:
+			c.Expiry = now.Add(time.Duration(cookie.MaxAge) * time.Second).Unix()
+		case !cookie.Expires.IsZero():
+			c.Expiry = cookie.Expires.Unix()
+		}
+
+		switch cookie.SameSite {
+		case http.SameSiteLaxMode:
+			c.SameSite = "Lax"
+		case http.SameSiteStrictMode:
+			c.SameSite = "Strict"
+		case http.SameSiteNoneMode:
+			c.SameSite = "None"
+		}
+
+		result = append(result, c)
+	}
+
+	return result
 }
 
 // CreateSession launch a new browser instance
@@ -111,16 +438,44 @@ type flaresolverrCommand struct {
 //
 // This also speeds up the requests since it won't have to launch a new browser instance for every request.
 func (c *client) CreateSession(ctx context.Context, session uuid.UUID, proxy ...string) (*Response, error) {
+	return c.CreateSessionWithOptions(ctx, session, proxyOptions(proxy)...)
+}
+
+// CreateSessionWithOptions is like CreateSession, with request options.
+// WithProxy sets the proxy used by the whole session, WithCookies seeds its browser with cookies.
+func (c *client) CreateSessionWithOptions(ctx context.Context, session uuid.UUID, opts ...RequestOption) (*Response, error) {
+	o := newRequestOptions(ctx, opts)
 	cmd := &flaresolverrCommand{
 		Cmd:     CommandSessionscreate,
-		Session: handleSession(session),
+		Session: o.sessionIDFor(session),
+		Proxy:   o.proxy,
+		Cookies: newCommandCookies(o.cookies, c.clock.Now()),
+	}
+
+	return c.do(ctx, cmd, o)
+}
+
+// CreateSessionAuto launches a browser instance within a session whose ID is generated by FlareSolverr,
+// and returns that ID. WithSession and WithSessionID are ignored.
+func (c *client) CreateSessionAuto(ctx context.Context, opts ...RequestOption) (SessionID, error) {
+	o := newRequestOptions(ctx, opts)
+	cmd := &flaresolverrCommand{
+		Cmd:     CommandSessionscreate,
+		Proxy:   o.proxy,
+		Cookies: newCommandCookies(o.cookies, c.clock.Now()),
+	}
+
+	response, err := c.do(ctx, cmd, o)
+	if err != nil {
+		return "", err
 	}
 
-	if len(proxy) > 0 {
-		cmd.Proxy = proxy[0]
+	id, err := ParseSessionID(response.Session)
+	if err != nil {
+		return "", fmt.Errorf("%w: server assigned session: %v", ErrInvalidServerResponse, err)
 	}
 
-	return c.do(ctx, cmd)
+	return id, nil
 }
 
 // ListSessions Returns a list of all the active sessions.
@@ -128,100 +483,494 @@ func (c *client) CreateSession(ctx context.Context, session uuid.UUID, proxy ...
 // You should always make sure to properly close each session
 // when you are done using them as too many may slow your computer down.
 func (c *client) ListSessions(ctx context.Context) (*Response, error) {
+	return c.ListSessionsWithOptions(ctx)
+}
+
+// ListSessionsWithOptions is like ListSessions, with request options.
+func (c *client) ListSessionsWithOptions(ctx context.Context, opts ...RequestOption) (*Response, error) {
 	cmd := &flaresolverrCommand{Cmd: CommandSessionslist}
-	return c.do(ctx, cmd)
+	return c.do(ctx, cmd, newRequestOptions(ctx, opts))
 }
 
 // DestroySession will properly shut down a browser instance
 // and remove all files associated with it to free up resources for a new session.
 // When you no longer need to use a session you should make sure to close it.
 func (c *client) DestroySession(ctx context.Context, session uuid.UUID) error {
+	return c.DestroySessionWithOptions(ctx, session)
+}
+
+// DestroySessionWithOptions is like DestroySession, with request options.
+func (c *client) DestroySessionWithOptions(ctx context.Context, session uuid.UUID, opts ...RequestOption) error {
+	o := newRequestOptions(ctx, opts)
 	cmd := &flaresolverrCommand{
 		Cmd:     CommandSessionsdestroy,
-		Session: handleSession(session),
+		Session: o.sessionIDFor(session),
 	}
-	_, err := c.do(ctx, cmd)
+	_, err := c.do(ctx, cmd, o)
 	return err
 }
 
 // Get makes an HTTP GET request using flaresolverr proxy
 // Session can be nil.
 func (c *client) Get(ctx context.Context, u string, session uuid.UUID, proxy ...string) (*Response, error) {
+	return c.GetWithOptions(ctx, u, session, proxyOptions(proxy)...)
+}
+
+// GetWithOptions is like Get, with request options.
+func (c *client) GetWithOptions(ctx context.Context, u string, session uuid.UUID, opts ...RequestOption) (*Response, error) {
+	o := c.requestOptionsFor(ctx, u, opts)
 	cmd := &flaresolverrCommand{
 		Cmd:               CommandRequestget,
 		URL:               u,
-		Session:           handleSession(session),
+		Session:           o.sessionFor(session),
 		Cookies:           nil, // TODO: handle cookies
-		ReturnOnlyCookies: false,
+		ReturnOnlyCookies: o.onlyCookies,
+		Proxy:             o.proxy,
+		UserAgent:         o.userAgent,
+		WaitInSeconds:     o.waitInSeconds(),
+		WaitForSelector:   o.waitForSelector,
+		ReturnScreenshot:  o.screenshot,
+		ReturnBase64:      o.base64,
 	}
 
-	if len(proxy) > 0 {
-		cmd.Proxy = proxy[0]
-	}
-
-	return c.do(ctx, cmd)
+	return c.follow(ctx, cmd, o)
 }
 
 // Post makes an HTTP POST request using flaresolverr proxy
 // data must be an application/x-www-form-urlencoded string.
 func (c *client) Post(ctx context.Context, u string, session uuid.UUID, data string, proxy ...string) (*Response, error) {
+	return c.PostWithOptions(ctx, u, session, data, proxyOptions(proxy)...)
+}
+
+// PostWithOptions is like Post, with request options.
+func (c *client) PostWithOptions(ctx context.Context, u string, session uuid.UUID, data string, opts ...RequestOption) (*Response, error) {
+	o := c.requestOptionsFor(ctx, u, opts)
 	cmd := &flaresolverrCommand{
 		Cmd:               CommandRequestpost,
 		URL:               u,
-		Session:           handleSession(session),
+		Session:           o.sessionFor(session),
 		Cookies:           nil, // TODO: handle cookies
-		ReturnOnlyCookies: false,
+		ReturnOnlyCookies: o.onlyCookies,
 		PostData:          data,
+		Proxy:             o.proxy,
+		UserAgent:         o.userAgent,
+		WaitInSeconds:     o.waitInSeconds(),
+		WaitForSelector:   o.waitForSelector,
+		ReturnScreenshot:  o.screenshot,
+		ReturnBase64:      o.base64,
+	}
+
+	return c.follow(ctx, cmd, o)
+}
+
+// proxyOptions returns the request options of the proxy argument of CreateSession, Get and Post:
+// only its first value is used.
+func proxyOptions(proxy []string) []RequestOption {
+	if len(proxy) == 0 {
+		return nil
+	}
+
+	return []RequestOption{WithProxy(proxy[0])}
+}
+
+func (c *client) do(ctx context.Context, cmd *flaresolverrCommand, o *requestOptions) (*Response, error) {
+	if err := c.profile.adapt(cmd); err != nil {
+		return nil, c.commandError(cmd, o, 1, 1, err)
+	}
+
+	if cmd.Proxy != "" {
+		proxy, err := NormalizeProxy(cmd.Proxy)
+		if err != nil {
+			return nil, c.commandError(cmd, o, 1, 1, err)
+		}
+		cmd.Proxy = proxy
+	}
+
+	if cmd.Session != "" {
+		if _, err := ParseSessionID(cmd.Session); err != nil {
+			return nil, c.commandError(cmd, o, 1, 1, err)
+		}
+	}
+
+	if err := c.sessions.checkProxy(cmd); err != nil {
+		return nil, c.commandError(cmd, o, 1, 1, err)
+	}
+
+	if c.cache == nil || !cacheable(cmd) || o.cacheTTL < 0 {
+		return c.share(ctx, cmd, o)
+	}
+
+	key := cacheKey(cmd, o)
+	response := c.cache.get(key)
+	c.stats.cacheLookup(response != nil)
+	if response != nil {
+		c.log(ctx, slog.LevelDebug, "flaresolverr response served from cache", slog.String("cmd", cmd.Cmd.String()), slog.String("url", cmd.URL))
+		return response, nil
+	}
+
+	if c.clearances != nil && revalidable(cmd) {
+		if clearance := c.clearances.get(cmd.URL); clearance != nil {
+			response, err := c.fetchDirect(ctx, cmd, clearance)
+			if err == nil {
+				c.cache.putFor(key, response, o.cacheTTL)
+				return response, nil
+			}
+
+			c.log(ctx, slog.LevelDebug, "direct request failed, solving through flaresolverr", slog.String("url", cmd.URL), slog.Any("error", err))
+		}
+	}
+
+	response, err := c.share(ctx, cmd, o)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.putFor(key, response, o.cacheTTL)
+	if c.clearances != nil && response.Solution != nil {
+		c.clearances.put(cmd.URL, response.Solution.Clearance())
+	}
+
+	return response, nil
+}
+
+func (c *client) share(ctx context.Context, cmd *flaresolverrCommand, o *requestOptions) (*Response, error) {
+	if c.flight == nil || (cmd.Cmd != CommandRequestget && cmd.Cmd != CommandRequestpost) {
+		return c.run(ctx, cmd, o)
 	}
 
-	if len(proxy) > 0 {
-		cmd.Proxy = proxy[0]
+	return share(ctx, c.flight, flightKey(cmd, o), func(ctx context.Context) (*Response, error) {
+		return c.run(ctx, cmd, o)
+	})
+}
+
+func (c *client) run(ctx context.Context, cmd *flaresolverrCommand, o *requestOptions) (*Response, error) {
+	if c.challengeRetries > 0 && cacheable(cmd) {
+		return c.runRetryingChallenge(ctx, cmd, o)
+	}
+
+	return c.route(ctx, cmd, o)
+}
+
+func (c *client) route(ctx context.Context, cmd *flaresolverrCommand, o *requestOptions) (*Response, error) {
+	switch {
+	case c.routedToSite(cmd, o):
+		return c.runInDomainSession(ctx, cmd, o)
+	case c.cancelInFlight && abandonable(cmd) && (c.profile == nil || !c.profile.NoSessions):
+		return c.runInTemporarySession(ctx, cmd, o)
+	case c.recreateSessions && recreatable(cmd):
+		return c.runRecreatingSession(ctx, cmd, o)
+	default:
+		return c.exec(ctx, cmd, o)
+	}
+}
+
+func (c *client) exec(ctx context.Context, cmd *flaresolverrCommand, o *requestOptions) (response *Response, err error) {
+	ctx, task := trace.NewTask(ctx, "flaresolverr."+cmd.Cmd.String())
+	defer task.End()
+
+	attempts := 1
+	start := c.clock.Now()
+	defer func() {
+		elapsed := c.clock.Now().Sub(start)
+		c.stats.observe(cmd.Cmd, elapsed, err)
+		if c.auditLog != nil {
+			c.audit(ctx, newAuditRecord(cmd, o, start, elapsed, attempts, err))
+		}
+	}()
+
+	attrs := []slog.Attr{slog.String("cmd", cmd.Cmd.String())}
+	if cmd.URL != "" {
+		attrs = append(attrs, slog.String("url", cmd.URL))
+	}
+	if cmd.Session != "" {
+		attrs = append(attrs, slog.String("session", cmd.Session))
+	}
+	if o.requestID != "" {
+		attrs = append(attrs, slog.String("request_id", o.requestID))
+		trace.Log(ctx, "request_id", o.requestID)
+	}
+	attrs = append(attrs, c.redaction.attrs(cmd)...)
+
+	defer c.sessions.begin(cmd)()
+
+	policy := c.retry.forCommand(cmd.Cmd)
+	maxAttempts := policy.attempts()
+	rotate := c.proxies != nil && rotated(cmd)
+	var rateLimitWaited time.Duration
+	for {
+		err = policy.retry(ctx, c.clock, func(attempt int) error {
+			attempts = attempt
+			attemptAttrs := append(slices.Clip(attrs), slog.Int("attempt", attempt))
+			c.log(ctx, slog.LevelDebug, "sending command to flaresolverr", attemptAttrs...)
+
+			if c.politeness != nil && cmd.URL != "" {
+				if err := c.politeness.wait(ctx, cmd.URL); err != nil {
+					return err
+				}
+			}
+
+			if rotate {
+				proxy, err := c.proxies.pick()
+				if err != nil {
+					return c.commandError(cmd, o, attempt, maxAttempts, err)
+				}
+				cmd.Proxy = proxy
+			}
+
+			// the slot is only held while FlareSolverr works on the command,
+			// not while waiting for the site, a backoff or a rate limit
+			if c.inFlight != nil {
+				if err := c.inFlight.acquire(ctx, o.priority); err != nil {
+					return c.commandError(cmd, o, attempt, maxAttempts, err)
+				}
+			}
+
+			event := newHookEvent(cmd, o, attempt)
+			c.hooks.request(ctx, event)
+			start := c.clock.Now()
+
+			var err error
+			response, err = c.send(ctx, cmd, o)
+			event.Duration = c.clock.Now().Sub(start)
+			if c.inFlight != nil {
+				c.inFlight.release()
+			}
+			if rotate && ctx.Err() == nil {
+				if score, ok := c.proxies.observe(cmd.Proxy, err); ok {
+					c.hooks.proxyScored(ctx, score)
+				}
+			}
+			if c.adaptive != nil && cmd.URL != "" {
+				switch {
+				case err == nil:
+					c.adaptive.observe(cmd.URL, event.Duration)
+				case errors.Is(err, ErrRequestTimeout):
+					// the timeout was too short, make room for slower solves
+					c.adaptive.observe(cmd.URL, time.Duration(cmd.MaxTimeout)*time.Millisecond+adaptiveMargin)
+				}
+			}
+			if err != nil {
+				e := c.commandError(cmd, o, attempt, maxAttempts, err)
+				c.hooks.failed(ctx, event, e)
+				if c.errorSuppressor == nil || c.errorSuppressor.allow(suppressionKey(e)) {
+					c.log(ctx, slog.LevelWarn, "flaresolverr command failed", append(attemptAttrs, slog.String("error", e.message()))...)
+				}
+				return e
+			}
+
+			c.log(ctx, slog.LevelDebug, "flaresolverr command succeeded", append(attemptAttrs, slog.String("status", response.Status.String()))...)
+			c.hooks.succeeded(ctx, event, cmd, response)
+			return nil
+		}, retryable)
+		if !c.waitRateLimit(ctx, err, &rateLimitWaited) {
+			break
+		}
+	}
+	if c.restarts != nil {
+		c.restarts.observe(c, cmd, response, err)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return c.do(ctx, cmd)
+	tracked := withAssignedSession(cmd, response)
+	c.sessions.track(tracked, o, response)
+	if c.keepAlive != nil {
+		c.keepAlive.track(c, tracked)
+	}
+	return response, nil
 }
 
-func (c *client) do(ctx context.Context, cmd *flaresolverrCommand) (*Response, error) {
-	// set the flaresolverr default timeout
-	cmd.MaxTimeout = int(c.timeout.Milliseconds())
+func (c *client) send(ctx context.Context, cmd *flaresolverrCommand, o *requestOptions) (*Response, error) {
+	// set the flaresolverr default timeout, or the time left to a shorter caller deadline
+	// so the browser does not keep solving once the caller gave up
+	timeout := c.timeout
+	switch {
+	case o.maxTimeout > 0:
+		timeout = o.maxTimeout
+	case c.adaptive != nil && cmd.URL != "":
+		timeout = c.adaptive.timeout(cmd.URL, c.timeout)
+	}
 
-	payload := new(bytes.Buffer)
-	if err := json.NewEncoder(payload).Encode(cmd); err != nil {
+	cmd.MaxTimeout = int(timeout.Milliseconds())
+	if deadline, ok := ctx.Deadline(); ok {
+		// context deadlines run on the wall clock, not the client clock
+		if left := time.Until(deadline); left < timeout {
+			cmd.MaxTimeout = int(max(left.Milliseconds(), 1))
+		}
+	}
+
+	cmd.extra = c.profile.extraFields(o.extra)
+
+	payload := getBuffer()
+	if c.codec != nil {
+		data, err := c.codec.Marshal(cmd)
+		if err != nil {
+			putBuffer(payload)
+			return nil, fmt.Errorf("invalid command: %w", err)
+		}
+		payload.Write(data)
+	} else if err := json.NewEncoder(payload).Encode(cmd); err != nil {
+		putBuffer(payload)
 		return nil, fmt.Errorf("invalid command: %w", err)
 	}
 
-	// set the timeout, add 10 seconds
-	ctx, cancel := context.WithTimeout(ctx, c.timeout+10*time.Second)
+	// give FlareSolverr some time to answer after its own timeout,
+	// a shorter caller deadline is kept
+	ctx, cancel := context.WithTimeout(ctx, max(timeout, c.timeout)+c.timeoutPadding)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL, payload)
+	size, body, encoding := int64(payload.Len()), io.Reader(&pooledBody{Buffer: payload}), ""
+	if c.compression != nil {
+		compressed, compressedEncoding, err := c.compression.compress(payload)
+		if err != nil {
+			putBuffer(payload)
+			return nil, fmt.Errorf("cannot compress command: %w", err)
+		}
+
+		if compressedEncoding != "" {
+			putBuffer(payload)
+			size, body, encoding = int64(compressed.Len()), compressed, compressedEncoding
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL, body)
 	if err != nil {
 		return nil, fmt.Errorf("cannot make request: %w", err)
 	}
+	req.ContentLength = size
+
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
 
 	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	if c.compression != nil {
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
+	if o.requestID != "" {
+		req.Header.Set("X-Request-Id", o.requestID)
+	}
+
+	resp, err := c.api().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error making request to flaresolverr: %w", err)
 	}
 	defer resp.Body.Close()
 
-	var response Response
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if c.compression != nil {
+		c.compression.observe(resp.Header)
+	}
+
+	content, err := decodeContent(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if _, err := buf.ReadFrom(content); err != nil {
 		return nil, fmt.Errorf("cannot read flaresolverr response: %w", err)
 	}
 
+	// the pooled buffer is reused, the raw response must be copied
+	raw := buf.Bytes()
+	if c.rawResponses {
+		raw = bytes.Clone(raw)
+	}
+
+	if !looksLikeJSON(raw) {
+		return nil, newResponseError(resp, raw, fmt.Errorf("%w: %s %q", ErrInvalidServerResponse, resp.Status, snippet(raw)))
+	}
+
+	var response Response
+	if err := c.decode(raw, &response); err != nil {
+		return nil, newResponseError(resp, raw, fmt.Errorf("cannot read flaresolverr response: %w", err))
+	}
+
+	if c.rawResponses {
+		response.Raw = raw
+	}
+
+	if response.Solution != nil {
+		response.Solution.clock = c.clock
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, handleError(&response)
+		return nil, newResponseError(resp, raw, c.profile.handleError(&response))
+	}
+
+	if cmd.ReturnBase64 {
+		if err := decodeBase64Body(response.Solution); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := rateLimitError(&response, c.clock.Now()); err != nil {
+		return nil, err
+	}
+	if err := challengePageError(cmd, &response); err != nil {
+		return nil, err
+	}
+	if c.failOnHTTPError {
+		if err := httpStatusError(&response); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.spillThreshold > 0 && !c.shared(cmd, o) {
+		if err := spill(response.Solution, c.spillThreshold, c.spillDir); err != nil {
+			return nil, err
+		}
 	}
 
 	return &response, nil
 }
 
+// api returns the http client reaching FlareSolverr.
+func (c *client) api() *http.Client {
+	if c.apiClient != nil {
+		return c.apiClient
+	}
+
+	return c.httpClient
+}
+
+// logSuppressed reports the identical errors collapsed by the error suppressor.
+func (c *client) logSuppressed(key string, count int, first, last time.Time) {
+	c.log(context.Background(), slog.LevelWarn, "identical flaresolverr errors suppressed",
+		slog.String("error", key),
+		slog.Int("count", count),
+		slog.Time("first", first),
+		slog.Time("last", last),
+	)
+}
+
+func (c *client) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.logger != nil {
+		c.logger.LogAttrs(ctx, level, msg, attrs...)
+	}
+}
+
 func handleError(resp *Response) error {
 	switch message := strings.ToLower(resp.Message); {
 	case strings.Contains(message, "maximum timeout reached"):
 		return ErrRequestTimeout
+	case strings.Contains(message, "session does not exist"), strings.Contains(message, "session doesn't exist"):
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, resp.Message)
+	case strings.Contains(message, "captcha_solver") || strings.Contains(message, "custom captcha"):
+		return fmt.Errorf("%w: %s", ErrCustomCaptchaDetected, resp.Message)
+	case strings.Contains(message, "captcha"):
+		return fmt.Errorf("%w: %s", ErrCaptchaDetected, resp.Message)
+	case strings.Contains(message, "ddos-guard"):
+		return fmt.Errorf("%w: %s", ErrDDoSGuardDetected, resp.Message)
+	case strings.Contains(message, "has blocked this request") || strings.Contains(message, "access denied"):
+		return fmt.Errorf("%w: %s", ErrAccessDenied, resp.Message)
 	default:
 		return fmt.Errorf("%w: %s", ErrUnexpectedError, resp.Message)
 	}
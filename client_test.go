@@ -1,10 +1,15 @@
 package flaresolverr
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"log/slog"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,22 +20,54 @@ import (
 
 func ensureFlareSolverrRunning(t *testing.T) Client {
 	t.Helper()
-	resp, err := http.Get("http://127.0.0.1:8191/")
-	if err != nil {
-		t.Fatalf("FlareSolverr is not running: %v", err)
-	}
+	endpoint := testEndpoint()
+	transport, live := newRecorder(t)
+	if live {
+		resp, err := http.Get(strings.TrimSuffix(endpoint, "/v1") + "/")
+		if err != nil {
+			t.Fatalf("FlareSolverr is not running: %v", err)
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		t.Fatalf("FlareSolverr is not running: %v", resp.Status)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("FlareSolverr is not running: %v", resp.Status)
+		}
 	}
 
 	return &client{
-		baseURL:    "http://127.0.0.1:8191/v1",
+		baseURL:    endpoint,
 		timeout:    60 * time.Second,
-		httpClient: http.DefaultClient,
+		httpClient: &http.Client{Transport: transport},
+		clock:      systemClock,
 	}
 }
 
+// newTestServer starts a fake FlareSolverr server answering every command with handler.
+// Responses with a Raw body are answered with it as is.
+func newTestServer(t testing.TB, handler func(cmd *flaresolverrCommand) (int, *Response)) *client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var cmd flaresolverrCommand
+		if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+			t.Errorf("invalid command: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		status, resp := handler(&cmd)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if resp != nil && resp.Raw != nil {
+			// answer with the raw body, for responses Response cannot encode
+			_, _ = w.Write(resp.Raw)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	return New(srv.URL+"/v1", time.Second, srv.Client()).(*client)
+}
+
 func cleanSessions(t *testing.T, c Client) {
 	t.Helper()
 	resp, err := c.ListSessions(context.Background())
@@ -67,6 +104,10 @@ func TestNew(t *testing.T) {
 				baseURL:    "foo.bar",
 				timeout:    time.Millisecond * 60000,
 				httpClient: http.DefaultClient,
+
+				timeoutPadding: 10 * time.Second,
+				clock:          systemClock,
+				sessions:       sessionRegistry{clock: systemClock},
 			},
 		},
 		{
@@ -80,6 +121,10 @@ func TestNew(t *testing.T) {
 				baseURL:    "foo.bar",
 				timeout:    100,
 				httpClient: http.DefaultClient,
+
+				timeoutPadding: 10 * time.Second,
+				clock:          systemClock,
+				sessions:       sessionRegistry{clock: systemClock},
 			},
 		},
 	}
@@ -204,6 +249,7 @@ func Test_client_Get(t *testing.T) {
 				Solution: &ResponseSolution{
 					URL:    "https://httpbin.org/status/200",
 					Status: http.StatusOK,
+					clock:  systemClock,
 				},
 			},
 			wantErr: false,
@@ -225,7 +271,7 @@ func Test_client_Get(t *testing.T) {
 				"Solution.Response",
 				"Solution.UserAgent",
 				"Solution.Cookies",
-			)); diff != "" {
+			), cmp.AllowUnexported(ResponseSolution{}), cmpopts.IgnoreFields(ResponseSolution{}, "document")); diff != "" {
 				t.Errorf("Get() mismatch (-want +got):\n%s", diff)
 			}
 		})
@@ -329,6 +375,7 @@ func Test_client_Post(t *testing.T) {
 				Solution: &ResponseSolution{
 					URL:    "https://httpbin.org/anything",
 					Status: http.StatusOK,
+					clock:  systemClock,
 					// TODO: how to check response body
 				},
 			},
@@ -351,7 +398,7 @@ func Test_client_Post(t *testing.T) {
 				"Solution.UserAgent",
 				"Solution.Cookies",
 				"Solution.Response",
-			)); diff != "" {
+			), cmp.AllowUnexported(ResponseSolution{}), cmpopts.IgnoreFields(ResponseSolution{}, "document")); diff != "" {
 				t.Errorf("Post() mismatch (-want +got):\n%s", diff)
 			}
 		})
@@ -388,6 +435,46 @@ func Test_handleError(t *testing.T) {
 			wantErr:    true,
 			wantErrErr: ErrRequestTimeout,
 		},
+		{
+			name: "Captcha error",
+			args: args{
+				resp: &Response{
+					Message: "Error: Error solving the challenge. Captcha detected.",
+				},
+			},
+			wantErr:    true,
+			wantErrErr: ErrCaptchaDetected,
+		},
+		{
+			name: "Custom captcha error",
+			args: args{
+				resp: &Response{
+					Message: "Captcha detected but 'CAPTCHA_SOLVER' env var not set",
+				},
+			},
+			wantErr:    true,
+			wantErrErr: ErrCustomCaptchaDetected,
+		},
+		{
+			name: "DDoS-Guard error",
+			args: args{
+				resp: &Response{
+					Message: "Error: Error solving the challenge. DDoS-Guard challenge detected.",
+				},
+			},
+			wantErr:    true,
+			wantErrErr: ErrDDoSGuardDetected,
+		},
+		{
+			name: "Access denied error",
+			args: args{
+				resp: &Response{
+					Message: "Error: Cloudflare has blocked this request. Probably your IP is banned for this site, check in your web browser.",
+				},
+			},
+			wantErr:    true,
+			wantErrErr: ErrAccessDenied,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -430,3 +517,479 @@ func Test_handleSession(t *testing.T) {
 		})
 	}
 }
+
+func TestCookie_HTTPCookie(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie Cookie
+		want   *http.Cookie
+	}{
+		{
+			name: "Session cookie",
+			cookie: Cookie{
+				Name:     "foo",
+				Value:    "bar",
+				Domain:   ".example.com",
+				Path:     "/",
+				Expires:  -1,
+				HTTPOnly: true,
+				Session:  true,
+				SameSite: "Lax",
+			},
+			want: &http.Cookie{
+				Name:     "foo",
+				Value:    "bar",
+				Domain:   ".example.com",
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			},
+		},
+		{
+			name: "Persistent cookie",
+			cookie: Cookie{
+				Name:     "cf_clearance",
+				Value:    "baz",
+				Expires:  1700000000.5,
+				Secure:   true,
+				SameSite: "None",
+			},
+			want: &http.Cookie{
+				Name:     "cf_clearance",
+				Value:    "baz",
+				Expires:  time.Unix(1700000000, 500000000),
+				Secure:   true,
+				SameSite: http.SameSiteNoneMode,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, tt.cookie.HTTPCookie()); diff != "" {
+				t.Errorf("HTTPCookie() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResponse_Duration(t *testing.T) {
+	resp := &Response{StartTimestamp: 1700000000000, EndTimestamp: 1700000003250}
+
+	if got, want := resp.StartTime(), time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("StartTime() = %v, want %v", got, want)
+	}
+
+	if got, want := resp.EndTime(), time.Date(2023, 11, 14, 22, 13, 23, 250000000, time.UTC); !got.Equal(want) {
+		t.Errorf("EndTime() = %v, want %v", got, want)
+	}
+
+	if got, want := resp.Duration(), 3250*time.Millisecond; got != want {
+		t.Errorf("Duration() = %v, want %v", got, want)
+	}
+}
+
+func TestResponse_ChallengeSolved(t *testing.T) {
+	tests := []struct {
+		name              string
+		resp              *Response
+		want              bool
+		wantSolveDuration time.Duration
+	}{
+		{
+			name:              "Challenge solved",
+			resp:              &Response{Message: "Challenge solved!", StartTimestamp: 1000, EndTimestamp: 6000},
+			want:              true,
+			wantSolveDuration: 5 * time.Second,
+		},
+		{
+			name:              "Challenge not detected",
+			resp:              &Response{Message: "Challenge not detected!", StartTimestamp: 1000, EndTimestamp: 2000},
+			want:              false,
+			wantSolveDuration: 0,
+		},
+		{
+			name:              "Session command",
+			resp:              &Response{Message: "Session created successfully."},
+			want:              false,
+			wantSolveDuration: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resp.ChallengeSolved(); got != tt.want {
+				t.Errorf("ChallengeSolved() = %v, want %v", got, tt.want)
+			}
+
+			if got := tt.resp.SolveDuration(); got != tt.wantSolveDuration {
+				t.Errorf("SolveDuration() = %v, want %v", got, tt.wantSolveDuration)
+			}
+		})
+	}
+}
+
+func Test_client_WithRequestID(t *testing.T) {
+	var gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Request-Id")
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(&Response{Status: "error", Message: "Error: maximum timeout reached"})
+	}))
+	defer srv.Close()
+
+	logs := new(bytes.Buffer)
+	c := New(srv.URL, time.Second, srv.Client(), WithLogger(slog.New(slog.NewTextHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+
+	_, err := c.GetWithOptions(context.Background(), "https://example.com", uuid.Nil, WithRequestID("job-42"))
+	if !errors.Is(err, ErrRequestTimeout) {
+		t.Errorf("Get() error = %v, want %v", err, ErrRequestTimeout)
+	}
+
+	if err == nil || !strings.Contains(err.Error(), "job-42") {
+		t.Errorf("Get() error = %v, expected the request ID", err)
+	}
+
+	if gotHeader != "job-42" {
+		t.Errorf("Get() X-Request-Id = %q, want %q", gotHeader, "job-42")
+	}
+
+	if got := logs.String(); strings.Count(got, "request_id=job-42") != 2 {
+		t.Errorf("Get() logs = %q, expected the request ID in every line", got)
+	}
+}
+
+func Test_client_proxyArgument(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var cmd flaresolverrCommand
+		_ = json.NewDecoder(r.Body).Decode(&cmd)
+		got = append(got, cmd.Proxy)
+		_ = json.NewEncoder(w).Encode(&Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}})
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := New(srv.URL, time.Second, srv.Client())
+	if _, err := c.CreateSession(ctx, uuid.New(), "http://a:8080"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if _, err := c.Get(ctx, "https://example.com", uuid.Nil, "http://b:8080", "http://ignored:8080"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, err := c.Post(ctx, "https://example.com", uuid.Nil, "a=b"); err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+
+	want := []string{"http://a:8080", "http://b:8080", ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sent proxies %q, want %q", got, want)
+	}
+}
+
+func Test_client_WithReturnOnlyCookies(t *testing.T) {
+	var got []bool
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		got = append(got, cmd.ReturnOnlyCookies)
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+
+	ctx := context.Background()
+	c := New(srv.baseURL, time.Second, srv.httpClient)
+	if _, err := c.Get(ctx, "https://example.com", uuid.Nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, err := c.GetWithOptions(ctx, "https://example.com", uuid.Nil, WithReturnOnlyCookies()); err != nil {
+		t.Fatalf("GetWithOptions() error = %v", err)
+	}
+	if _, err := c.PostWithOptions(ctx, "https://example.com", uuid.Nil, "a=b", WithReturnOnlyCookies()); err != nil {
+		t.Fatalf("PostWithOptions() error = %v", err)
+	}
+
+	if want := []bool{false, true, true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sent returnOnlyCookies %v, want %v", got, want)
+	}
+}
+
+func TestWithHeader(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		_ = json.NewEncoder(w).Encode(&Response{Status: "ok"})
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		opts []Option
+		want map[string]string
+	}{
+		{
+			name: "Custom header",
+			opts: []Option{WithHeader("X-Api-Key", "foo")},
+			want: map[string]string{"X-Api-Key": "foo", "Content-Type": "application/json"},
+		},
+		{
+			name: "Basic auth",
+			opts: []Option{WithBasicAuth("foo", "bar")},
+			want: map[string]string{"Authorization": "Basic Zm9vOmJhcg=="},
+		},
+		{
+			name: "Bearer token",
+			opts: []Option{WithBearerToken("foo")},
+			want: map[string]string{"Authorization": "Bearer foo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(srv.URL, time.Second, srv.Client(), tt.opts...)
+			if _, err := c.ListSessions(context.Background()); err != nil {
+				t.Fatalf("ListSessions() error = %v", err)
+			}
+
+			for key, value := range tt.want {
+				if got.Get(key) != value {
+					t.Errorf("header %s = %q, want %q", key, got.Get(key), value)
+				}
+			}
+		})
+	}
+}
+
+func TestWithRawResponses(t *testing.T) {
+	const body = `{"status":"ok","message":"","solution":{"url":"https://example.com","status":200,"turnstile_token":"foo"}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "Raw response kept",
+			opts: []Option{WithRawResponses()},
+			want: body,
+		},
+		{
+			name: "Raw response dropped by default",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(srv.URL, time.Second, srv.Client(), tt.opts...)
+			resp, err := c.Get(context.Background(), "https://example.com", uuid.Nil)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+
+			if string(resp.Raw) != tt.want {
+				t.Errorf("Raw = %s, want %s", resp.Raw, tt.want)
+			}
+
+			if resp.Solution.URL != "https://example.com" {
+				t.Errorf("Solution.URL = %v, want %v", resp.Solution.URL, "https://example.com")
+			}
+		})
+	}
+}
+
+func TestWithTimeoutPadding(t *testing.T) {
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		time.Sleep(200 * time.Millisecond)
+		return http.StatusOK, &Response{Status: "ok"}
+	})
+
+	tests := []struct {
+		name    string
+		padding time.Duration
+		wantErr error
+	}{
+		{name: "Answer within padding", padding: time.Second},
+		{name: "Answer after padding", padding: 50 * time.Millisecond, wantErr: context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(srv.baseURL, 50*time.Millisecond, srv.httpClient, WithTimeoutPadding(tt.padding))
+			if _, err := c.ListSessions(context.Background()); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ListSessions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_client_maxTimeout(t *testing.T) {
+	var got int
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		got = cmd.MaxTimeout
+		return http.StatusOK, &Response{Status: "ok"}
+	})
+
+	tests := []struct {
+		name     string
+		deadline time.Duration
+		min, max int
+	}{
+		{name: "No deadline", min: 1000, max: 1000},
+		{name: "Longer deadline", deadline: time.Minute, min: 1000, max: 1000},
+		{name: "Shorter deadline", deadline: 500 * time.Millisecond, min: 400, max: 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+
+			if _, err := c.Get(ctx, "https://example.com", uuid.Nil); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+
+			if got < tt.min || got > tt.max {
+				t.Errorf("Get() maxTimeout = %d, want between %d and %d", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestNewWithValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		want    string
+		wantErr error
+	}{
+		{name: "Endpoint", baseURL: "http://127.0.0.1:8191/v1", want: "http://127.0.0.1:8191/v1"},
+		{name: "Server root", baseURL: "http://127.0.0.1:8191", want: "http://127.0.0.1:8191/v1"},
+		{name: "Trailing slash", baseURL: "https://example.com/flaresolverr/v1/", want: "https://example.com/flaresolverr/v1"},
+		{name: "Missing scheme", baseURL: "foo.bar", wantErr: ErrInvalidBaseURL},
+		{name: "Missing host", baseURL: "http:///v1", wantErr: ErrInvalidBaseURL},
+		{name: "Malformed", baseURL: "http://[::1", wantErr: ErrInvalidBaseURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewWithValidation(tt.baseURL, 0, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewWithValidation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && got.(*client).baseURL != tt.want {
+				t.Errorf("NewWithValidation() baseURL = %q, want %q", got.(*client).baseURL, tt.want)
+			}
+		})
+	}
+}
+
+func Test_flaresolverrCommand_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		extra map[string]any
+		want  string
+	}{
+		{
+			name: "No extra fields",
+			want: `{"cmd":"request.get","url":"https://example.com","maxTimeout":1000}`,
+		},
+		{
+			name:  "Extra fields",
+			extra: map[string]any{"disableMedia": true, "url": "https://example.org"},
+			want:  `{"cmd":"request.get","disableMedia":true,"maxTimeout":1000,"url":"https://example.com"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &flaresolverrCommand{Cmd: CommandRequestget, URL: "https://example.com", MaxTimeout: 1000}
+			cmd.extra = newRequestOptions(context.Background(), []RequestOption{WithExtraFields(tt.extra)}).extra
+
+			got, err := json.Marshal(cmd)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("MarshalJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCookie_IsExpired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name        string
+		cookie      Cookie
+		wantExpires time.Time
+		want        bool
+	}{
+		{name: "Session cookie", cookie: Cookie{Expires: -1, Session: true}},
+		{name: "Valid cookie", cookie: Cookie{Expires: 1700000060.5}, wantExpires: time.Unix(1700000060, 5e8)},
+		{name: "Expired cookie", cookie: Cookie{Expires: 1699999999}, wantExpires: time.Unix(1699999999, 0), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cookie.ExpiresAt(); !got.Equal(tt.wantExpires) {
+				t.Errorf("ExpiresAt() = %v, want %v", got, tt.wantExpires)
+			}
+
+			if got := tt.cookie.IsExpired(now); got != tt.want {
+				t.Errorf("IsExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCookie_v3(t *testing.T) {
+	// FlareSolverr v3 returns the cookies of Selenium, with an expiry and without session
+	var got []Cookie
+	data := `[
+		{"name": "cf_clearance", "value": "solved", "domain": ".example.com", "path": "/", "expiry": 1700000060, "httpOnly": true, "secure": true, "sameSite": "None"},
+		{"name": "lang", "value": "en", "domain": "example.com", "path": "/", "httpOnly": false, "secure": false}
+	]`
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	if expires := got[0].ExpiresAt(); !expires.Equal(time.Unix(1700000060, 0)) {
+		t.Errorf("ExpiresAt() = %v, want the expiry", expires)
+	}
+	if got[0].IsExpired(now) || !got[0].IsExpired(now.Add(time.Minute)) {
+		t.Errorf("IsExpired() mismatch, want the cookie expired after a minute")
+	}
+	if expires := got[0].HTTPCookie().Expires; !expires.Equal(time.Unix(1700000060, 0)) {
+		t.Errorf("HTTPCookie() expires = %v, want the expiry", expires)
+	}
+
+	if expires := got[1].ExpiresAt(); !expires.IsZero() || got[1].IsExpired(now) {
+		t.Errorf("ExpiresAt() = %v, want a session cookie", expires)
+	}
+}
+
+func Test_client_CreateSession_WithCookies(t *testing.T) {
+	var got []commandCookie
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		got = cmd.Cookies
+		return http.StatusOK, &Response{Status: "ok", Message: "Session created successfully.", Session: cmd.Session}
+	})
+
+	expires := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	cookies := []*http.Cookie{
+		{Name: "session_id", Value: "foo", Domain: ".example.com", Path: "/", Secure: true, HttpOnly: true, Expires: expires, SameSite: http.SameSiteLaxMode},
+		{Name: "lang", Value: "en"},
+	}
+
+	if _, err := c.CreateSessionWithOptions(context.Background(), uuid.New(), WithCookies(cookies)); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	want := []commandCookie{
+		{Name: "session_id", Value: "foo", Domain: ".example.com", Path: "/", Secure: true, HTTPOnly: true, Expiry: expires.Unix(), SameSite: "Lax"},
+		{Name: "lang", Value: "en"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CreateSession() cookies mismatch (-want +got):\n%s", diff)
+	}
+}
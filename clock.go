@@ -0,0 +1,83 @@
+package flaresolverr
+
+import "time"
+
+// Clock tells the time and waits for the client, see WithClock.
+// It must be safe for concurrent use.
+type Clock interface {
+	Now() time.Time
+
+	// NewTimer returns a timer sending the time on its channel once d has elapsed.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single-use timer created by a Clock.
+type Timer interface {
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing, it reports whether the timer was stopped before firing.
+	Stop() bool
+}
+
+// systemClock is the real clock, used by default.
+var systemClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{t: time.NewTimer(d)}
+}
+
+type realTimer struct {
+	t *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.t.Stop()
+}
+
+// useClock shares the clock of the client with its subsystems, once the options are applied.
+func (c *client) useClock() {
+	c.sessions.clock = c.clock
+	if c.cache != nil {
+		c.cache.clock = c.clock
+	}
+	if c.clearances != nil {
+		c.clearances.clock = c.clock
+	}
+	if c.politeness != nil {
+		c.politeness.clock = c.clock
+	}
+	if c.proxies != nil {
+		c.proxies.clock = c.clock
+	}
+	if c.errorSuppressor != nil {
+		c.errorSuppressor.clock = c.clock
+	}
+	if c.keepAlive != nil {
+		c.keepAlive.clock = c.clock
+	}
+	if c.restarts != nil {
+		c.restarts.clock = c.clock
+	}
+	if c.domainSessions != nil {
+		c.domainSessions.clock = c.clock
+	}
+}
+
+// clockOf returns the clock of a client created with New, or the system clock.
+func clockOf(c Client) Clock {
+	if cl, ok := c.(*client); ok && cl.clock != nil {
+		return cl.clock
+	}
+
+	return systemClock
+}
@@ -0,0 +1,170 @@
+package flaresolverr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+// fakeClock is a clock whose timers fire at once, moving the time forward.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	waited []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(max(d, 0))
+	c.waited = append(c.waited, d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return fakeTimer(ch)
+}
+
+// Advance moves the time forward by d.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (c *fakeClock) Waited() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.waited...)
+}
+
+type fakeTimer chan time.Time
+
+func (t fakeTimer) C() <-chan time.Time { return t }
+func (t fakeTimer) Stop() bool          { return false }
+
+func Test_client_WithClock(t *testing.T) {
+	var mu sync.Mutex
+	calls, maxTimeout := 0, 0
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		maxTimeout = cmd.MaxTimeout
+
+		if calls <= 2 {
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: maximum timeout reached"}
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Status: http.StatusOK}}
+	})
+
+	clock := newFakeClock()
+	c := New(srv.baseURL, time.Second, srv.httpClient,
+		WithClock(clock),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Minute}),
+		WithResponseCache(time.Hour, 10),
+	)
+
+	ctx := context.Background()
+	start := time.Now()
+	if _, err := c.Get(ctx, "https://example.com", uuid.Nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Get() took %s, want the backoff waited on the fake clock", elapsed)
+	}
+
+	if diff := cmp.Diff([]time.Duration{time.Minute, 2 * time.Minute}, clock.Waited()); diff != "" {
+		t.Errorf("waited mismatch (-want +got):\n%s", diff)
+	}
+
+	// served from the cache until the TTL elapses on the fake clock
+	_, _ = c.Get(ctx, "https://example.com", uuid.Nil)
+	clock.Advance(time.Hour + time.Second)
+	_, _ = c.Get(ctx, "https://example.com", uuid.Nil)
+
+	if got := c.Stats(); got.CacheHits != 1 || got.CacheMisses != 2 {
+		t.Errorf("Stats() cache = %d hits, %d misses, want 1 hit and 2 misses", got.CacheHits, got.CacheMisses)
+	}
+
+	// the caller deadline runs on the wall clock
+	deadlineCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	_, _ = c.Get(deadlineCtx, "https://example.org", uuid.Nil)
+	mu.Lock()
+	defer mu.Unlock()
+	if maxTimeout > 500 {
+		t.Errorf("maxTimeout = %d, want at most the 500ms left to the caller deadline", maxTimeout)
+	}
+}
+
+func Test_client_WithClock_subsystems(t *testing.T) {
+	destroyed := make(chan string, 1)
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		switch {
+		case cmd.Cmd == CommandSessionsdestroy:
+			destroyed <- cmd.Session
+		case strings.Contains(cmd.URL, "/timeout"):
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: maximum timeout reached"}
+		}
+
+		solution := &ResponseSolution{URL: cmd.URL, Status: http.StatusOK}
+		solution.Headers.RetryAfter = "Mon, 01 Jan 2024 02:00:00 GMT"
+		return http.StatusOK, &Response{Status: "ok", Solution: solution}
+	})
+
+	clock := newFakeClock()
+	var log bytes.Buffer
+	c := New(srv.baseURL, time.Second, srv.httpClient,
+		WithClock(clock),
+		WithAuditLog(&log),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Minute}),
+		WithSessionPerDomain(time.Hour),
+	)
+
+	// the idle site session is destroyed once the hour elapsed on the fake clock
+	ctx := context.Background()
+	resp, err := c.Get(ctx, "https://example.com", uuid.Nil)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	select {
+	case <-destroyed:
+	case <-time.After(time.Second):
+		t.Errorf("idle site session not destroyed")
+	}
+
+	// the fake clock is at 01:00, the hour the session was idle
+	if got, want := resp.Solution.RetryAfter(), time.Hour; got != want {
+		t.Errorf("RetryAfter() = %v, want %v", got, want)
+	}
+
+	log.Reset()
+	_, _ = c.Get(ctx, "https://example.org/timeout", uuid.Nil)
+	lines := bytes.Split(bytes.TrimSpace(log.Bytes()), []byte("\n"))
+	var record AuditRecord
+	if err := json.Unmarshal(lines[len(lines)-1], &record); err != nil {
+		t.Fatalf("invalid audit log %q: %v", log.String(), err)
+	}
+	if record.DurationMs != time.Minute.Milliseconds() {
+		t.Errorf("audit duration = %dms, want the backoff waited on the fake clock", record.DurationMs)
+	}
+}
@@ -0,0 +1,24 @@
+package flaresolverr
+
+import "context"
+
+// Close stops the background work of the client: the pings of WithSessionKeepAlive, the idle timers
+// of WithSessionPerDomain and the windows of WithErrorSuppression, whose summaries are logged at once.
+// The sessions created by the client for WithSessionPerDomain are destroyed, the ones created
+// with CreateSession are left to the caller. When ctx is done first, Close returns the context error.
+// The client must not be used after Close.
+func (c *client) Close(ctx context.Context) error {
+	if c.keepAlive != nil {
+		c.keepAlive.close()
+	}
+
+	if c.errorSuppressor != nil {
+		c.errorSuppressor.close()
+	}
+
+	if c.domainSessions != nil {
+		return c.domainSessions.close(ctx, c)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_client_Close(t *testing.T) {
+	var pings atomic.Int32
+	var mu sync.Mutex
+	var created, destroyed []string
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case cmd.Cmd == CommandSessionscreate:
+			created = append(created, cmd.Session)
+		case cmd.Cmd == CommandSessionsdestroy:
+			destroyed = append(destroyed, cmd.Session)
+		case cmd.URL == "https://example.com/ping":
+			pings.Add(1)
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Status: http.StatusOK}}
+	})
+
+	var summaries sync.Map
+	c := New(srv.baseURL, time.Second, srv.httpClient,
+		WithSessionKeepAlive(10*time.Millisecond, "https://example.com/ping"),
+		WithSessionPerDomain(time.Hour),
+	).(*client)
+	c.errorSuppressor = newSuppressor(time.Hour, func(key string, count int, _, _ time.Time) { summaries.Store(key, count) })
+	c.errorSuppressor.allow("foo")
+	c.errorSuppressor.allow("foo")
+
+	ctx := context.Background()
+	if _, err := c.CreateSession(ctx, uuid.New()); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if _, err := c.Get(ctx, "https://www.example.org", uuid.Nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	waitFor(t, func() bool { return pings.Load() > 0 })
+
+	if err := c.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// only the site session is destroyed, the other one belongs to the caller
+	mu.Lock()
+	if len(created) != 2 || len(destroyed) != 1 || destroyed[0] != created[1] {
+		t.Errorf("destroyed sessions %q, want the site session of %q", destroyed, created)
+	}
+	mu.Unlock()
+
+	if got, _ := summaries.Load("foo"); got != 1 {
+		t.Errorf("suppressed summary = %v, want 1 once closed", got)
+	}
+
+	got := pings.Load()
+	time.Sleep(50 * time.Millisecond)
+	if after := pings.Load(); after != got {
+		t.Errorf("session pinged %d times after Close", after-got)
+	}
+}
+
+func Test_client_Close_canceled(t *testing.T) {
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Status: http.StatusOK}}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithSessionPerDomain(time.Hour))
+
+	if _, err := c.Get(context.Background(), "https://example.com", uuid.Nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.Close(ctx); err == nil {
+		t.Errorf("Close() error = nil, want the context error")
+	}
+}
@@ -0,0 +1,166 @@
+// Command flaresolverr talks to a FlareSolverr instance from the shell.
+//
+// Usage:
+//
+//	flaresolverr [flags] get URL
+//	flaresolverr [flags] post URL DATA
+//	flaresolverr [flags] cookies URL
+//	flaresolverr [flags] sessions list
+//	flaresolverr [flags] sessions create [ID]
+//	flaresolverr [flags] sessions destroy ID
+//
+// Every command prints its result as JSON on the standard output.
+// The FlareSolverr endpoint defaults to $FLARESOLVERR_URL, or http://127.0.0.1:8191/v1.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+	"github.com/google/uuid"
+)
+
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "flaresolverr:", err)
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout io.Writer) error {
+	endpoint := os.Getenv("FLARESOLVERR_URL")
+	if endpoint == "" {
+		endpoint = "http://127.0.0.1:8191/v1"
+	}
+
+	flags := flag.NewFlagSet("flaresolverr", flag.ContinueOnError)
+	flags.StringVar(&endpoint, "url", endpoint, "FlareSolverr endpoint")
+	timeout := flags.Duration("timeout", 60*time.Second, "maximum time to solve a challenge")
+	proxy := flags.String("proxy", "", "proxy used by the browser")
+	sessionID := flags.String("session", "", "session to use")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: flaresolverr [flags] get URL | post URL DATA | cookies URL | sessions list|create [ID]|destroy ID")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	var opts []flaresolverr.RequestOption
+	if *sessionID != "" {
+		session, err := parseSession(*sessionID)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, flaresolverr.WithSessionID(session))
+	}
+	if *proxy != "" {
+		opts = append(opts, flaresolverr.WithProxy(*proxy))
+	}
+
+	c, err := flaresolverr.NewWithValidation(endpoint, *timeout, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	out := printer{w: stdout}
+	args = flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	switch cmd, args := args[0], args[1:]; {
+	case cmd == "get" && len(args) == 1:
+		return out.print(c.GetWithOptions(ctx, args[0], uuid.Nil, opts...))
+	case cmd == "post" && len(args) == 2:
+		return out.print(c.PostWithOptions(ctx, args[0], uuid.Nil, args[1], opts...))
+	case cmd == "cookies" && len(args) == 1:
+		resp, err := c.GetWithOptions(ctx, args[0], uuid.Nil, append(opts, flaresolverr.WithReturnOnlyCookies())...)
+		if err != nil {
+			return err
+		}
+
+		var cookies []flaresolverr.Cookie
+		if resp.Solution != nil {
+			cookies = resp.Solution.Cookies
+		}
+		return out.print(cookies, nil)
+	case cmd == "sessions":
+		return sessions(ctx, c, args, opts, out)
+	default:
+		flags.Usage()
+		return errUsage
+	}
+}
+
+func sessions(ctx context.Context, c flaresolverr.Client, args []string, opts []flaresolverr.RequestOption, out printer) error {
+	switch {
+	case len(args) == 1 && args[0] == "list":
+		return out.print(c.ListSessionsWithOptions(ctx, opts...))
+	case len(args) >= 1 && len(args) <= 2 && args[0] == "create":
+		session := flaresolverr.SessionIDFromUUID(uuid.New())
+		if len(args) == 2 {
+			var err error
+			if session, err = parseSession(args[1]); err != nil {
+				return err
+			}
+		}
+		return out.print(c.CreateSessionWithOptions(ctx, uuid.Nil, append(opts, flaresolverr.WithSessionID(session))...))
+	case len(args) == 2 && args[0] == "destroy":
+		session, err := parseSession(args[1])
+		if err != nil {
+			return err
+		}
+
+		if err := c.DestroySessionWithOptions(ctx, uuid.Nil, append(opts, flaresolverr.WithSessionID(session))...); err != nil {
+			return err
+		}
+		return out.print(map[string]string{"session": session.String(), "status": "destroyed"}, nil)
+	default:
+		return fmt.Errorf("%w: sessions list|create [ID]|destroy ID", errUsage)
+	}
+}
+
+// parseSession returns the session ID given on the command line, which does not have to be a UUID.
+func parseSession(id string) (flaresolverr.SessionID, error) {
+	session, err := flaresolverr.ParseSessionID(id)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	return session, nil
+}
+
+// printer writes the command results.
+type printer struct {
+	w io.Writer
+}
+
+// print writes v as indented JSON, unless err is set.
+func (p printer) print(v any, err error) error {
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
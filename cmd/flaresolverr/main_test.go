@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_run(t *testing.T) {
+	var last map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var cmd map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&cmd)
+		last = cmd
+
+		switch cmd["cmd"] {
+		case "sessions.list":
+			_, _ = w.Write([]byte(`{"status":"ok","sessions":["47d0a203-a007-4a01-b8c1-0cf0156c3cc7"]}`))
+		case "request.get":
+			if cmd["returnOnlyCookies"] != true {
+				_, _ = w.Write([]byte(`{"status":"ok","solution":{"url":"https://example.com","response":"<html></html>"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"ok","solution":{"url":"https://example.com","cookies":[{"name":"cf_clearance","value":"foo"}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		args     []string
+		want     string
+		wantErr  error
+		wantSent map[string]any
+	}{
+		{
+			name: "List sessions",
+			args: []string{"-url", srv.URL, "sessions", "list"},
+			want: `"47d0a203-a007-4a01-b8c1-0cf0156c3cc7"`,
+		},
+		{
+			name: "Cookies",
+			args: []string{"-url", srv.URL, "cookies", "https://example.com"},
+			want: `"name": "cf_clearance"`,
+		},
+		{
+			name:     "Cookies in session",
+			args:     []string{"-url", srv.URL, "-session", "my-session", "cookies", "https://example.com"},
+			want:     `"name": "cf_clearance"`,
+			wantSent: map[string]any{"session": "my-session", "returnOnlyCookies": true},
+		},
+		{
+			name:     "Create session",
+			args:     []string{"-url", srv.URL, "sessions", "create", "my-session"},
+			wantSent: map[string]any{"cmd": "sessions.create", "session": "my-session"},
+		},
+		{
+			name:     "Destroy session",
+			args:     []string{"-url", srv.URL, "sessions", "destroy", "47d0a203-a007-4a01-b8c1-0cf0156c3cc7"},
+			want:     `"status": "destroyed"`,
+			wantSent: map[string]any{"cmd": "sessions.destroy", "session": "47d0a203-a007-4a01-b8c1-0cf0156c3cc7"},
+		},
+		{
+			name:     "Destroy session not a UUID",
+			args:     []string{"-url", srv.URL, "sessions", "destroy", "my-session"},
+			want:     `"session": "my-session"`,
+			wantSent: map[string]any{"cmd": "sessions.destroy", "session": "my-session"},
+		},
+		{
+			name:    "Invalid session",
+			args:    []string{"-url", srv.URL, "sessions", "destroy", "foo bar"},
+			wantErr: errUsage,
+		},
+		{
+			name:    "Invalid session flag",
+			args:    []string{"-url", srv.URL, "-session", "foo/bar", "get", "https://example.com"},
+			wantErr: errUsage,
+		},
+		{
+			name:    "Unknown command",
+			args:    []string{"-url", srv.URL, "foo"},
+			wantErr: errUsage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := new(bytes.Buffer)
+			err := run(context.Background(), tt.args, stdout)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !strings.Contains(stdout.String(), tt.want) {
+				t.Errorf("run() output = %s, want %s", stdout, tt.want)
+			}
+
+			for key, want := range tt.wantSent {
+				if last[key] != want {
+					t.Errorf("run() sent %s = %v, want %v", key, last[key], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package flaresolverr
+
+import "encoding/json"
+
+// JSONCodec marshals the commands sent to FlareSolverr and unmarshals its responses.
+// It is satisfied by the standard-library compatible configurations of
+// alternative JSON libraries, such as jsoniter or sonic.
+// Unmarshal must not retain data after returning, its buffer is reused.
+type JSONCodec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// stdCodec is the encoding/json codec.
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,65 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// countingCodec counts the calls to encoding/json.
+type countingCodec struct {
+	stdCodec
+	marshal, unmarshal int
+}
+
+func (c *countingCodec) Marshal(v any) ([]byte, error) {
+	c.marshal++
+	return c.stdCodec.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.unmarshal++
+	return c.stdCodec.Unmarshal(data, v)
+}
+
+func Test_client_WithJSONCodec(t *testing.T) {
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+
+	codec := new(countingCodec)
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithJSONCodec(codec))
+
+	resp, err := c.Get(context.Background(), "https://example.com", uuid.Nil)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if resp.Solution.URL != "https://example.com" {
+		t.Errorf("Get() = %v, want the decoded solution", resp.Solution)
+	}
+
+	if codec.marshal != 1 || codec.unmarshal != 1 {
+		t.Errorf("codec calls = %d marshal, %d unmarshal, want 1 each", codec.marshal, codec.unmarshal)
+	}
+}
+
+func Test_client_WithJSONCodec_strict(t *testing.T) {
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+
+	codec := new(countingCodec)
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithJSONCodec(codec), WithStrictDecoding())
+
+	if _, err := c.Get(context.Background(), "https://example.com", uuid.Nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if codec.unmarshal != 1 {
+		t.Errorf("codec unmarshal calls = %d, want 1", codec.unmarshal)
+	}
+}
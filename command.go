@@ -2,6 +2,16 @@ package flaresolverr
 
 //go:generate go run github.com/abice/go-enum@v0.5.5 --file=$GOFILE --marshal
 
+// Command is a FlareSolverr command, sent as the cmd field of every request:
+//
+//   - sessions.create launches a browser instance kept until it is destroyed.
+//   - sessions.list returns the active sessions.
+//   - sessions.destroy shuts down a browser instance.
+//   - request.get loads a URL, solving the challenge protecting it.
+//   - request.post submits form data to a URL, solving the challenge protecting it.
+//
+// ParseCommand and IsValid tell whether a command is known by the client.
+//
 // ENUM(
 // sessions.create
 // sessions.list
@@ -9,4 +19,4 @@ package flaresolverr
 // request.get
 // request.post
 // )
-type command string
+type Command string
@@ -12,32 +12,32 @@ import (
 )
 
 const (
-	// CommandSessionscreate is a command of type sessions.create.
-	CommandSessionscreate command = "sessions.create"
-	// CommandSessionslist is a command of type sessions.list.
-	CommandSessionslist command = "sessions.list"
-	// CommandSessionsdestroy is a command of type sessions.destroy.
-	CommandSessionsdestroy command = "sessions.destroy"
-	// CommandRequestget is a command of type request.get.
-	CommandRequestget command = "request.get"
-	// CommandRequestpost is a command of type request.post.
-	CommandRequestpost command = "request.post"
+	// CommandSessionscreate is a Command of type sessions.create.
+	CommandSessionscreate Command = "sessions.create"
+	// CommandSessionslist is a Command of type sessions.list.
+	CommandSessionslist Command = "sessions.list"
+	// CommandSessionsdestroy is a Command of type sessions.destroy.
+	CommandSessionsdestroy Command = "sessions.destroy"
+	// CommandRequestget is a Command of type request.get.
+	CommandRequestget Command = "request.get"
+	// CommandRequestpost is a Command of type request.post.
+	CommandRequestpost Command = "request.post"
 )
 
-var ErrInvalidcommand = errors.New("not a valid command")
+var ErrInvalidCommand = errors.New("not a valid Command")
 
 // String implements the Stringer interface.
-func (x command) String() string {
+func (x Command) String() string {
 	return string(x)
 }
 
 // String implements the Stringer interface.
-func (x command) IsValid() bool {
-	_, err := Parsecommand(string(x))
+func (x Command) IsValid() bool {
+	_, err := ParseCommand(string(x))
 	return err == nil
 }
 
-var _commandValue = map[string]command{
+var _CommandValue = map[string]Command{
 	"sessions.create":  CommandSessionscreate,
 	"sessions.list":    CommandSessionslist,
 	"sessions.destroy": CommandSessionsdestroy,
@@ -45,22 +45,22 @@ var _commandValue = map[string]command{
 	"request.post":     CommandRequestpost,
 }
 
-// Parsecommand attempts to convert a string to a command.
-func Parsecommand(name string) (command, error) {
-	if x, ok := _commandValue[name]; ok {
+// ParseCommand attempts to convert a string to a Command.
+func ParseCommand(name string) (Command, error) {
+	if x, ok := _CommandValue[name]; ok {
 		return x, nil
 	}
-	return command(""), fmt.Errorf("%s is %w", name, ErrInvalidcommand)
+	return Command(""), fmt.Errorf("%s is %w", name, ErrInvalidCommand)
 }
 
 // MarshalText implements the text marshaller method.
-func (x command) MarshalText() ([]byte, error) {
+func (x Command) MarshalText() ([]byte, error) {
 	return []byte(string(x)), nil
 }
 
 // UnmarshalText implements the text unmarshaller method.
-func (x *command) UnmarshalText(text []byte) error {
-	tmp, err := Parsecommand(string(text))
+func (x *Command) UnmarshalText(text []byte) error {
+	tmp, err := ParseCommand(string(text))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,38 @@
+package flaresolverr
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Command
+		wantErr error
+	}{
+		{name: "sessions.create", want: CommandSessionscreate},
+		{name: "request.post", want: CommandRequestpost},
+		{name: "request.cookies", wantErr: ErrInvalidCommand},
+		{name: "", wantErr: ErrInvalidCommand},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCommand(tt.name)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want || got.IsValid() != (tt.wantErr == nil) {
+				t.Errorf("ParseCommand() = %q, valid %v, want %q", got, got.IsValid(), tt.want)
+			}
+
+			var cmd flaresolverrCommand
+			err = json.Unmarshal([]byte(`{"cmd":"`+tt.name+`"}`), &cmd)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
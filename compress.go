@@ -0,0 +1,63 @@
+package flaresolverr
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// compression compresses the commands sent to FlareSolverr once the server advertised support.
+type compression struct {
+	minSize   int
+	supported atomic.Bool
+}
+
+// observe records whether the server accepts gzip request bodies,
+// as advertised by the Accept-Encoding response header (RFC 7694).
+func (c *compression) observe(header http.Header) {
+	if strings.Contains(strings.ToLower(header.Get("Accept-Encoding")), "gzip") {
+		c.supported.Store(true)
+	}
+}
+
+// compress returns the body to send and its content encoding, empty if not compressed.
+func (c *compression) compress(payload *bytes.Buffer) (*bytes.Buffer, string, error) {
+	if !c.supported.Load() || payload.Len() < c.minSize {
+		return payload, "", nil
+	}
+
+	compressed := new(bytes.Buffer)
+	zw := gzip.NewWriter(compressed)
+	if _, err := zw.Write(payload.Bytes()); err != nil {
+		return nil, "", err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return compressed, "gzip", nil
+}
+
+// decodeContent returns the response body, decompressed according to its Content-Encoding.
+func decodeContent(resp *http.Response) (io.ReadCloser, error) {
+	switch strings.ToLower(resp.Header.Get("Content-Encoding")) {
+	case "", "identity":
+		return resp.Body, nil
+	case "gzip":
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read flaresolverr response: %w", err)
+		}
+		return zr, nil
+	case "deflate":
+		return flate.NewReader(resp.Body), nil
+	default:
+		return nil, fmt.Errorf("cannot read flaresolverr response: unsupported content encoding %q", resp.Header.Get("Content-Encoding"))
+	}
+}
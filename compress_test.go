@@ -0,0 +1,72 @@
+package flaresolverr
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_client_WithCompression(t *testing.T) {
+	var encodings []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+
+		body := io.Reader(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("invalid gzip body: %v", err)
+				return
+			}
+			body = zr
+		}
+
+		var cmd flaresolverrCommand
+		if err := json.NewDecoder(body).Decode(&cmd); err != nil {
+			t.Errorf("invalid command: %v", err)
+			return
+		}
+
+		w.Header().Set("Accept-Encoding", "gzip")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		defer zw.Close()
+		_ = json.NewEncoder(zw).Encode(&Response{Status: "ok", Solution: &ResponseSolution{Response: cmd.PostData}})
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, time.Second, srv.Client(), WithCompression(1024))
+	data := "q=" + strings.Repeat("a", 2048)
+
+	for _, want := range []string{"", "gzip"} {
+		resp, err := c.Post(context.Background(), "https://example.com", uuid.Nil, data)
+		if err != nil {
+			t.Fatalf("Post() error = %v", err)
+		}
+
+		if resp.Solution.Response != data {
+			t.Errorf("Post() = %q, want the post data", resp.Solution.Response)
+		}
+
+		if got := encodings[len(encodings)-1]; got != want {
+			t.Errorf("Post() Content-Encoding = %q, want %q", got, want)
+		}
+	}
+
+	// small commands are not compressed
+	if _, err := c.ListSessions(context.Background()); err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+
+	if got := encodings[len(encodings)-1]; got != "" {
+		t.Errorf("ListSessions() Content-Encoding = %q, want none", got)
+	}
+}
@@ -0,0 +1,117 @@
+package flaresolverr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// flaresolverrImage is the image started when FLARESOLVERR_DOCKER is set to 1 or true.
+const flaresolverrImage = "ghcr.io/flaresolverr/flaresolverr:latest"
+
+// TestMain starts a FlareSolverr container for the tests needing a live server when
+// FLARESOLVERR_DOCKER is set, to 1 or true for the latest image, or to the image to use.
+// FLARESOLVERR_URL otherwise points to the live server, http://127.0.0.1:8191/v1 by default.
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	image := os.Getenv("FLARESOLVERR_DOCKER")
+	switch strings.ToLower(image) {
+	case "":
+		return m.Run()
+	case "1", "true":
+		image = flaresolverrImage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	c, err := startContainer(ctx, image)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cannot start FlareSolverr:", err)
+		return 1
+	}
+	defer c.stop()
+
+	os.Setenv("FLARESOLVERR_URL", c.endpoint)
+	os.Setenv("FLARESOLVERR_ENDPOINTS", "docker="+c.endpoint)
+	return m.Run()
+}
+
+// testEndpoint returns the endpoint of the live FlareSolverr server.
+func testEndpoint() string {
+	if endpoint := os.Getenv("FLARESOLVERR_URL"); endpoint != "" {
+		return endpoint
+	}
+
+	return "http://127.0.0.1:8191/v1"
+}
+
+// container is a FlareSolverr container run with docker, or podman.
+type container struct {
+	runtime  string
+	id       string
+	endpoint string
+}
+
+// startContainer starts image on a random local port, and waits for FlareSolverr to be ready.
+func startContainer(ctx context.Context, image string) (*container, error) {
+	runtime := "docker"
+	if _, err := exec.LookPath(runtime); err != nil {
+		runtime = "podman"
+	}
+
+	out, err := exec.CommandContext(ctx, runtime, "run", "-d", "--rm", "-p", "127.0.0.1::8191", image).Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s run: %w", runtime, err)
+	}
+	c := &container{runtime: runtime, id: strings.TrimSpace(string(out))}
+
+	out, err = exec.CommandContext(ctx, runtime, "port", c.id, "8191/tcp").Output()
+	if err != nil {
+		c.stop()
+		return nil, fmt.Errorf("%s port: %w", runtime, err)
+	}
+
+	// the first line is the IPv4 binding, e.g. 127.0.0.1:49153
+	addr, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	c.endpoint = "http://" + strings.TrimSpace(addr) + "/v1"
+
+	if err := c.wait(ctx); err != nil {
+		c.stop()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// wait polls the health endpoint until FlareSolverr is ready.
+func (c *container) wait(ctx context.Context) error {
+	hc := New(c.endpoint, 5*time.Second, http.DefaultClient)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		err := hc.Health(ctx)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("FlareSolverr is not ready: %w", err)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *container) stop() {
+	_ = exec.Command(c.runtime, "stop", c.id).Run()
+}
@@ -0,0 +1,46 @@
+package flaresolverr
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type requestOptionsKey struct{}
+
+// ContextWithRequestOptions returns a copy of ctx carrying opts, applied to every command
+// the client runs with it. This sets per-request options where the call cannot change,
+// such as within the transport of NewTransport or a library built on the client.
+//
+// Options passed to a method take precedence over the ones carried by its context,
+// and options added to a derived context take precedence over the ones of its parent.
+// A session passed as argument to Get or Post takes precedence over any session option.
+func ContextWithRequestOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	parent, _ := ctx.Value(requestOptionsKey{}).([]RequestOption)
+	return context.WithValue(ctx, requestOptionsKey{}, append(slices.Clip(parent), opts...))
+}
+
+// ContextWithProxy returns a copy of ctx making the requests through the given proxy, see ContextWithRequestOptions.
+func ContextWithProxy(ctx context.Context, proxy string) context.Context {
+	return ContextWithRequestOptions(ctx, WithProxy(proxy))
+}
+
+// ContextWithSession returns a copy of ctx making the requests made without session within
+// the given session, see ContextWithRequestOptions.
+func ContextWithSession(ctx context.Context, session uuid.UUID) context.Context {
+	return ContextWithRequestOptions(ctx, WithSession(session))
+}
+
+// ContextWithMaxTimeout returns a copy of ctx giving FlareSolverr d to solve the challenges,
+// see ContextWithRequestOptions and WithMaxTimeout.
+func ContextWithMaxTimeout(ctx context.Context, d time.Duration) context.Context {
+	return ContextWithRequestOptions(ctx, WithMaxTimeout(d))
+}
+
+// contextRequestOptions returns the options carried by ctx.
+func contextRequestOptions(ctx context.Context) []RequestOption {
+	opts, _ := ctx.Value(requestOptionsKey{}).([]RequestOption)
+	return opts
+}
@@ -0,0 +1,123 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestContextWithRequestOptions(t *testing.T) {
+	var mu sync.Mutex
+	var got *flaresolverrCommand
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = cmd
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+
+	ctxSession, argSession, optSession := uuid.New(), uuid.New(), uuid.New()
+	ctx := ContextWithMaxTimeout(ContextWithSession(ContextWithProxy(context.Background(), "http://outer:8080"), ctxSession), 5*time.Second)
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		session        uuid.UUID
+		opts           []RequestOption
+		wantProxy      string
+		wantSession    string
+		wantMaxTimeout int
+	}{
+		{
+			name:           "Context",
+			ctx:            ctx,
+			wantProxy:      "http://outer:8080",
+			wantSession:    ctxSession.String(),
+			wantMaxTimeout: 5000,
+		},
+		{
+			name:           "Derived context",
+			ctx:            ContextWithProxy(ctx, "http://inner:8080"),
+			wantProxy:      "http://inner:8080",
+			wantSession:    ctxSession.String(),
+			wantMaxTimeout: 5000,
+		},
+		{
+			name:           "Explicit options",
+			ctx:            ctx,
+			opts:           []RequestOption{WithProxy("http://explicit:8080"), WithSession(optSession), WithMaxTimeout(2 * time.Second)},
+			wantProxy:      "http://explicit:8080",
+			wantSession:    optSession.String(),
+			wantMaxTimeout: 2000,
+		},
+		{
+			name:           "Session argument",
+			ctx:            ctx,
+			session:        argSession,
+			opts:           []RequestOption{WithSession(optSession)},
+			wantProxy:      "http://outer:8080",
+			wantSession:    argSession.String(),
+			wantMaxTimeout: 5000,
+		},
+		{
+			name:           "No options",
+			ctx:            context.Background(),
+			wantMaxTimeout: 1000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := srv.GetWithOptions(tt.ctx, "https://example.com", tt.session, tt.opts...); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if got.Proxy != tt.wantProxy || got.Session != tt.wantSession || got.MaxTimeout != tt.wantMaxTimeout {
+				t.Errorf("Get() sent proxy %q, session %q, maxTimeout %d, want %q, %q, %d",
+					got.Proxy, got.Session, got.MaxTimeout, tt.wantProxy, tt.wantSession, tt.wantMaxTimeout)
+			}
+		})
+	}
+}
+
+func TestContextWithRequestOptions_transport(t *testing.T) {
+	var mu sync.Mutex
+	var proxies []string
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+		proxies = append(proxies, cmd.Proxy)
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+
+	target := &http.Client{Transport: NewTransport(c, roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{"Cf-Mitigated": {"challenge"}}, Body: http.NoBody, Request: req}, nil
+	}))}
+
+	req, err := http.NewRequestWithContext(ContextWithProxy(context.Background(), "http://proxy:8080"), http.MethodGet, "https://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := target.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if len(proxies) != 1 || proxies[0] != "http://proxy:8080" {
+		t.Errorf("solved through proxies %q, want the context proxy", proxies)
+	}
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
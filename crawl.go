@@ -0,0 +1,397 @@
+package flaresolverr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxRobotsSize is the size of robots.txt files and sitemaps read by a Crawler.
+const maxRobotsSize = 512 << 10
+
+// CrawledPage is a page solved by a Crawler.
+type CrawledPage struct {
+	// URL is the crawled URL.
+	URL string
+
+	// Depth is the number of links followed from a seed to reach the page.
+	// Seeds and sitemap pages have a depth of 0.
+	Depth int
+
+	// Response is the FlareSolverr response, set when Err is not.
+	Response *Response
+	Err      error
+}
+
+// CrawlOption configures a Crawler.
+type CrawlOption func(*Crawler)
+
+// WithCrawlUserAgent sets the user agent matched against the robots.txt groups.
+// Only the * group applies by default.
+func WithCrawlUserAgent(userAgent string) CrawlOption {
+	return func(cr *Crawler) {
+		cr.userAgent = userAgent
+	}
+}
+
+// WithCrawlDelay waits at least d between two pages of the same site.
+// A longer robots.txt Crawl-delay takes precedence.
+func WithCrawlDelay(d time.Duration) CrawlOption {
+	return func(cr *Crawler) {
+		cr.delay = d
+	}
+}
+
+// WithCrawlMaxPages stops the crawl after n pages. Values below 1 mean no limit.
+func WithCrawlMaxPages(n int) CrawlOption {
+	return func(cr *Crawler) {
+		cr.maxPages = n
+	}
+}
+
+// WithCrawlMaxDepth follows links up to n hops from the seeds. Negative values mean no limit, the default.
+func WithCrawlMaxDepth(n int) CrawlOption {
+	return func(cr *Crawler) {
+		cr.maxDepth = n
+	}
+}
+
+// WithCrawlFilter only crawls the URLs for which keep returns true.
+// By default, only the hosts of the seeds are crawled.
+func WithCrawlFilter(keep func(u *url.URL) bool) CrawlOption {
+	return func(cr *Crawler) {
+		cr.filter = keep
+	}
+}
+
+// WithCrawlSitemaps also crawls the pages listed by the sitemaps declared in robots.txt.
+func WithCrawlSitemaps() CrawlOption {
+	return func(cr *Crawler) {
+		cr.sitemaps = true
+	}
+}
+
+// WithRobotsClient fetches robots.txt files and sitemaps directly with httpClient,
+// instead of through FlareSolverr.
+func WithRobotsClient(httpClient *http.Client) CrawlOption {
+	return func(cr *Crawler) {
+		cr.httpClient = httpClient
+	}
+}
+
+// WithCrawlSession crawls within the given session.
+func WithCrawlSession(session uuid.UUID) CrawlOption {
+	return func(cr *Crawler) {
+		cr.session = session
+	}
+}
+
+// WithCrawlRequestOptions applies opts to every request of the crawl.
+func WithCrawlRequestOptions(opts ...RequestOption) CrawlOption {
+	return func(cr *Crawler) {
+		cr.request = append(cr.request, opts...)
+	}
+}
+
+// Crawler crawls sites through FlareSolverr, following their links
+// while respecting their robots.txt rules and crawl delays.
+// Pages are crawled one at a time, breadth first. A Crawler is not safe for concurrent use.
+//
+//	cr := flaresolverr.NewCrawler(c, []string{"https://example.com/"}, flaresolverr.WithCrawlMaxPages(100))
+//	for cr.Next(ctx) {
+//		page := cr.Page()
+//		...
+//	}
+//	if err := cr.Err(); err != nil {
+//		return err
+//	}
+type Crawler struct {
+	c          Client
+	userAgent  string
+	delay      time.Duration
+	maxPages   int
+	maxDepth   int
+	filter     func(u *url.URL) bool
+	sitemaps   bool
+	httpClient *http.Client
+	session    uuid.UUID
+	request    []RequestOption
+	clock      Clock
+
+	queue  []crawlItem
+	seen   map[string]bool
+	robots map[string]*robots
+	// robotsErr holds the error fetching the robots.txt of the sites which could not be crawled.
+	robotsErr map[string]error
+	last      map[string]time.Time
+	pages     int
+	page      *CrawledPage
+	err       error
+}
+
+type crawlItem struct {
+	url   *url.URL
+	depth int
+}
+
+// NewCrawler returns a Crawler starting from seeds.
+func NewCrawler(c Client, seeds []string, opts ...CrawlOption) *Crawler {
+	cr := &Crawler{
+		c:         c,
+		userAgent: "*",
+		maxDepth:  -1,
+		clock:     clockOf(c),
+		seen:      make(map[string]bool),
+		robots:    make(map[string]*robots),
+		robotsErr: make(map[string]error),
+		last:      make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		opt(cr)
+	}
+
+	if cr.filter == nil {
+		hosts := make(map[string]bool)
+		for _, seed := range seeds {
+			if u, err := url.Parse(seed); err == nil {
+				hosts[strings.ToLower(u.Hostname())] = true
+			}
+		}
+		cr.filter = func(u *url.URL) bool { return hosts[strings.ToLower(u.Hostname())] }
+	}
+
+	for _, seed := range seeds {
+		cr.enqueue(seed, 0)
+	}
+
+	return cr
+}
+
+// Next crawls the next page, which is then returned by Page.
+// It returns false when the crawl is over, or failed with the error returned by Err.
+func (cr *Crawler) Next(ctx context.Context) bool {
+	cr.page = nil
+	for cr.err == nil && len(cr.queue) > 0 && (cr.maxPages < 1 || cr.pages < cr.maxPages) {
+		item := cr.queue[0]
+		cr.queue = cr.queue[1:]
+
+		rules, err := cr.robotsFor(ctx, item.url)
+		if ctx.Err() != nil {
+			cr.err = ctx.Err()
+			return false
+		}
+
+		// the pages of a site whose robots.txt cannot be fetched fail, the other sites are crawled
+		if err != nil {
+			cr.pages++
+			cr.page = &CrawledPage{URL: item.url.String(), Depth: item.depth, Err: err}
+			return true
+		}
+
+		if !rules.allowed(item.url) {
+			continue
+		}
+
+		if err := cr.wait(ctx, item.url, rules.delay); err != nil {
+			cr.err = err
+			return false
+		}
+
+		resp, err := cr.c.GetWithOptions(ctx, item.url.String(), cr.session, cr.request...)
+		cr.last[origin(item.url)] = cr.clock.Now()
+		if ctx.Err() != nil {
+			cr.err = ctx.Err()
+			return false
+		}
+
+		cr.pages++
+		cr.page = &CrawledPage{URL: item.url.String(), Depth: item.depth, Response: resp, Err: err}
+		if err == nil && resp.Solution != nil && (cr.maxDepth < 0 || item.depth < cr.maxDepth) {
+			links, _ := resp.Solution.Links()
+			for _, link := range links {
+				cr.enqueue(link, item.depth+1)
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// Page returns the page crawled by the last call to Next.
+func (cr *Crawler) Page() *CrawledPage {
+	return cr.page
+}
+
+// Err returns the error which stopped the crawl, such as the context being done.
+// Pages which failed to be solved, or whose robots.txt could not be fetched, do not stop the crawl:
+// their error is set in CrawledPage.Err.
+func (cr *Crawler) Err() error {
+	return cr.err
+}
+
+func (cr *Crawler) enqueue(raw string, depth int) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return
+	}
+
+	u.Fragment, u.RawFragment = "", ""
+	if cr.seen[u.String()] || !cr.filter(u) {
+		return
+	}
+
+	cr.seen[u.String()] = true
+	cr.queue = append(cr.queue, crawlItem{url: u, depth: depth})
+}
+
+// wait blocks until the crawl delay of the site of u has elapsed since its last page.
+func (cr *Crawler) wait(ctx context.Context, u *url.URL, delay time.Duration) error {
+	delay = max(delay, cr.delay)
+	last, ok := cr.last[origin(u)]
+	if !ok || delay <= 0 {
+		return nil
+	}
+
+	timer := cr.clock.NewTimer(last.Add(delay).Sub(cr.clock.Now()))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
+
+// robotsFor returns the robots.txt rules of the site of u, fetching them on first use.
+// A missing robots.txt allows everything, an unavailable one disallows everything.
+func (cr *Crawler) robotsFor(ctx context.Context, u *url.URL) (*robots, error) {
+	site := origin(u)
+	if rules, ok := cr.robots[site]; ok {
+		return rules, nil
+	}
+	if err, ok := cr.robotsErr[site]; ok {
+		return nil, err
+	}
+
+	status, body, err := cr.fetch(ctx, site+"/robots.txt")
+	if err != nil {
+		err = fmt.Errorf("cannot fetch robots.txt of %s: %w", site, err)
+		if ctx.Err() == nil {
+			cr.robotsErr[site] = err
+		}
+		return nil, err
+	}
+
+	rules := allowAll
+	switch {
+	case status >= http.StatusInternalServerError:
+		rules = disallowAll
+	case status < http.StatusBadRequest:
+		rules = parseRobots(plainText(body), cr.userAgent)
+	}
+	cr.robots[site] = rules
+
+	if cr.sitemaps {
+		for _, sitemap := range rules.sitemaps {
+			if err := cr.crawlSitemap(ctx, sitemap, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return rules, nil
+}
+
+// crawlSitemap enqueues the pages listed by a sitemap, following sitemap indexes once.
+func (cr *Crawler) crawlSitemap(ctx context.Context, sitemap string, nesting int) error {
+	status, body, err := cr.fetch(ctx, sitemap)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// a broken sitemap does not prevent crawling the site
+		return nil
+	}
+
+	if status >= http.StatusBadRequest {
+		return nil
+	}
+
+	pages, sitemaps := sitemapLocations(body)
+	for _, page := range pages {
+		cr.enqueue(page, 0)
+	}
+
+	if nesting > 0 {
+		return nil
+	}
+
+	for _, nested := range sitemaps {
+		if err := cr.crawlSitemap(ctx, nested, nesting+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetch gets a robots.txt file or a sitemap, directly or through FlareSolverr.
+func (cr *Crawler) fetch(ctx context.Context, u string) (int, string, error) {
+	if cr.httpClient == nil {
+		resp, err := cr.c.GetWithOptions(ctx, u, cr.session, cr.request...)
+		if err != nil {
+			return 0, "", err
+		}
+
+		if resp.Solution == nil {
+			return http.StatusNotFound, "", nil
+		}
+		defer resp.Solution.Close()
+
+		// the body may be spilled to disk, see WithBodySpill
+		content, err := resp.Solution.Body()
+		if err != nil {
+			return 0, "", err
+		}
+		defer content.Close()
+
+		body, err := io.ReadAll(io.LimitReader(content, maxRobotsSize))
+		if err != nil {
+			return 0, "", err
+		}
+
+		return resp.Solution.Status, string(body), nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, "", err
+	}
+
+	resp, err := cr.httpClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRobotsSize))
+	if err != nil {
+		return 0, "", err
+	}
+
+	return resp.StatusCode, string(body), nil
+}
+
+// origin returns the scheme and host of u.
+func origin(u *url.URL) string {
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,251 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testSite serves the pages of a site through the FlareSolverr test server.
+func testSite(t *testing.T, pages map[string]string) (*client, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var requested []string
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		requested = append(requested, cmd.URL)
+		mu.Unlock()
+
+		body, ok := pages[cmd.URL]
+		if !ok {
+			return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Status: http.StatusNotFound}}
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Status: http.StatusOK, Response: body}}
+	})
+
+	return c, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requested...)
+	}
+}
+
+func crawl(t *testing.T, cr *Crawler) []string {
+	t.Helper()
+
+	var crawled []string
+	for cr.Next(context.Background()) {
+		page := cr.Page()
+		if page.Err != nil {
+			t.Errorf("Next() page %s error = %v", page.URL, page.Err)
+		}
+		crawled = append(crawled, fmt.Sprintf("%d %s", page.Depth, page.URL))
+	}
+
+	if err := cr.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	return crawled
+}
+
+func TestCrawler(t *testing.T) {
+	c, _ := testSite(t, map[string]string{
+		"https://example.com/robots.txt": `<html><body><pre>User-agent: *
+Disallow: /admin
+Sitemap: https://example.com/sitemap.xml</pre></body></html>`,
+		"https://example.com/sitemap.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>https://example.com/hidden</loc></url></urlset>`,
+		"https://example.com/":       `<a href="/a">a</a> <a href="/admin/">admin</a> <a href="https://other.com/">other</a> <a href="mailto:foo@example.com">mail</a>`,
+		"https://example.com/a":      `<a href="/">home</a> <a href="b#top">b</a>`,
+		"https://example.com/b":      `<a href="/c">c</a>`,
+		"https://example.com/c":      `end`,
+		"https://example.com/hidden": `<a href="/c">c</a>`,
+	})
+
+	tests := []struct {
+		name string
+		opts []CrawlOption
+		want []string
+	}{
+		{
+			name: "links",
+			want: []string{"0 https://example.com/", "1 https://example.com/a", "2 https://example.com/b", "3 https://example.com/c"},
+		},
+		{
+			name: "max depth",
+			opts: []CrawlOption{WithCrawlMaxDepth(1)},
+			want: []string{"0 https://example.com/", "1 https://example.com/a"},
+		},
+		{
+			name: "max pages",
+			opts: []CrawlOption{WithCrawlMaxPages(3)},
+			want: []string{"0 https://example.com/", "1 https://example.com/a", "2 https://example.com/b"},
+		},
+		{
+			name: "sitemaps",
+			opts: []CrawlOption{WithCrawlSitemaps(), WithCrawlMaxDepth(1)},
+			want: []string{"0 https://example.com/", "0 https://example.com/hidden", "1 https://example.com/a", "1 https://example.com/c"},
+		},
+		{
+			name: "filter",
+			opts: []CrawlOption{WithCrawlFilter(func(u *url.URL) bool { return u.Host == "example.com" && u.Path != "/b" })},
+			want: []string{"0 https://example.com/", "1 https://example.com/a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := crawl(t, NewCrawler(c, []string{"https://example.com/"}, tt.opts...))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Crawler pages mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCrawler_robots(t *testing.T) {
+	t.Run("crawl delay", func(t *testing.T) {
+		c, _ := testSite(t, map[string]string{
+			"https://example.com/robots.txt": "User-agent: *\nCrawl-delay: 0.05\n",
+			"https://example.com/":           `<a href="/a">a</a>`,
+		})
+
+		start := time.Now()
+		got := crawl(t, NewCrawler(c, []string{"https://example.com/"}))
+		if elapsed := time.Since(start); len(got) != 2 || elapsed < 50*time.Millisecond {
+			t.Errorf("Crawler crawled %v in %v, want 2 pages in at least 50ms", got, elapsed)
+		}
+	})
+
+	t.Run("user agent", func(t *testing.T) {
+		c, _ := testSite(t, map[string]string{
+			"https://example.com/robots.txt": "User-agent: *\nDisallow: /\n\nUser-agent: mybot\nAllow: /\n",
+			"https://example.com/":           `home`,
+		})
+
+		if got := crawl(t, NewCrawler(c, []string{"https://example.com/"})); len(got) != 0 {
+			t.Errorf("Crawler crawled %v, want nothing", got)
+		}
+
+		if got := crawl(t, NewCrawler(c, []string{"https://example.com/"}, WithCrawlUserAgent("MyBot/1.0"))); len(got) != 1 {
+			t.Errorf("Crawler crawled %v, want the home page", got)
+		}
+	})
+
+	t.Run("direct", func(t *testing.T) {
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/robots.txt" {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+		}))
+		t.Cleanup(target.Close)
+
+		c, requested := testSite(t, map[string]string{
+			target.URL + "/": `<a href="/private">private</a>`,
+		})
+
+		got := crawl(t, NewCrawler(c, []string{target.URL + "/"}, WithRobotsClient(target.Client())))
+		if diff := cmp.Diff([]string{"0 " + target.URL + "/"}, got); diff != "" {
+			t.Errorf("Crawler pages mismatch (-want +got):\n%s", diff)
+		}
+
+		for _, u := range requested() {
+			if strings.HasSuffix(u, "/robots.txt") {
+				t.Errorf("Crawler requested %s through FlareSolverr", u)
+			}
+		}
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+			return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Status: http.StatusServiceUnavailable}}
+		})
+
+		if got := crawl(t, NewCrawler(c, []string{"https://example.com/"})); len(got) != 0 {
+			t.Errorf("Crawler crawled %v, want nothing", got)
+		}
+	})
+}
+
+func TestCrawler_Err(t *testing.T) {
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		if strings.HasPrefix(cmd.URL, "https://example.com/") {
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: maximum timeout reached"}
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Status: http.StatusOK}}
+	})
+
+	// the site whose robots.txt fails does not stop the crawl of the other ones
+	cr := NewCrawler(c, []string{"https://example.com/", "https://example.com/a", "https://example.org/"})
+	var got []string
+	for cr.Next(context.Background()) {
+		page := cr.Page()
+		if strings.HasPrefix(page.URL, "https://example.com/") && !errors.Is(page.Err, ErrRequestTimeout) {
+			t.Errorf("Next() page %s error = %v, want %v", page.URL, page.Err, ErrRequestTimeout)
+		}
+		got = append(got, page.URL)
+	}
+
+	if err := cr.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+	if diff := cmp.Diff([]string{"https://example.com/", "https://example.com/a", "https://example.org/"}, got); diff != "" {
+		t.Errorf("Crawler pages mismatch (-want +got):\n%s", diff)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cr = NewCrawler(c, []string{"https://example.net/"})
+	if cr.Next(ctx) {
+		t.Fatalf("Next() = true, want false")
+	}
+	if err := cr.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Err() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestCrawler_spilledRobots(t *testing.T) {
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		body := `<a href="/private">private</a>`
+		if cmd.URL == "https://example.com/robots.txt" {
+			body = "User-agent: *\nDisallow: /private\n"
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Status: http.StatusOK, Response: body}}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithBodySpill(1, t.TempDir()))
+
+	got := crawl(t, NewCrawler(c, []string{"https://example.com/"}))
+	if diff := cmp.Diff([]string{"0 https://example.com/"}, got); diff != "" {
+		t.Errorf("Crawler pages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCrawler_clock(t *testing.T) {
+	srv, _ := testSite(t, map[string]string{
+		"https://example.com/robots.txt": "User-agent: *\nCrawl-delay: 60\n",
+		"https://example.com/":           `<a href="/a">a</a>`,
+	})
+	clock := newFakeClock()
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithClock(clock))
+
+	got := crawl(t, NewCrawler(c, []string{"https://example.com/"}))
+	if len(got) != 2 {
+		t.Errorf("Crawler crawled %v, want 2 pages", got)
+	}
+
+	if diff := cmp.Diff([]time.Duration{time.Minute}, clock.Waited()); diff != "" {
+		t.Errorf("waited mismatch (-want +got):\n%s", diff)
+	}
+}
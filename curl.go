@@ -0,0 +1,42 @@
+package flaresolverr
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CurlCommand returns a curl command requesting u with the solved cookies and user agent,
+// to check by hand whether the clearance is accepted. The solution URL is used if u is empty.
+func (s *ResponseSolution) CurlCommand(u string) string {
+	return s.Clearance().CurlCommand(u)
+}
+
+// CurlCommand returns a curl command requesting u with the clearance cookies and user agent.
+// Only the cookies matching the host of u are sent. The clearance URL is used if u is empty.
+func (c *Clearance) CurlCommand(u string) string {
+	if u == "" {
+		u = c.URL
+	}
+
+	args := []string{"curl", shellQuote(u)}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return strings.Join(args, " ")
+	}
+	c.Apply(req)
+
+	if userAgent := req.Header.Get("User-Agent"); userAgent != "" {
+		args = append(args, "-A", shellQuote(userAgent))
+	}
+
+	if cookie := req.Header.Get("Cookie"); cookie != "" {
+		args = append(args, "-b", shellQuote(cookie))
+	}
+
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
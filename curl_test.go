@@ -0,0 +1,43 @@
+package flaresolverr
+
+import "testing"
+
+func TestResponseSolution_CurlCommand(t *testing.T) {
+	solution := &ResponseSolution{
+		URL:       "https://www.example.com/page",
+		UserAgent: "Mozilla/5.0 (it's me)",
+		Cookies: []Cookie{
+			{Name: "cf_clearance", Value: "solved", Domain: ".example.com", Path: "/"},
+			{Name: "other", Value: "bar", Domain: "other.com", Path: "/"},
+			{Name: "session", Value: "foo", Path: "/"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		u    string
+		want string
+	}{
+		{
+			name: "solution URL",
+			want: `curl 'https://www.example.com/page' -A 'Mozilla/5.0 (it'\''s me)' -b 'cf_clearance=solved; session=foo'`,
+		},
+		{
+			name: "other host",
+			u:    "https://other.com/?a=1&b=2",
+			want: `curl 'https://other.com/?a=1&b=2' -A 'Mozilla/5.0 (it'\''s me)' -b 'other=bar; session=foo'`,
+		},
+		{
+			name: "invalid URL",
+			u:    "://invalid",
+			want: `curl '://invalid'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := solution.CurlCommand(tt.u); got != tt.want {
+				t.Errorf("CurlCommand() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package flaresolverr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// responseFields are the lower-cased top-level fields modeled by Response.
+var responseFields = jsonFields(reflect.TypeOf(Response{}))
+
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "-" && name != "" {
+			fields[strings.ToLower(name)] = true
+		}
+	}
+
+	return fields
+}
+
+// fieldScanner walks the top-level fields of a JSON object without decoding their values,
+// to look at the fields the codec does not model in the same scan.
+type fieldScanner struct {
+	data    []byte
+	i       int
+	started bool
+}
+
+// next returns the next top-level key and its raw value, or false at the end of the object
+// or on malformed data.
+func (s *fieldScanner) next() (key string, value []byte, ok bool) {
+	data := s.data
+	sep := byte(',')
+	if !s.started {
+		s.started, sep = true, '{'
+	}
+
+	s.skipSpace()
+	if s.i == len(data) || data[s.i] != sep {
+		return "", nil, false
+	}
+	s.i++
+
+	s.skipSpace()
+	if s.i == len(data) || data[s.i] != '"' {
+		return "", nil, false
+	}
+
+	end := skipString(data, s.i)
+	if end < 0 {
+		return "", nil, false
+	}
+	quoted := data[s.i:end]
+	s.i = end
+
+	s.skipSpace()
+	if s.i == len(data) || data[s.i] != ':' {
+		return "", nil, false
+	}
+	s.i++
+	s.skipSpace()
+
+	start, end := s.i, skipJSONValue(data, s.i)
+	if end < 0 {
+		return "", nil, false
+	}
+	s.i = end
+
+	if bytes.IndexByte(quoted, '\\') < 0 {
+		key = string(quoted[1 : len(quoted)-1])
+	} else if err := json.Unmarshal(quoted, &key); err != nil {
+		return "", nil, false
+	}
+
+	return key, data[start:s.i], true
+}
+
+func (s *fieldScanner) skipSpace() {
+	for s.i < len(s.data) {
+		switch s.data[s.i] {
+		case ' ', '\t', '\n', '\r':
+			s.i++
+		default:
+			return
+		}
+	}
+}
+
+// skipString returns the end of the JSON string starting at i, or -1.
+func skipString(data []byte, i int) int {
+	for j := i + 1; j < len(data); j++ {
+		switch data[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		}
+	}
+
+	return -1
+}
+
+// skipJSONValue returns the end of the JSON value starting at i, or -1.
+func skipJSONValue(data []byte, i int) int {
+	if i >= len(data) {
+		return -1
+	}
+
+	switch data[i] {
+	case '"':
+		return skipString(data, i)
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(data); j++ {
+			switch data[j] {
+			case '"':
+				end := skipString(data, j)
+				if end < 0 {
+					return -1
+				}
+				j = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					return j + 1
+				}
+			}
+		}
+		return -1
+	default:
+		j := i
+		for j < len(data) && strings.IndexByte(",}] \t\r\n", data[j]) < 0 {
+			j++
+		}
+		if j == i {
+			return -1
+		}
+		return j
+	}
+}
+
+// scanFields returns the top-level fields of a response not modeled by Response, or nil,
+// and sets Response.OtherSessions. data is only scanned once, the values are not decoded
+// except the sessions, and the kept values are copied as data is reused.
+func scanFields(data []byte, response *Response) map[string]json.RawMessage {
+	var fields map[string]json.RawMessage
+	s := fieldScanner{data: data}
+	for {
+		key, value, ok := s.next()
+		if !ok {
+			return fields
+		}
+
+		lower := strings.ToLower(key)
+		if lower == "sessions" {
+			response.OtherSessions = otherSessions(value)
+		}
+		if responseFields[lower] {
+			continue
+		}
+
+		if fields == nil {
+			fields = make(map[string]json.RawMessage)
+		}
+		fields[key] = append(json.RawMessage(nil), value...)
+	}
+}
+
+// decode decodes a FlareSolverr response, rejecting unknown fields with WithStrictDecoding,
+// or keeping the unknown top-level ones in Response.Extra.
+func (c *client) decode(data []byte, response *Response) error {
+	switch {
+	case c.strictDecoding && c.codec == nil:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(response); err != nil {
+			return err
+		}
+	case c.codec != nil:
+		if err := c.codec.Unmarshal(data, response); err != nil {
+			return err
+		}
+	default:
+		if err := json.Unmarshal(data, response); err != nil {
+			return err
+		}
+	}
+
+	extra := scanFields(data, response)
+	if !c.strictDecoding {
+		response.Extra = extra
+		return nil
+	}
+
+	// the codec may not reject unknown fields itself
+	if len(extra) > 0 {
+		keys := make([]string, 0, len(extra))
+		for key := range extra {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return fmt.Errorf("json: unknown field %q", keys[0])
+	}
+
+	return nil
+}
+
+// decodeBase64Body decodes the body of a solution requested with WithBase64Response.
+func decodeBase64Body(s *ResponseSolution) error {
+	if s == nil || s.Response == "" {
+		return nil
+	}
+
+	body, err := base64.StdEncoding.DecodeString(s.Response)
+	if err != nil {
+		return fmt.Errorf("%w: invalid base64 body: %v", ErrInvalidServerResponse, err)
+	}
+
+	s.Response = string(body)
+	return nil
+}
@@ -0,0 +1,140 @@
+package flaresolverr
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_client_decode(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		opts      []Option
+		wantErr   bool
+		wantExtra map[string]json.RawMessage
+	}{
+		{
+			name: "Known fields",
+			body: `{"status":"ok","message":"","solution":{"url":"https://example.com","status":200}}`,
+		},
+		{
+			name:      "Unknown fields",
+			body:      `{"status":"ok","Message":"","queueDepth":3,"debug":{"tabs":2}}`,
+			wantExtra: map[string]json.RawMessage{"queueDepth": json.RawMessage(`3`), "debug": json.RawMessage(`{"tabs":2}`)},
+		},
+		{
+			name:      "Unknown fields with codec",
+			body:      `{"status":"ok","queueDepth":3}`,
+			opts:      []Option{WithJSONCodec(stdCodec{})},
+			wantExtra: map[string]json.RawMessage{"queueDepth": json.RawMessage(`3`)},
+		},
+		{
+			name: "Strict known fields",
+			body: `{"status":"ok","message":"","solution":{"url":"https://example.com","status":200}}`,
+			opts: []Option{WithStrictDecoding()},
+		},
+		{
+			name:    "Strict unknown field",
+			body:    `{"status":"ok","queueDepth":3}`,
+			opts:    []Option{WithStrictDecoding()},
+			wantErr: true,
+		},
+		{
+			name:    "Strict unknown fields",
+			body:    `{"status":"ok","queueDepth":3,"debug":{"tabs":2}}`,
+			opts:    []Option{WithStrictDecoding()},
+			wantErr: true,
+		},
+		{
+			name:      "Unknown fields with escapes",
+			body:      `{"status":"ok","solution":{"url":"https://example.com","extra":"}{\""},"a\u0062c":[1,{"d":"]"}],"e":null}`,
+			wantExtra: map[string]json.RawMessage{"abc": json.RawMessage(`[1,{"d":"]"}]`), "e": json.RawMessage(`null`)},
+		},
+		{
+			name:    "Strict unknown nested field",
+			body:    `{"status":"ok","solution":{"url":"https://example.com","turnstileToken":"..."}}`,
+			opts:    []Option{WithStrictDecoding()},
+			wantErr: true,
+		},
+		{
+			name:    "Strict unknown field with codec",
+			body:    `{"status":"ok","queueDepth":3}`,
+			opts:    []Option{WithStrictDecoding(), WithJSONCodec(stdCodec{})},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := New(srv.URL, time.Second, srv.Client(), tt.opts...)
+			got, err := c.Get(context.Background(), "https://example.com", uuid.Nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tt.wantExtra, got.Extra); diff != "" {
+				t.Errorf("Get() extra mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_client_WithBase64Response(t *testing.T) {
+	body := "caf\xe9 \x00\xff"
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		response := body
+		if cmd.ReturnBase64 {
+			response = base64.StdEncoding.EncodeToString([]byte(body))
+		}
+		if cmd.URL == "https://example.com/invalid" {
+			response = "not base64!"
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Status: http.StatusOK, Response: response}}
+	})
+
+	profile := ProfileFlareSolverr
+	profile.Base64Response = true
+	tests := []struct {
+		name    string
+		opts    []Option
+		url     string
+		wantErr error
+	}{
+		{name: "Unsupported", url: "https://example.com", wantErr: ErrUnsupportedByServer},
+		{name: "Decoded", opts: []Option{WithProfile(profile)}, url: "https://example.com"},
+		{name: "Invalid", opts: []Option{WithProfile(profile)}, url: "https://example.com/invalid", wantErr: ErrInvalidServerResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(srv.baseURL, time.Second, srv.httpClient, tt.opts...)
+			got, err := c.GetWithOptions(context.Background(), tt.url, uuid.Nil, WithBase64Response())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Solution.Response != body {
+				t.Errorf("Get() body = %q, want %q", got.Solution.Response, body)
+			}
+		})
+	}
+}
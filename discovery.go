@@ -0,0 +1,67 @@
+package flaresolverr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrServerNotFound when no FlareSolverr server answers at the discovered URL.
+var ErrServerNotFound = errors.New("FlareSolverr server not found")
+
+// Discover returns the FlareSolverr API endpoint served at baseURL,
+// which can be the server root, e.g. "http://127.0.0.1:8191", or the endpoint itself.
+// The server index is probed to check FlareSolverr is listening.
+// Uses the default http client if not provided.
+func Discover(ctx context.Context, baseURL string, httpClient *http.Client) (string, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	endpoint, err := normalizeBaseURL(baseURL)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidBaseURL, err)
+	}
+
+	// the index is served next to the API path
+	u.Path = strings.TrimSuffix(u.Path, "/v1") + "/"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return "", fmt.Errorf("cannot make request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w at %s: %v", ErrServerNotFound, u.Redacted(), err)
+	}
+	defer resp.Body.Close()
+
+	var index struct {
+		Msg string `json:"msg"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&index); err != nil || !strings.Contains(index.Msg, "FlareSolverr") {
+		return "", fmt.Errorf("%w at %s: unexpected index %s", ErrServerNotFound, u.Redacted(), resp.Status)
+	}
+
+	return endpoint, nil
+}
+
+// NewWithDiscovery is like New, but discovers the API endpoint from baseURL with Discover.
+func NewWithDiscovery(ctx context.Context, baseURL string, timeout time.Duration, httpClient *http.Client, opts ...Option) (Client, error) {
+	endpoint, err := Discover(ctx, baseURL, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(endpoint, timeout, httpClient, opts...), nil
+}
@@ -0,0 +1,48 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"msg":"FlareSolverr is ready!","version":"3.3.21","userAgent":"Mozilla/5.0"}`))
+		case "/other/":
+			_, _ = w.Write([]byte(`<html></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		want    string
+		wantErr error
+	}{
+		{name: "Server root", baseURL: srv.URL, want: srv.URL + "/v1"},
+		{name: "Endpoint", baseURL: srv.URL + "/v1", want: srv.URL + "/v1"},
+		{name: "Not FlareSolverr", baseURL: srv.URL + "/other", wantErr: ErrServerNotFound},
+		{name: "Not found", baseURL: srv.URL + "/missing/v1", wantErr: ErrServerNotFound},
+		{name: "Invalid", baseURL: "foo.bar", wantErr: ErrInvalidBaseURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Discover(context.Background(), tt.baseURL, srv.Client())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Discover() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Discover() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package flaresolverr
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+type document struct {
+	once sync.Once
+	node *html.Node
+	err  error
+}
+
+// Document returns the parsed HTML body of the solution.
+// The body is parsed on first call only and the result is cached,
+// do not modify Response afterwards.
+func (s *ResponseSolution) Document() (*html.Node, error) {
+	s.document.once.Do(func() {
+		body, err := s.Body()
+		if err != nil {
+			s.document.err = err
+			return
+		}
+		defer body.Close()
+
+		s.document.node, s.document.err = html.Parse(body)
+	})
+
+	return s.document.node, s.document.err
+}
+
+func lookupAttr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && strings.EqualFold(a.Key, key) {
+			return a.Val, true
+		}
+	}
+
+	return "", false
+}
+
+func attr(n *html.Node, key string) string {
+	value, _ := lookupAttr(n, key)
+	return value
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	_, ok := lookupAttr(n, key)
+	return ok
+}
+
+func text(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+
+	return b.String()
+}
+
+// Links returns the URLs of the links found in the solution body, in document order.
+// They are resolved against the solution URL and their fragment is removed.
+func (s *ResponseSolution) Links() ([]string, error) {
+	doc, err := s.Document()
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse HTML: %w", err)
+	}
+
+	base, err := url.Parse(s.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid solution URL: %w", err)
+	}
+
+	var links []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && (n.DataAtom == atom.A || n.DataAtom == atom.Area) && hasAttr(n, "href") {
+			if link, err := base.Parse(strings.TrimSpace(attr(n, "href"))); err == nil {
+				link.Fragment, link.RawFragment = "", ""
+				links = append(links, link.String())
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	return links, nil
+}
+
+// findElement returns the first element of n, in document order, matching match.
+func findElement(n *html.Node, match func(n *html.Node) bool) *html.Node {
+	if n.Type == html.ElementNode && match(n) {
+		return n
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if found := findElement(child, match); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// find returns the first element of the solution body matching match, or nil.
+func (s *ResponseSolution) find(match func(n *html.Node) bool) *html.Node {
+	doc, err := s.Document()
+	if err != nil {
+		return nil
+	}
+
+	return findElement(doc, match)
+}
+
+// Title returns the title of the page, with the whitespace collapsed,
+// e.g. "Just a moment..." for a Cloudflare challenge page. Empty without title.
+func (s *ResponseSolution) Title() string {
+	title := s.find(func(n *html.Node) bool { return n.DataAtom == atom.Title })
+	if title == nil {
+		return ""
+	}
+
+	return strings.Join(strings.Fields(text(title)), " ")
+}
+
+// MetaRefresh returns the target and the delay of the meta refresh of the page.
+// The target is resolved against the solution URL, it is the page itself when the
+// refresh has no URL. ok is false when the page has no valid meta refresh.
+func (s *ResponseSolution) MetaRefresh() (target string, delay time.Duration, ok bool) {
+	meta := s.find(func(n *html.Node) bool {
+		return n.DataAtom == atom.Meta && strings.EqualFold(attr(n, "http-equiv"), "refresh")
+	})
+	if meta == nil {
+		return "", 0, false
+	}
+
+	delay, ref, ok := parseRefresh(attr(meta, "content"))
+	if !ok {
+		return "", 0, false
+	}
+
+	u, err := url.Parse(s.URL)
+	if err == nil {
+		u, err = u.Parse(ref)
+	}
+	if err != nil {
+		return "", 0, false
+	}
+
+	return u.String(), delay, true
+}
+
+// CanonicalURL returns the canonical URL of the page declared with <link rel="canonical">,
+// resolved against the solution URL. Empty without canonical link.
+func (s *ResponseSolution) CanonicalURL() string {
+	link := s.find(func(n *html.Node) bool {
+		return n.DataAtom == atom.Link && hasAttr(n, "href") && hasToken(attr(n, "rel"), "canonical")
+	})
+	if link == nil {
+		return ""
+	}
+
+	u, err := url.Parse(s.URL)
+	if err == nil {
+		u, err = u.Parse(strings.TrimSpace(attr(link, "href")))
+	}
+	if err != nil {
+		return ""
+	}
+
+	return u.String()
+}
+
+// hasToken reports whether the space separated list contains token, case-insensitively.
+func hasToken(list, token string) bool {
+	for _, t := range strings.Fields(list) {
+		if strings.EqualFold(t, token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseRefresh parses the content of a meta refresh, e.g. "5; url=/next".
+// The URL is empty when the page refreshes itself.
+func parseRefresh(content string) (delay time.Duration, ref string, ok bool) {
+	seconds := strings.TrimSpace(content)
+	if i := strings.IndexAny(seconds, ";,"); i >= 0 {
+		seconds, ref = seconds[:i], seconds[i+1:]
+	}
+
+	// browsers ignore the fractional part of the delay
+	seconds, _, _ = strings.Cut(strings.TrimSpace(seconds), ".")
+	n, err := strconv.Atoi(seconds)
+	if err != nil || n < 0 {
+		return 0, "", false
+	}
+
+	ref = strings.TrimSpace(ref)
+	if len(ref) >= 4 && strings.EqualFold(ref[:4], "url=") {
+		ref = strings.TrimSpace(ref[4:])
+	}
+
+	return time.Duration(n) * time.Second, strings.Trim(ref, `"'`), true
+}
@@ -0,0 +1,110 @@
+package flaresolverr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func TestResponseSolution_Document(t *testing.T) {
+	solution := &ResponseSolution{Response: "<html><head><title>Foo</title></head><body><p>bar</p></body></html>"}
+
+	doc, err := solution.Document()
+	if err != nil {
+		t.Fatalf("Document() error = %v", err)
+	}
+
+	var title *html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.DataAtom == atom.Title {
+			title = n
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	if title == nil || text(title) != "Foo" {
+		t.Errorf("Document() expected title Foo")
+	}
+
+	if again, _ := solution.Document(); again != doc {
+		t.Errorf("Document() expected cached document")
+	}
+}
+
+func TestResponseSolution_Links(t *testing.T) {
+	solution := &ResponseSolution{
+		URL:      "https://example.com/dir/page",
+		Response: `<a href="/a">a</a><a href="b#frag">b</a><a name="anchor">no href</a><map><area href="https://other.com/c"></map>`,
+	}
+
+	got, err := solution.Links()
+	if err != nil {
+		t.Fatalf("Links() error = %v", err)
+	}
+
+	want := []string{"https://example.com/a", "https://example.com/dir/b", "https://other.com/c"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Links() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestResponseSolution_metadata(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		wantTitle     string
+		wantRefresh   string
+		wantDelay     time.Duration
+		wantRefreshOK bool
+		wantCanonical string
+	}{
+		{name: "empty", body: ""},
+		{
+			name:      "challenge",
+			body:      "<html><head><title>\n  Just a\tmoment...\n</title></head></html>",
+			wantTitle: "Just a moment...",
+		},
+		{
+			name:          "metadata",
+			body:          `<html><head><title>Home</title><meta http-equiv="REFRESH" content="5; URL='/next'"><link rel="alternate canonical" href="/home"></head></html>`,
+			wantTitle:     "Home",
+			wantRefresh:   "https://example.com/next",
+			wantDelay:     5 * time.Second,
+			wantRefreshOK: true,
+			wantCanonical: "https://example.com/home",
+		},
+		{
+			name:          "self refresh",
+			body:          `<meta http-equiv="refresh" content="30">`,
+			wantRefresh:   "https://example.com/page",
+			wantDelay:     30 * time.Second,
+			wantRefreshOK: true,
+		},
+		{name: "invalid refresh", body: `<meta http-equiv="refresh" content="soon">`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ResponseSolution{URL: "https://example.com/page", Response: tt.body}
+
+			if got := s.Title(); got != tt.wantTitle {
+				t.Errorf("Title() = %q, want %q", got, tt.wantTitle)
+			}
+
+			target, delay, ok := s.MetaRefresh()
+			if target != tt.wantRefresh || delay != tt.wantDelay || ok != tt.wantRefreshOK {
+				t.Errorf("MetaRefresh() = %q, %v, %v, want %q, %v, %v", target, delay, ok, tt.wantRefresh, tt.wantDelay, tt.wantRefreshOK)
+			}
+
+			if got := s.CanonicalURL(); got != tt.wantCanonical {
+				t.Errorf("CanonicalURL() = %q, want %q", got, tt.wantCanonical)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package flaresolverr
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// siteSessionIdle is how long the site sessions created for SessionPolicyPerDomain are kept idle,
+// when WithSessionPerDomain is not used.
+const siteSessionIdle = 5 * time.Minute
+
+// SessionPolicy is how the requests made without session to a domain are routed, see DomainConfig.
+type SessionPolicy int
+
+const (
+	// SessionPolicyDefault routes the requests as the client does for every domain.
+	SessionPolicyDefault SessionPolicy = iota
+
+	// SessionPolicyNone makes the requests in a temporary browser, even with WithSessionPerDomain.
+	SessionPolicyNone
+
+	// SessionPolicyPerDomain routes the requests to a session of their site, as WithSessionPerDomain does.
+	// Without WithSessionPerDomain, site sessions are destroyed once idle for 5 minutes.
+	SessionPolicyPerDomain
+)
+
+// DomainConfig is the configuration of the requests made by Get and Post to a domain, see WithDomainConfig.
+// Zero fields keep the client behavior, and request options take precedence.
+type DomainConfig struct {
+	// Timeout gives FlareSolverr this long to solve the challenges, see WithMaxTimeout.
+	Timeout time.Duration
+
+	// Proxy is the proxy of the requests, see WithProxy.
+	Proxy string
+
+	// Session is how the requests made without session are routed.
+	Session SessionPolicy
+
+	// CacheTTL overrides the TTL of WithResponseCache, a negative one disables the cache.
+	CacheTTL time.Duration
+
+	// PolitenessDelay is the minimum delay between two requests to the same hostname, see WithPoliteness.
+	PolitenessDelay time.Duration
+}
+
+// WithDomainConfig configures the requests made by Get and Post to domain and its subdomains,
+// e.g. "example.com", so the options of every site do not have to be passed on every request.
+// The configuration of the closest parent domain of a hostname is used.
+func WithDomainConfig(domain string, config DomainConfig) Option {
+	return func(c *client) {
+		if c.domains == nil {
+			c.domains = make(map[string]DomainConfig)
+		}
+		c.domains[strings.TrimPrefix(strings.ToLower(domain), "*.")] = config
+	}
+}
+
+// domainConfig returns the configuration of the closest parent domain of the hostname of u.
+func (c *client) domainConfig(u string) (DomainConfig, bool) {
+	for domain := hostname(u); domain != ""; {
+		if config, ok := c.domains[domain]; ok {
+			return config, true
+		}
+
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			break
+		}
+		domain = parent
+	}
+
+	return DomainConfig{}, false
+}
+
+// requestOptionsFor applies the options carried by ctx, then the configuration of the domain of u, then opts.
+func (c *client) requestOptionsFor(ctx context.Context, u string, opts []RequestOption) *requestOptions {
+	config, ok := c.domainConfig(u)
+	if !ok {
+		return newRequestOptions(ctx, opts)
+	}
+
+	return newRequestOptions(ctx, append(config.options(), opts...))
+}
+
+// options returns the request options applying the configuration.
+func (config DomainConfig) options() []RequestOption {
+	opts := []RequestOption{func(o *requestOptions) {
+		o.sessionPolicy = config.Session
+		o.cacheTTL = config.CacheTTL
+	}}
+
+	if config.Timeout > 0 {
+		opts = append(opts, WithMaxTimeout(config.Timeout))
+	}
+	if config.Proxy != "" {
+		opts = append(opts, WithProxy(config.Proxy))
+	}
+
+	return opts
+}
+
+// useDomainConfigs sets up the subsystems needed by the domain configurations, once the options are applied.
+func (c *client) useDomainConfigs() {
+	for domain, config := range c.domains {
+		if config.PolitenessDelay > 0 {
+			if c.politeness == nil {
+				c.politeness = newPoliteness(0, nil)
+			}
+			c.politeness.perDomain[domain] = config.PolitenessDelay
+		}
+
+		if config.Session == SessionPolicyPerDomain && c.domainSessions == nil {
+			c.domainSessions = newDomainSessions(siteSessionIdle)
+			c.domainSessions.optIn = true
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_client_domainConfig(t *testing.T) {
+	c := New("http://localhost:8191/v1", time.Second, nil,
+		WithDomainConfig("example.com", DomainConfig{Proxy: "http://proxy:8080"}),
+		WithDomainConfig("*.API.example.com", DomainConfig{Proxy: "http://api-proxy:8080"}),
+	).(*client)
+
+	tests := []struct {
+		u         string
+		wantProxy string
+		wantOK    bool
+	}{
+		{u: "https://example.com/", wantProxy: "http://proxy:8080", wantOK: true},
+		{u: "https://www.example.com/page", wantProxy: "http://proxy:8080", wantOK: true},
+		{u: "https://v2.api.example.com/items", wantProxy: "http://api-proxy:8080", wantOK: true},
+		{u: "https://notexample.com/", wantOK: false},
+		{u: "https://example.org/", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.u, func(t *testing.T) {
+			got, ok := c.domainConfig(tt.u)
+			if ok != tt.wantOK || got.Proxy != tt.wantProxy {
+				t.Errorf("domainConfig() = %+v, %v, want proxy %q, %v", got, ok, tt.wantProxy, tt.wantOK)
+			}
+		})
+	}
+}
+
+func Test_client_WithDomainConfig(t *testing.T) {
+	var mu sync.Mutex
+	var commands []string
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, cmd.Cmd.String()+" "+hostname(cmd.URL)+" proxy="+cmd.Proxy+" timeout="+strconv.Itoa(cmd.MaxTimeout)+" session="+strconv.FormatBool(cmd.Session != ""))
+		return http.StatusOK, &Response{Status: "ok", Session: cmd.Session, Solution: &ResponseSolution{URL: cmd.URL, Status: http.StatusOK}}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient,
+		WithResponseCache(time.Minute, 0),
+		WithDomainConfig("example.com", DomainConfig{Timeout: 5 * time.Second, Proxy: "http://proxy:8080", Session: SessionPolicyPerDomain}),
+		WithDomainConfig("live.example.org", DomainConfig{CacheTTL: -1}),
+		WithDomainConfig("slow.example.org", DomainConfig{PolitenessDelay: 2 * time.Second}),
+	).(*client)
+
+	ctx := context.Background()
+	for _, u := range []string{
+		"https://www.example.com/a",
+		"https://live.example.org/",
+		"https://live.example.org/",
+		"https://other.example.org/",
+		"https://other.example.org/",
+	} {
+		if _, err := c.Get(ctx, u, uuid.Nil); err != nil {
+			t.Fatalf("Get(%s) error = %v", u, err)
+		}
+	}
+
+	// request options take precedence
+	if _, err := c.PostWithOptions(ctx, "https://example.com/search", uuid.Nil, "q=a", WithMaxTimeout(10*time.Second)); err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+
+	want := []string{
+		"sessions.create  proxy=http://proxy:8080 timeout=1000 session=true",
+		"request.get www.example.com proxy=http://proxy:8080 timeout=5000 session=true",
+		"request.get live.example.org proxy= timeout=1000 session=false",
+		"request.get live.example.org proxy= timeout=1000 session=false",
+		"request.get other.example.org proxy= timeout=1000 session=false",
+		"request.post example.com proxy=http://proxy:8080 timeout=10000 session=true",
+	}
+	if diff := cmp.Diff(want, commands); diff != "" {
+		t.Errorf("commands mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := c.politeness.delayFor("www.slow.example.org"); got != 2*time.Second {
+		t.Errorf("politeness delay = %s, want 2s", got)
+	}
+	if got := c.politeness.delayFor("other.example.org"); got != 0 {
+		t.Errorf("politeness delay = %s, want 0", got)
+	}
+}
@@ -0,0 +1,242 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/net/publicsuffix"
+)
+
+// domainSessions routes the requests made without session to a session of their target site,
+// created on first use and destroyed once idle, see WithSessionPerDomain.
+type domainSessions struct {
+	idle  time.Duration
+	clock Clock
+
+	// optIn routes only the requests to the domains configured with SessionPolicyPerDomain.
+	optIn bool
+
+	mu       sync.Mutex
+	sessions map[string]*domainSession
+}
+
+type domainSession struct {
+	id       uuid.UUID
+	ready    chan struct{} // closed once created
+	err      error
+	inFlight int
+
+	// abandoned reports whether the creation failed because the request creating the session gave up.
+	abandoned bool
+
+	stopIdle context.CancelFunc // stops the idle timer, once released
+}
+
+func newDomainSessions(idle time.Duration) *domainSessions {
+	return &domainSessions{idle: idle, clock: systemClock, sessions: make(map[string]*domainSession)}
+}
+
+// routable reports whether the command can be routed to a domain session.
+func routable(cmd *flaresolverrCommand) bool {
+	return (cmd.Cmd == CommandRequestget || cmd.Cmd == CommandRequestpost) && cmd.Session == ""
+}
+
+// routedToSite reports whether the command is routed to a session of its target site.
+func (c *client) routedToSite(cmd *flaresolverrCommand, o *requestOptions) bool {
+	if c.domainSessions == nil || !routable(cmd) || (c.profile != nil && c.profile.NoSessions) {
+		return false
+	}
+
+	switch o.sessionPolicy {
+	case SessionPolicyNone:
+		return false
+	case SessionPolicyPerDomain:
+		return true
+	default:
+		return !c.domainSessions.optIn
+	}
+}
+
+// siteOf returns the registrable domain of u, e.g. example.com for www.example.com.
+func siteOf(u string) string {
+	host := hostname(u)
+	if site, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return site
+	}
+
+	return host
+}
+
+// runInDomainSession runs the command in the session of its target site.
+func (c *client) runInDomainSession(ctx context.Context, cmd *flaresolverrCommand, o *requestOptions) (*Response, error) {
+	site := siteOf(cmd.URL)
+	if site == "" {
+		return c.exec(ctx, cmd, o)
+	}
+
+	// sessions are bound to a proxy
+	key := site + "\x00" + cmd.Proxy
+	id, err := c.domainSessions.acquire(ctx, c, key, site, cmd.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	defer c.domainSessions.release(c, key, id)
+
+	routed := *cmd
+	routed.Session = id.String()
+	response, err := c.run(ctx, &routed, o)
+	if errors.Is(err, ErrSessionNotFound) {
+		c.domainSessions.forget(key, id)
+	}
+
+	return response, err
+}
+
+// acquire returns the session of the site, creating it if needed.
+func (d *domainSessions) acquire(ctx context.Context, c *client, key, site, proxy string) (uuid.UUID, error) {
+	for {
+		d.mu.Lock()
+		s, ok := d.sessions[key]
+		if !ok {
+			break
+		}
+
+		s.inFlight++
+		if s.stopIdle != nil {
+			s.stopIdle()
+			s.stopIdle = nil
+		}
+		d.mu.Unlock()
+
+		select {
+		case <-s.ready:
+			if s.abandoned && ctx.Err() == nil {
+				// the error belongs to the request creating the session, try to create it again
+				continue
+			}
+			return s.id, s.err
+		case <-ctx.Done():
+			d.release(c, key, s.id)
+			return uuid.Nil, ctx.Err()
+		}
+	}
+
+	// d.mu is held, the site has no session
+	s := &domainSession{id: c.newUUID(), ready: make(chan struct{}), inFlight: 1}
+	d.sessions[key] = s
+	d.mu.Unlock()
+
+	create := &flaresolverrCommand{Cmd: CommandSessionscreate, Session: s.id.String(), Proxy: proxy}
+	_, s.err = c.run(ctx, create, &requestOptions{labels: map[string]string{"site": site}})
+	if s.err != nil {
+		s.abandoned = ctx.Err() != nil
+		d.forget(key, s.id)
+	}
+	close(s.ready)
+
+	return s.id, s.err
+}
+
+// release marks a request made in the session id done, destroying the session once idle.
+// Requests made in a session since replaced by another one are ignored.
+func (d *domainSessions) release(c *client, key string, id uuid.UUID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s, ok := d.sessions[key]
+	if !ok || s.id != id {
+		return
+	}
+
+	if s.inFlight--; s.inFlight > 0 {
+		return
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	s.stopIdle = stop
+	timer := d.clock.NewTimer(d.idle)
+	go func() {
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C():
+		}
+
+		d.mu.Lock()
+		if d.sessions[key] != s || s.inFlight > 0 {
+			d.mu.Unlock()
+			return
+		}
+		delete(d.sessions, key)
+		d.mu.Unlock()
+
+		destroy := &flaresolverrCommand{Cmd: CommandSessionsdestroy, Session: s.id.String()}
+		if _, err := c.run(context.Background(), destroy, new(requestOptions)); err != nil {
+			c.log(context.Background(), slog.LevelWarn, "cannot destroy idle flaresolverr session", slog.String("session", s.id.String()), slog.Any("error", err))
+		}
+	}()
+}
+
+// forget drops the session of the site, so the next request creates a new one.
+func (d *domainSessions) forget(key string, id uuid.UUID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if s, ok := d.sessions[key]; ok && s.id == id {
+		if s.stopIdle != nil {
+			s.stopIdle()
+		}
+		delete(d.sessions, key)
+	}
+}
+
+// close destroys every session, created or being created, and stops their idle timers.
+func (d *domainSessions) close(ctx context.Context, c *client) error {
+	d.mu.Lock()
+	sessions := make([]*domainSession, 0, len(d.sessions))
+	for key, s := range d.sessions {
+		if s.stopIdle != nil {
+			s.stopIdle()
+		}
+		delete(d.sessions, key)
+		sessions = append(sessions, s)
+	}
+	d.mu.Unlock()
+
+	var errs []error
+	for _, s := range sessions {
+		select {
+		case <-s.ready:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if s.err != nil {
+			continue
+		}
+
+		destroy := &flaresolverrCommand{Cmd: CommandSessionsdestroy, Session: s.id.String()}
+		if _, err := c.run(ctx, destroy, new(requestOptions)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// reset forgets every session, without destroying them.
+func (d *domainSessions) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key, s := range d.sessions {
+		if s.stopIdle != nil {
+			s.stopIdle()
+		}
+		delete(d.sessions, key)
+	}
+}
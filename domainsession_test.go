@@ -0,0 +1,196 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_client_WithSessionPerDomain(t *testing.T) {
+	var mu sync.Mutex
+	created, destroyed := make(map[string]int), make(map[string]bool)
+	sessions := make(map[string]string) // URL to session
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch cmd.Cmd {
+		case CommandSessionscreate:
+			created[cmd.Session]++
+		case CommandSessionsdestroy:
+			destroyed[cmd.Session] = true
+		case CommandRequestget:
+			sessions[cmd.URL] = cmd.Session
+		}
+		return http.StatusOK, &Response{Status: "ok", Session: cmd.Session}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithSessionPerDomain(50*time.Millisecond)).(*client)
+
+	urls := []string{"https://example.com/", "https://www.example.com/a", "https://example.com/b", "https://example.org/"}
+	var wg sync.WaitGroup
+	for _, u := range urls {
+		wg.Add(1)
+		go func(u string) {
+			defer wg.Done()
+			if _, err := c.Get(context.Background(), u, uuid.Nil); err != nil {
+				t.Errorf("Get(%s) error = %v", u, err)
+			}
+		}(u)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	if len(created) != 2 {
+		t.Errorf("created sessions = %v, want one per site", created)
+	}
+
+	for session, n := range created {
+		if n != 1 {
+			t.Errorf("session %s created %d times, want once", session, n)
+		}
+	}
+
+	example := sessions["https://example.com/"]
+	if example == "" || sessions["https://www.example.com/a"] != example || sessions["https://example.com/b"] != example {
+		t.Errorf("requests sessions = %v, want the same session for example.com", sessions)
+	}
+
+	if sessions["https://example.org/"] == example {
+		t.Errorf("requests sessions = %v, want another session for example.org", sessions)
+	}
+	mu.Unlock()
+
+	if got := c.FindSessionByLabel("site", "example.com"); got == nil || got.ID.String() != example {
+		t.Errorf("FindSessionByLabel() = %v, want %s", got, example)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for session := range created {
+		if !destroyed[session] {
+			t.Errorf("session %s not destroyed once idle", session)
+		}
+	}
+}
+
+func Test_client_WithUUIDSource(t *testing.T) {
+	var mu sync.Mutex
+	var created []string
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if cmd.Cmd == CommandSessionscreate {
+			created = append(created, cmd.Session)
+		}
+		return http.StatusOK, &Response{Status: "ok", Session: cmd.Session}
+	})
+
+	var n byte
+	source := func() uuid.UUID {
+		mu.Lock()
+		defer mu.Unlock()
+		n++
+		return uuid.UUID{15: n}
+	}
+
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithUUIDSource(source), WithSessionPerDomain(time.Minute))
+	ctx := context.Background()
+	for _, u := range []string{"https://example.com/", "https://example.org/", "https://example.com/a"} {
+		if _, err := c.Get(ctx, u, uuid.Nil); err != nil {
+			t.Fatalf("Get(%s) error = %v", u, err)
+		}
+	}
+
+	want := []string{uuid.UUID{15: 1}.String(), uuid.UUID{15: 2}.String()}
+	mu.Lock()
+	defer mu.Unlock()
+	if diff := cmp.Diff(want, created); diff != "" {
+		t.Errorf("created sessions mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_client_WithSessionPerDomain_replacedSession(t *testing.T) {
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		return http.StatusOK, &Response{Status: "ok", Session: cmd.Session}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithSessionPerDomain(time.Minute)).(*client)
+	d := c.domainSessions
+
+	ctx := context.Background()
+	const key = "example.com\x00"
+	old, err := d.acquire(ctx, c, key, "example.com", "")
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	// the session is lost while a request runs in it, the next request replaces it
+	d.forget(key, old)
+	id, err := d.acquire(ctx, c, key, "example.com", "")
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	d.release(c, key, old)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := d.sessions[key]
+	if s == nil || s.id != id || s.inFlight != 1 || s.stopIdle != nil {
+		t.Errorf("session = %+v, want %s still in use", s, id)
+	}
+}
+
+func Test_client_WithSessionPerDomain_abandonedCreation(t *testing.T) {
+	started := make(chan struct{})
+	var once sync.Once
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		if cmd.Cmd == CommandSessionscreate {
+			first := false
+			once.Do(func() { first = true })
+			if first {
+				// the first creation is abandoned while in progress
+				close(started)
+				time.Sleep(100 * time.Millisecond)
+			}
+		}
+		return http.StatusOK, &Response{Status: "ok", Session: cmd.Session}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithSessionPerDomain(time.Minute)).(*client)
+	d := c.domainSessions
+
+	const key = "example.com\x00"
+	creating, cancel := context.WithCancel(context.Background())
+	go func() {
+		_, _ = d.acquire(creating, c, key, "example.com", "")
+	}()
+	<-started
+
+	// wait for the request creating the session before canceling it
+	done := make(chan error, 1)
+	go func() {
+		_, err := d.acquire(context.Background(), c, key, "example.com", "")
+		done <- err
+	}()
+	for {
+		d.mu.Lock()
+		n := d.sessions[key].inFlight
+		d.mu.Unlock()
+		if n == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Errorf("acquire() error = %v, want the session created again", err)
+	}
+}
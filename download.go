@@ -0,0 +1,193 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+)
+
+// Download solves the challenge protecting u, then downloads u directly
+// using the solved cookies and user agent and streams its content into w.
+// FlareSolverr cannot return binary content, use this for files and images.
+//
+// The download is made with the client http.Client, which must reach the target
+// with the same IP address as FlareSolverr for the cookies to be accepted.
+func (c *client) Download(ctx context.Context, u string, w io.Writer, opts ...RequestOption) (int64, error) {
+	clearance, err := c.solveClearance(ctx, u, newRequestOptions(ctx, opts))
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := clearance.NewRequest(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return 0, fmt.Errorf("cannot make request: %w", err)
+	}
+
+	return c.download(ctx, req, w, new(downloadOptions))
+}
+
+// DownloadOption customizes a direct download.
+type DownloadOption func(*downloadOptions)
+
+type downloadOptions struct {
+	progress func(written, total int64)
+	offset   int64
+}
+
+// WithProgress calls fn every time a chunk is written with the number of bytes written so far.
+// total is -1 when the size is unknown. Both include the resumed offset.
+func WithProgress(fn func(written, total int64)) DownloadOption {
+	return func(o *downloadOptions) {
+		o.progress = fn
+	}
+}
+
+// WithResume resumes a download from offset, using a Range request.
+// Only the missing bytes are written, even if the server does not support ranges.
+func WithResume(offset int64) DownloadOption {
+	return func(o *downloadOptions) {
+		o.offset = offset
+	}
+}
+
+// DownloadDirect downloads u without FlareSolverr, using the cookies and user agent of the given session
+// as mirrored by the client, and streams its content into w.
+// The session must have solved the challenge protecting u beforehand.
+//
+// The download is made with the client http.Client, which must reach the target
+// with the same IP address as FlareSolverr for the cookies to be accepted.
+func (c *client) DownloadDirect(ctx context.Context, session uuid.UUID, u string, w io.Writer, opts ...DownloadOption) (int64, error) {
+	o := new(downloadOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	s := c.sessions.get(session)
+	if s == nil {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownSession, session)
+	}
+
+	clearance, err := s.Clearance(u)
+	if err != nil {
+		return 0, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	req, err := clearance.NewRequest(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return 0, fmt.Errorf("cannot make request: %w", err)
+	}
+
+	return c.download(ctx, req, w, o)
+}
+
+// download makes req and streams the response body into w,
+// within the client bandwidth and concurrency limits.
+func (c *client) download(ctx context.Context, req *http.Request, w io.Writer, o *downloadOptions) (int64, error) {
+	if c.downloadSlots != nil {
+		select {
+		case c.downloadSlots <- struct{}{}:
+			defer func() { <-c.downloadSlots }()
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	// the query may hold credentials, such as signed URL tokens
+	u := redactURL(req.URL.String())
+	if o.offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", o.offset))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			redacted := *urlErr
+			redacted.URL = u
+			err = &redacted
+		}
+		return 0, fmt.Errorf("error downloading %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	total := resp.ContentLength
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		if total >= 0 {
+			total += o.offset
+		}
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		// the range has been ignored, skip what has already been downloaded
+		if _, err := io.CopyN(io.Discard, resp.Body, o.offset); err != nil {
+			return 0, fmt.Errorf("error downloading %s: %w", u, err)
+		}
+	default:
+		return 0, fmt.Errorf("error downloading %s: unexpected status %s", u, resp.Status)
+	}
+
+	if o.progress != nil {
+		w = &progressWriter{w: w, written: o.offset, total: total, fn: o.progress}
+	}
+
+	if c.downloadLimiter != nil {
+		w = &throttledWriter{ctx: ctx, w: w, limiter: c.downloadLimiter}
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("error downloading %s: %w", u, err)
+	}
+
+	return n, nil
+}
+
+type progressWriter struct {
+	w       io.Writer
+	written int64
+	total   int64
+	fn      func(written, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	p.fn(p.written, p.total)
+	return n, err
+}
+
+type throttledWriter struct {
+	ctx     context.Context
+	w       io.Writer
+	limiter *rate.Limiter
+}
+
+func (t *throttledWriter) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		// a single wait cannot exceed the limiter burst
+		chunk := b
+		if burst := t.limiter.Burst(); len(chunk) > burst {
+			chunk = chunk[:burst]
+		}
+
+		if err := t.limiter.WaitN(t.ctx, len(chunk)); err != nil {
+			return written, err
+		}
+
+		n, err := t.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+
+		b = b[n:]
+	}
+
+	return written, nil
+}
@@ -0,0 +1,281 @@
+package flaresolverr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_client_Download(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("cf_clearance"); err != nil || cookie.Value != "foo" || r.UserAgent() != "bar" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		_, _ = w.Write([]byte{0x89, 0x50, 0x4e, 0x47})
+	}))
+	defer target.Close()
+
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		if !cmd.ReturnOnlyCookies {
+			t.Errorf("Download() expected returnOnlyCookies")
+		}
+
+		return http.StatusOK, &Response{
+			Status: "ok",
+			Solution: &ResponseSolution{
+				URL:       cmd.URL,
+				Status:    http.StatusOK,
+				Cookies:   []Cookie{{Name: "cf_clearance", Value: "foo"}},
+				UserAgent: "bar",
+			},
+		}
+	})
+
+	tests := []struct {
+		name    string
+		u       string
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "Expect binary content",
+			u:    target.URL + "/image.png",
+			want: []byte{0x89, 0x50, 0x4e, 0x47},
+		},
+		{
+			name:    "Expect error on invalid URL",
+			u:       "://foo",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			n, err := c.Download(context.Background(), tt.u, w)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Download() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !bytes.Equal(w.Bytes(), tt.want) || n != int64(len(tt.want)) {
+				t.Errorf("Download() = %v (%d bytes), want %v", w.Bytes(), n, tt.want)
+			}
+		})
+	}
+}
+
+func Test_client_DownloadDirect(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	handler := func(ranges bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if cookie, err := r.Cookie("cf_clearance"); err != nil || cookie.Value != "foo" || r.UserAgent() != "bar" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			if !ranges {
+				r.Header.Del("Range")
+			}
+			http.ServeContent(w, r, "file.bin", time.Time{}, bytes.NewReader(content))
+		}
+	}
+
+	withRanges := httptest.NewServer(handler(true))
+	defer withRanges.Close()
+	withoutRanges := httptest.NewServer(handler(false))
+	defer withoutRanges.Close()
+
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		return http.StatusOK, &Response{
+			Status: "ok",
+			Solution: &ResponseSolution{
+				URL:       cmd.URL,
+				Cookies:   []Cookie{{Name: "cf_clearance", Value: "foo", Path: "/", Session: true}},
+				UserAgent: "bar",
+			},
+		}
+	})
+
+	session := uuid.New()
+	for _, u := range []string{withRanges.URL, withoutRanges.URL} {
+		if _, err := c.Get(context.Background(), u, session); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+
+	type args struct {
+		session uuid.UUID
+		u       string
+		offset  int64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []byte
+		wantErr error
+	}{
+		{
+			name: "Full download",
+			args: args{session: session, u: withRanges.URL + "/file.bin"},
+			want: content,
+		},
+		{
+			name: "Resumed download",
+			args: args{session: session, u: withRanges.URL + "/file.bin", offset: 995},
+			want: content[995:],
+		},
+		{
+			name: "Resumed download without range support",
+			args: args{session: session, u: withoutRanges.URL + "/file.bin", offset: 995},
+			want: content[995:],
+		},
+		{
+			name:    "Unknown session",
+			args:    args{session: uuid.New(), u: withRanges.URL + "/file.bin"},
+			wantErr: ErrUnknownSession,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written, total int64
+			w := new(bytes.Buffer)
+			n, err := c.DownloadDirect(context.Background(), tt.args.session, tt.args.u, w, WithResume(tt.args.offset), WithProgress(func(w, t int64) {
+				written, total = w, t
+			}))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DownloadDirect() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			if !bytes.Equal(w.Bytes(), tt.want) || n != int64(len(tt.want)) {
+				t.Errorf("DownloadDirect() = %q (%d bytes), want %q", w.Bytes(), n, tt.want)
+			}
+
+			if written != int64(len(content)) || total != int64(len(content)) {
+				t.Errorf("DownloadDirect() progress = %d/%d, want %d/%d", written, total, len(content), len(content))
+			}
+		})
+	}
+}
+
+func Test_client_DownloadDirect_redactsQuery(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer target.Close()
+
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		return http.StatusOK, &Response{
+			Status: "ok",
+			Solution: &ResponseSolution{
+				URL:     cmd.URL,
+				Cookies: []Cookie{{Name: "cf_clearance", Value: "foo", Path: "/", Session: true}},
+			},
+		}
+	})
+
+	session := uuid.New()
+	for _, u := range []string{target.URL, unreachable.URL} {
+		if _, err := c.Get(context.Background(), u, session); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+
+		_, err := c.DownloadDirect(context.Background(), session, u+"/file.zip?token=secret", io.Discard)
+		if err == nil {
+			t.Fatalf("DownloadDirect(%s) error = nil, want an error", u)
+		}
+		if strings.Contains(err.Error(), "secret") {
+			t.Errorf("DownloadDirect() error = %v, contains the query", err)
+		}
+	}
+}
+
+func Test_client_download_limits(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 2000)
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		_, _ = w.Write(content)
+	}))
+	defer target.Close()
+
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithDownloadBandwidth(10000), WithMaxConcurrentDownloads(1))
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Download(context.Background(), target.URL, io.Discard); err != nil {
+				t.Errorf("Download() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 40000 bytes at 10000 bytes per second, the first 10000 being the burst
+	if elapsed := time.Since(start); elapsed < 2500*time.Millisecond {
+		t.Errorf("Download() took %v, expected to be throttled", elapsed)
+	}
+
+	if maxInFlight != 1 {
+		t.Errorf("Download() ran %d downloads concurrently, want 1", maxInFlight)
+	}
+}
+
+func Test_client_download_noLimits(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bytes.Repeat([]byte("0123456789"), 2000))
+	}))
+	defer target.Close()
+
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+
+	// zero limits are unlimited, rather than blocking the downloads forever
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithDownloadBandwidth(0), WithMaxConcurrentDownloads(0))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	n, err := c.Download(ctx, target.URL, io.Discard)
+	if err != nil || n != 20000 {
+		t.Errorf("Download() = %d, %v, want 20000 bytes", n, err)
+	}
+}
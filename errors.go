@@ -0,0 +1,182 @@
+package flaresolverr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxErrorBody is the number of bytes of the FlareSolverr response kept in errors.
+const maxErrorBody = 512
+
+// Error is returned by the client commands.
+// It describes what was being solved when the underlying error occurred.
+type Error struct {
+	Command Command
+
+	// Host is the target host, the full URL is not included for privacy.
+	Host string
+
+	Session   string
+	RequestID string
+
+	// Vendor is the vendor of the challenge which failed, if known.
+	Vendor Vendor
+
+	// Attempt is the failed attempt, out of MaxAttempts.
+	Attempt     int
+	MaxAttempts int
+
+	// StatusCode, Header and Body describe the FlareSolverr HTTP response when the command
+	// failed after reaching the server, to diagnose a reverse proxy or a wrong path.
+	// Body is truncated to 512 bytes, and redacted following the client RedactionPolicy.
+	StatusCode int
+	Header     http.Header
+	Body       string
+
+	Err error
+
+	// redact removes the sensitive data from the message of Err.
+	redact func(string) string
+}
+
+func newError(cmd *flaresolverrCommand, o *requestOptions, attempt, maxAttempts int, err error) *Error {
+	e := &Error{
+		Command:     cmd.Cmd,
+		Session:     cmd.Session,
+		RequestID:   o.requestID,
+		Attempt:     attempt,
+		MaxAttempts: maxAttempts,
+		Vendor:      vendorOf(err.Error()),
+		Err:         err,
+	}
+
+	if u, err := url.Parse(cmd.URL); err == nil {
+		e.Host = u.Hostname()
+	}
+
+	var re *responseError
+	if errors.As(err, &re) {
+		e.StatusCode, e.Header, e.Body = re.statusCode, re.header, re.body
+	}
+
+	return e
+}
+
+// maxSnippet is the number of bytes of a non-JSON response quoted in errors.
+const maxSnippet = 120
+
+// looksLikeJSON reports whether the body is a JSON object, as returned by FlareSolverr.
+func looksLikeJSON(body []byte) bool {
+	body = bytes.TrimLeft(body, " \t\r\n")
+	return len(body) > 0 && body[0] == '{'
+}
+
+// snippet returns the beginning of a body on a single line.
+func snippet(body []byte) string {
+	s := strings.Join(strings.Fields(string(body)), " ")
+	if len(s) <= maxSnippet {
+		return s
+	}
+
+	s = s[:maxSnippet]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+
+	return s + "..."
+}
+
+// responseError is a failure after FlareSolverr answered, with its HTTP response.
+type responseError struct {
+	statusCode int
+	header     http.Header
+	body       string
+	err        error
+}
+
+func newResponseError(resp *http.Response, body []byte, err error) *responseError {
+	if len(body) > maxErrorBody {
+		body = body[:maxErrorBody]
+		// do not cut a rune in half
+		for len(body) > 0 && !utf8.Valid(body) {
+			body = body[:len(body)-1]
+		}
+	}
+
+	return &responseError{statusCode: resp.StatusCode, header: resp.Header.Clone(), body: string(body), err: err}
+}
+
+// Error returns the underlying error, with the HTTP status when it is not a FlareSolverr error response.
+func (e *responseError) Error() string {
+	if e.statusCode == http.StatusOK || e.statusCode == http.StatusInternalServerError {
+		return e.err.Error()
+	}
+
+	return fmt.Sprintf("%v (HTTP %d %s)", e.err, e.statusCode, http.StatusText(e.statusCode))
+}
+
+func (e *responseError) Unwrap() error {
+	return e.err
+}
+
+// Error returns a message such as
+// "request.get example.com (session 47d0a203, attempt 2/3): maximum timeout reached".
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Command.String())
+	if e.Host != "" {
+		b.WriteString(" " + e.Host)
+	}
+
+	b.WriteString(" (")
+	if e.RequestID != "" {
+		b.WriteString("request " + e.RequestID + ", ")
+	}
+	if e.Session != "" {
+		b.WriteString("session " + shortID(e.Session) + ", ")
+	}
+	fmt.Fprintf(&b, "attempt %d/%d): %s", e.Attempt, e.MaxAttempts, e.message())
+
+	return b.String()
+}
+
+// message returns the message of the underlying error, without the sensitive data.
+func (e *Error) message() string {
+	if e.redact == nil {
+		return e.Err.Error()
+	}
+
+	return e.redact(e.Err.Error())
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// suppressionKey identifies identical errors, whatever the attempt or session.
+func suppressionKey(e *Error) string {
+	return e.Command.String() + " " + e.Host + ": " + e.message()
+}
+
+// shortID returns the first group of a UUID.
+func shortID(id string) string {
+	if short, _, ok := strings.Cut(id, "-"); ok {
+		return short
+	}
+
+	return id
+}
+
+// retryable reports whether a failed command is worth retrying:
+// FlareSolverr timeouts and network errors.
+func retryable(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, ErrRequestTimeout) || errors.As(err, &netErr)
+}
@@ -0,0 +1,263 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *Error
+		want string
+	}{
+		{
+			name: "Request with session",
+			err: &Error{
+				Command:     CommandRequestget,
+				Host:        "example.com",
+				Session:     "47d0a203-a007-4a01-b8c1-0cf0156c3cc7",
+				Attempt:     2,
+				MaxAttempts: 3,
+				Err:         ErrRequestTimeout,
+			},
+			want: "request.get example.com (session 47d0a203, attempt 2/3): maximum timeout reached",
+		},
+		{
+			name: "Request with request ID",
+			err: &Error{
+				Command:     CommandRequestpost,
+				Host:        "example.com",
+				RequestID:   "job-42",
+				Attempt:     1,
+				MaxAttempts: 1,
+				Err:         ErrRequestTimeout,
+			},
+			want: "request.post example.com (request job-42, attempt 1/1): maximum timeout reached",
+		},
+		{
+			name: "Session command",
+			err: &Error{
+				Command:     CommandSessionslist,
+				Attempt:     1,
+				MaxAttempts: 1,
+				Err:         ErrUnexpectedError,
+			},
+			want: "sessions.list (attempt 1/1): unexpected error from FlareSolverr server",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newError_Vendor(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *Response
+		want Vendor
+	}{
+		{name: "DDoS-Guard", resp: &Response{Message: "Error: Error solving the challenge. DDoS-Guard challenge detected."}, want: VendorDDoSGuard},
+		{name: "Cloudflare", resp: &Response{Message: "Error: Cloudflare has blocked this request."}, want: VendorCloudflare},
+		{name: "Unknown", resp: &Response{Message: "Error: maximum timeout reached"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &flaresolverrCommand{Cmd: CommandRequestget, URL: "https://example.com"}
+			if got := newError(cmd, &requestOptions{}, 1, 1, handleError(tt.resp)); got.Vendor != tt.want {
+				t.Errorf("newError() Vendor = %q, want %q", got.Vendor, tt.want)
+			}
+		})
+	}
+}
+
+func Test_client_WithRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		post         bool
+		retryPOST    bool
+		messages     []string
+		wantErr      error
+		wantAttempts int
+	}{
+		{
+			name:         "Succeeds after a timeout",
+			messages:     []string{"Error: maximum timeout reached", ""},
+			wantAttempts: 2,
+		},
+		{
+			name:         "Attempts exhausted",
+			messages:     []string{"Error: maximum timeout reached", "Error: maximum timeout reached", "Error: maximum timeout reached"},
+			wantErr:      ErrRequestTimeout,
+			wantAttempts: 3,
+		},
+		{
+			name:         "Unexpected errors are not retried",
+			messages:     []string{"Error: oops", ""},
+			wantErr:      ErrUnexpectedError,
+			wantAttempts: 1,
+		},
+		{
+			name:         "POST requests are not retried",
+			post:         true,
+			messages:     []string{"Error: maximum timeout reached", ""},
+			wantErr:      ErrRequestTimeout,
+			wantAttempts: 1,
+		},
+		{
+			name:         "POST requests are retried with RetryPOST",
+			post:         true,
+			retryPOST:    true,
+			messages:     []string{"Error: maximum timeout reached", ""},
+			wantAttempts: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+				attempts++
+				if msg := tt.messages[attempts-1]; msg != "" {
+					return http.StatusInternalServerError, &Response{Status: "error", Message: msg}
+				}
+				return http.StatusOK, &Response{Status: "ok"}
+			})
+			c := New(srv.baseURL, time.Second, srv.httpClient, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, RetryPOST: tt.retryPOST}))
+
+			var err error
+			if tt.post {
+				_, err = c.Post(context.Background(), "https://example.com/foo", uuid.Nil, "a=b")
+			} else {
+				_, err = c.Get(context.Background(), "https://example.com/foo", uuid.Nil)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var e *Error
+			if err != nil && (!errors.As(err, &e) || e.Attempt != tt.wantAttempts || e.Host != "example.com") {
+				t.Errorf("Get() error = %#v, expected an *Error at attempt %d", err, tt.wantAttempts)
+			}
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("Get() attempts = %v, want %v", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func Test_client_responseError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantErr    error
+		wantStatus int
+		wantBody   string
+		wantMsg    string
+	}{
+		{
+			name:       "FlareSolverr error",
+			status:     http.StatusInternalServerError,
+			body:       `{"status":"error","message":"Error: maximum timeout reached"}`,
+			wantErr:    ErrRequestTimeout,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"status":"error","message":"Error: maximum timeout reached"}`,
+		},
+		{
+			name:       "Wrong path",
+			status:     http.StatusNotFound,
+			body:       `{"status":"error","message":"Not Found"}`,
+			wantErr:    ErrUnexpectedError,
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"status":"error","message":"Not Found"}`,
+			wantMsg:    "HTTP 404 Not Found",
+		},
+		{
+			name:       "HTML error page",
+			status:     http.StatusBadGateway,
+			body:       "<html>\n<head><title>502 Bad Gateway</title></head>\n<body>nginx</body>\n</html>",
+			wantErr:    ErrInvalidServerResponse,
+			wantStatus: http.StatusBadGateway,
+			wantBody:   "<html>\n<head><title>502 Bad Gateway</title></head>\n<body>nginx</body>\n</html>",
+			wantMsg:    `"<html> <head><title>502 Bad Gateway</title></head> <body>nginx</body> </html>"`,
+		},
+		{
+			name:       "Plain text",
+			status:     http.StatusNotFound,
+			body:       "404 page not found\n",
+			wantErr:    ErrInvalidServerResponse,
+			wantStatus: http.StatusNotFound,
+			wantBody:   "404 page not found\n",
+			wantMsg:    `404 Not Found "404 page not found"`,
+		},
+		{
+			name:       "Truncated body",
+			status:     http.StatusBadGateway,
+			body:       `{"message":"` + strings.Repeat("é", maxErrorBody) + `"}`,
+			wantErr:    ErrUnexpectedError,
+			wantStatus: http.StatusBadGateway,
+			wantBody:   `{"message":"` + strings.Repeat("é", (maxErrorBody-12)/2),
+			wantMsg:    "HTTP 502 Bad Gateway",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Server", "nginx")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := New(srv.URL, time.Second, srv.Client())
+			_, err := c.Get(context.Background(), "https://example.com", uuid.Nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Get() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("Get() error = %T, want *Error", err)
+			}
+
+			if e.StatusCode != tt.wantStatus || e.Header.Get("Server") != "nginx" || e.Body != tt.wantBody {
+				t.Errorf("Get() error = status %d, server %q, body %q, want %d, nginx, %q", e.StatusCode, e.Header.Get("Server"), e.Body, tt.wantStatus, tt.wantBody)
+			}
+
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Get() error = %q, want %q in the message", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func Test_snippet(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "Short", body: "Bad  Gateway\n", want: "Bad Gateway"},
+		{name: "Long", body: strings.Repeat("a", maxSnippet+1), want: strings.Repeat("a", maxSnippet) + "..."},
+		{name: "Multi-byte", body: "a" + strings.Repeat("é", maxSnippet), want: "a" + strings.Repeat("é", (maxSnippet-1)/2) + "..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snippet([]byte(tt.body)); got != tt.want {
+				t.Errorf("snippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package flaresolverr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+	"github.com/SkYNewZ/go-flaresolverr/flaresolverrtest"
+	"github.com/google/uuid"
+)
+
+func ExampleNew() {
+	srv := flaresolverrtest.NewServer()
+	defer srv.Close()
+
+	c := flaresolverr.New(srv.Endpoint(), 60*time.Second, nil,
+		flaresolverr.WithRetryPolicy(flaresolverr.RetryPolicy{MaxAttempts: 3}),
+	)
+
+	if err := c.Health(context.Background()); err != nil {
+		fmt.Println("FlareSolverr is down:", err)
+		return
+	}
+	fmt.Println("FlareSolverr is up")
+	// Output: FlareSolverr is up
+}
+
+func ExampleClient_Get() {
+	srv := flaresolverrtest.NewServer(flaresolverrtest.WithHandler(flaresolverrtest.Page("<h1>Hello</h1>")))
+	defer srv.Close()
+
+	c := flaresolverr.New(srv.Endpoint(), 60*time.Second, srv.Client())
+	resp, err := c.GetWithOptions(context.Background(), "https://example.com", uuid.Nil, flaresolverr.WithRequestID("job-42"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(resp.Solution.Status, resp.Solution.Response)
+	// Output: 200 <h1>Hello</h1>
+}
+
+func ExampleClient_Post() {
+	srv := flaresolverrtest.NewServer(flaresolverrtest.WithHandler(func(cmd *flaresolverrtest.Command) (int, *flaresolverr.Response) {
+		return flaresolverrtest.Page("posted " + cmd.PostData)(cmd)
+	}))
+	defer srv.Close()
+
+	c := flaresolverr.New(srv.Endpoint(), 60*time.Second, srv.Client())
+	resp, err := c.Post(context.Background(), "https://example.com/search", uuid.Nil, "q=flaresolverr")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(resp.Solution.Response)
+	// Output: posted q=flaresolverr
+}
+
+func ExampleClient_CreateSession() {
+	srv := flaresolverrtest.NewServer()
+	defer srv.Close()
+
+	c := flaresolverr.New(srv.Endpoint(), 60*time.Second, srv.Client())
+	ctx := context.Background()
+
+	session := uuid.New()
+	if _, err := c.CreateSessionWithOptions(ctx, session, flaresolverr.WithLabel("site", "example.com")); err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer c.DestroySession(context.Background(), session)
+
+	// requests within the session share the browser cookies
+	if _, err := c.Get(ctx, "https://example.com", session); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(c.FindSessionByLabel("site", "example.com").ID == session)
+	// Output: true
+}
+
+func ExampleClient_ListSessionInfo() {
+	srv := flaresolverrtest.NewServer()
+	defer srv.Close()
+
+	c := flaresolverr.New(srv.Endpoint(), 60*time.Second, srv.Client())
+	ctx := context.Background()
+
+	session := uuid.New()
+	if _, err := c.CreateSessionWithOptions(ctx, session, flaresolverr.WithProxy("http://proxy:8080")); err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer c.DestroySession(context.Background(), session)
+
+	infos, err := c.ListSessionInfo(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, info := range infos {
+		fmt.Println(info.Tracked, info.Proxy, info.InUse)
+	}
+	// Output: true http://proxy:8080 false
+}
+
+func ExampleClient_DestroySession() {
+	srv := flaresolverrtest.NewServer()
+	defer srv.Close()
+
+	c := flaresolverr.New(srv.Endpoint(), 60*time.Second, srv.Client())
+
+	err := c.DestroySession(context.Background(), uuid.New())
+	fmt.Println(errors.Is(err, flaresolverr.ErrSessionNotFound))
+	// Output: true
+}
+
+func ExampleClient_GetBatch() {
+	srv := flaresolverrtest.NewServer()
+	defer srv.Close()
+
+	c := flaresolverr.New(srv.Endpoint(), 60*time.Second, srv.Client())
+	urls := []string{"https://example.com/1", "https://example.com/2"}
+
+	responses, errs := c.GetBatch(context.Background(), urls, flaresolverr.WithBatchConcurrency(2))
+	for i := range urls {
+		if errs[i] != nil {
+			fmt.Println(errs[i])
+			continue
+		}
+		fmt.Println(responses[i].Solution.URL)
+	}
+	// Output:
+	// https://example.com/1
+	// https://example.com/2
+}
+
+func ExampleNewHybridClient() {
+	srv := flaresolverrtest.NewServer()
+	defer srv.Close()
+
+	// the target is not protected, it is reached directly
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "direct")
+	}))
+	defer target.Close()
+
+	c := flaresolverr.New(srv.Endpoint(), 60*time.Second, srv.Client())
+	hc := flaresolverr.NewHybridClient(c, target.Client())
+
+	resp, err := hc.Get(target.URL)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	fmt.Println(string(body))
+	// Output: direct
+}
+
+func ExampleNewQueue() {
+	srv := flaresolverrtest.NewServer()
+	defer srv.Close()
+
+	c := flaresolverr.New(srv.Endpoint(), 60*time.Second, srv.Client())
+	results := make(chan *flaresolverr.Result, 1)
+	q := flaresolverr.NewQueue(c, func(r *flaresolverr.Result) { results <- r })
+
+	if err := q.Enqueue(context.Background(), flaresolverr.Job{ID: "home", URL: "https://example.com"}); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	r := <-results
+	fmt.Println(r.Job.ID, r.Err, r.Response.Solution.URL)
+
+	_ = q.Close(context.Background())
+	// Output: home <nil> https://example.com
+}
+
+func ExampleWithMaxInFlight() {
+	srv := flaresolverrtest.NewServer()
+	defer srv.Close()
+
+	// at most 4 browser tabs at the same time, 100 requests waiting at most
+	c := flaresolverr.New(srv.Endpoint(), 60*time.Second, srv.Client(), flaresolverr.WithMaxInFlight(4, 100))
+
+	// interactive requests jump ahead of the bulk traffic
+	resp, err := c.GetWithOptions(context.Background(), "https://example.com", uuid.Nil, flaresolverr.WithPriority(flaresolverr.PriorityHigh))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(resp.OK())
+	// Output: true
+}
+
+func ExampleParseForms() {
+	forms, err := flaresolverr.ParseForms(`<form id="search" action="/search"><input name="q" value="go"></form>`, "https://example.com/")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(forms[0].Method, forms[0].Action, forms[0].Encode())
+	// Output: GET https://example.com/search q=go
+}
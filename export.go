@@ -0,0 +1,139 @@
+package flaresolverr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrUnknownCookieFormat when cookies are exported in a format which is not supported.
+var ErrUnknownCookieFormat = errors.New("unknown cookie format")
+
+// CookieFormat is a cookie format understood by a browser automation tool.
+type CookieFormat string
+
+const (
+	// CookieFormatPlaywright is the Playwright storage state, loaded with browser.newContext({storageState}).
+	CookieFormatPlaywright CookieFormat = "playwright"
+
+	// CookieFormatSelenium is a list of WebDriver cookies, added one by one with driver.add_cookie.
+	CookieFormatSelenium CookieFormat = "selenium"
+)
+
+// ExportCookies returns the solution cookies as JSON in the given format,
+// to hand the solved state over to another browser. That browser must use the
+// solution user agent and reach the target with the same IP address as FlareSolverr.
+// Cookies without domain are bound to the host of the solution URL.
+func (s *ResponseSolution) ExportCookies(format CookieFormat) ([]byte, error) {
+	switch format {
+	case CookieFormatPlaywright:
+		return json.Marshal(s.playwrightState())
+	case CookieFormatSelenium:
+		return json.Marshal(s.seleniumCookies())
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCookieFormat, format)
+	}
+}
+
+// playwrightState is a Playwright storage state.
+type playwrightState struct {
+	Cookies []playwrightCookie `json:"cookies"`
+	Origins []json.RawMessage  `json:"origins"`
+}
+
+type playwrightCookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  float64 `json:"expires"`
+	HTTPOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+	SameSite string  `json:"sameSite"`
+}
+
+func (s *ResponseSolution) playwrightState() *playwrightState {
+	state := &playwrightState{Cookies: make([]playwrightCookie, 0, len(s.Cookies)), Origins: []json.RawMessage{}}
+	for _, cookie := range s.Cookies {
+		c := playwrightCookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   s.cookieDomain(cookie),
+			Path:     cookie.Path,
+			Expires:  -1,
+			HTTPOnly: cookie.HTTPOnly,
+			Secure:   cookie.Secure,
+			SameSite: sameSite(cookie.SameSite),
+		}
+
+		if c.Path == "" {
+			c.Path = "/"
+		}
+
+		// Playwright requires a SameSite value, Lax is the browser default
+		if c.SameSite == "" {
+			c.SameSite = "Lax"
+		}
+
+		if expires := cookie.ExpiresAt(); !expires.IsZero() {
+			c.Expires = float64(expires.UnixNano()) / float64(time.Second)
+		}
+
+		state.Cookies = append(state.Cookies, c)
+	}
+
+	return state
+}
+
+func (s *ResponseSolution) seleniumCookies() []commandCookie {
+	cookies := make([]commandCookie, 0, len(s.Cookies))
+	for _, cookie := range s.Cookies {
+		c := commandCookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   s.cookieDomain(cookie),
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HTTPOnly,
+			SameSite: sameSite(cookie.SameSite),
+		}
+
+		if expires := cookie.ExpiresAt(); !expires.IsZero() {
+			c.Expiry = expires.Unix()
+		}
+
+		cookies = append(cookies, c)
+	}
+
+	return cookies
+}
+
+// cookieDomain returns the domain of cookie, or the host of the solution URL.
+func (s *ResponseSolution) cookieDomain(cookie Cookie) string {
+	if cookie.Domain != "" {
+		return cookie.Domain
+	}
+
+	if u, err := url.Parse(s.URL); err == nil {
+		return u.Hostname()
+	}
+
+	return ""
+}
+
+// sameSite normalizes a SameSite value to Strict, Lax or None, or an empty string if unknown.
+func sameSite(value string) string {
+	switch strings.ToLower(value) {
+	case "strict":
+		return "Strict"
+	case "lax":
+		return "Lax"
+	case "none":
+		return "None"
+	default:
+		return ""
+	}
+}
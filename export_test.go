@@ -0,0 +1,50 @@
+package flaresolverr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResponseSolution_ExportCookies(t *testing.T) {
+	solution := &ResponseSolution{
+		URL: "https://www.example.com/page",
+		Cookies: []Cookie{
+			{Name: "cf_clearance", Value: "solved", Domain: ".example.com", Path: "/", Expires: 1893456000, HTTPOnly: true, Secure: true, SameSite: "None"},
+			{Name: "session", Value: "foo", Expires: -1, Session: true},
+		},
+	}
+
+	tests := []struct {
+		format  CookieFormat
+		want    string
+		wantErr error
+	}{
+		{
+			format: CookieFormatPlaywright,
+			want: `{"cookies":[` +
+				`{"name":"cf_clearance","value":"solved","domain":".example.com","path":"/","expires":1893456000,"httpOnly":true,"secure":true,"sameSite":"None"},` +
+				`{"name":"session","value":"foo","domain":"www.example.com","path":"/","expires":-1,"httpOnly":false,"secure":false,"sameSite":"Lax"}` +
+				`],"origins":[]}`,
+		},
+		{
+			format: CookieFormatSelenium,
+			want: `[` +
+				`{"name":"cf_clearance","value":"solved","domain":".example.com","path":"/","secure":true,"httpOnly":true,"expiry":1893456000,"sameSite":"None"},` +
+				`{"name":"session","value":"foo","domain":"www.example.com"}` +
+				`]`,
+		},
+		{format: "puppeteer", wantErr: ErrUnknownCookieFormat},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			got, err := solution.ExportCookies(tt.format)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExportCookies() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("ExportCookies() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Package flaresolverrchromedp hands the state solved by FlareSolverr over to a chromedp browser,
+// to continue the automation once the challenge is solved.
+//
+//	err := chromedp.Run(ctx,
+//		flaresolverrchromedp.SetUserAgent(resp.Solution),
+//		flaresolverrchromedp.SetCookies(resp.Solution),
+//		chromedp.Navigate(resp.Solution.URL),
+//	)
+//
+// The browser must reach the target with the same IP address as FlareSolverr
+// for the cookies to be accepted.
+package flaresolverrchromedp
+
+import (
+	"strings"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+)
+
+// CookieParams converts the solution cookies to CDP cookie parameters.
+// Cookies without domain are bound to the solution URL.
+func CookieParams(s *flaresolverr.ResponseSolution) []*network.CookieParam {
+	params := make([]*network.CookieParam, 0, len(s.Cookies))
+	for _, cookie := range s.Cookies {
+		param := &network.CookieParam{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HTTPOnly,
+			SameSite: sameSite(cookie.SameSite),
+		}
+
+		if param.Domain == "" {
+			param.URL = s.URL
+		}
+
+		if expires := cookie.ExpiresAt(); !expires.IsZero() {
+			t := cdp.TimeSinceEpoch(expires)
+			param.Expires = &t
+		}
+
+		params = append(params, param)
+	}
+
+	return params
+}
+
+// SetCookies returns the chromedp action setting the solution cookies in the browser.
+func SetCookies(s *flaresolverr.ResponseSolution) *network.SetCookiesParams {
+	return network.SetCookies(CookieParams(s))
+}
+
+// SetUserAgent returns the chromedp action making the browser use the solution user agent.
+// Cloudflare binds its clearance cookie to the user agent which solved the challenge.
+func SetUserAgent(s *flaresolverr.ResponseSolution) *emulation.SetUserAgentOverrideParams {
+	return emulation.SetUserAgentOverride(s.UserAgent)
+}
+
+func sameSite(value string) network.CookieSameSite {
+	switch strings.ToLower(value) {
+	case "strict":
+		return network.CookieSameSiteStrict
+	case "lax":
+		return network.CookieSameSiteLax
+	case "none":
+		return network.CookieSameSiteNone
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,42 @@
+package flaresolverrchromedp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCookieParams(t *testing.T) {
+	expires := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	solution := &flaresolverr.ResponseSolution{
+		URL:       "https://example.com/page",
+		UserAgent: "Mozilla/5.0",
+		Cookies: []flaresolverr.Cookie{
+			{Name: "cf_clearance", Value: "solved", Domain: ".example.com", Path: "/", Expires: float64(expires.Unix()), HTTPOnly: true, Secure: true, SameSite: "None"},
+			{Name: "session", Value: "foo", Path: "/", Expires: -1, Session: true, SameSite: "lax"},
+		},
+	}
+
+	cdpExpires := cdp.TimeSinceEpoch(expires)
+	want := []*network.CookieParam{
+		{Name: "cf_clearance", Value: "solved", Domain: ".example.com", Path: "/", Expires: &cdpExpires, HTTPOnly: true, Secure: true, SameSite: network.CookieSameSiteNone},
+		{Name: "session", Value: "foo", URL: "https://example.com/page", Path: "/", SameSite: network.CookieSameSiteLax},
+	}
+
+	got := CookieParams(solution)
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b cdp.TimeSinceEpoch) bool { return a.Time().Equal(b.Time()) })); diff != "" {
+		t.Errorf("CookieParams() mismatch (-want +got):\n%s", diff)
+	}
+
+	if params := SetCookies(solution); len(params.Cookies) != 2 {
+		t.Errorf("SetCookies() sets %d cookies, want 2", len(params.Cookies))
+	}
+
+	if params := SetUserAgent(solution); params.UserAgent != "Mozilla/5.0" {
+		t.Errorf("SetUserAgent() = %q, want %q", params.UserAgent, "Mozilla/5.0")
+	}
+}
@@ -0,0 +1,358 @@
+// Package flaresolverrconfig builds FlareSolverr clients from a declarative YAML or JSON file,
+// so deployments configure the client without code changes.
+//
+//	# flaresolverr.yaml
+//	url: http://flaresolverr:8191/v1
+//	timeout: 60s
+//	retry:
+//	  maxAttempts: 3
+//	  initialBackoff: 1s
+//	proxies:
+//	  - http://proxy-1:8080
+//	  - http://proxy-2:8080
+//	domains:
+//	  example.com:
+//	    timeout: 90s
+//	    session: perDomain
+//	    politenessDelay: 2s
+//
+//	c, err := flaresolverrconfig.NewFromConfig("flaresolverr.yaml")
+//
+// Settings are overridden by the environment, see Config.ApplyEnv.
+package flaresolverrconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidConfig when a configuration cannot be loaded or used to build a client.
+var ErrInvalidConfig = errors.New("invalid flaresolverr configuration")
+
+// Config is the configuration of a FlareSolverr client. Zero fields keep the client defaults.
+type Config struct {
+	// URL is the FlareSolverr endpoint, the /v1 API path is appended when missing.
+	URL string `json:"url" yaml:"url"`
+
+	// Timeout is how long FlareSolverr is given to solve the challenges, see flaresolverr.New.
+	Timeout Duration `json:"timeout" yaml:"timeout"`
+
+	// TimeoutPadding see flaresolverr.WithTimeoutPadding.
+	TimeoutPadding Duration `json:"timeoutPadding" yaml:"timeoutPadding"`
+
+	// Headers are sent to FlareSolverr with every command, see flaresolverr.WithHeader.
+	Headers map[string]string `json:"headers" yaml:"headers"`
+
+	// BearerToken authenticates the client to FlareSolverr, see flaresolverr.WithBearerToken.
+	// Prefer setting it with the FLARESOLVERR_BEARER_TOKEN environment variable.
+	BearerToken string `json:"bearerToken" yaml:"bearerToken"`
+
+	// Retry see flaresolverr.WithRetryPolicy.
+	Retry *RetryConfig `json:"retry" yaml:"retry"`
+
+	// Proxies are rotated over the requests made without proxy, see flaresolverr.WithProxyRotation.
+	Proxies []string `json:"proxies" yaml:"proxies"`
+
+	// ProxyHealth scores the proxies, see flaresolverr.ProxyHealthPolicy.
+	ProxyHealth *ProxyHealthConfig `json:"proxyHealth" yaml:"proxyHealth"`
+
+	// Cache see flaresolverr.WithResponseCache.
+	Cache *CacheConfig `json:"cache" yaml:"cache"`
+
+	// PolitenessDelay is the minimum delay between two requests to the same hostname, see flaresolverr.WithPoliteness.
+	PolitenessDelay Duration `json:"politenessDelay" yaml:"politenessDelay"`
+
+	// SessionPerDomainIdle routes the requests made without session to a session of their site,
+	// destroyed once idle for this long, see flaresolverr.WithSessionPerDomain.
+	SessionPerDomainIdle Duration `json:"sessionPerDomainIdle" yaml:"sessionPerDomainIdle"`
+
+	// Domains configures the requests per domain, see flaresolverr.WithDomainConfig.
+	Domains map[string]DomainConfig `json:"domains" yaml:"domains"`
+}
+
+// RetryConfig see flaresolverr.RetryPolicy.
+type RetryConfig struct {
+	MaxAttempts    int      `json:"maxAttempts" yaml:"maxAttempts"`
+	InitialBackoff Duration `json:"initialBackoff" yaml:"initialBackoff"`
+	MaxBackoff     Duration `json:"maxBackoff" yaml:"maxBackoff"`
+	RetryPOST      bool     `json:"retryPost" yaml:"retryPost"`
+}
+
+// ProxyHealthConfig see flaresolverr.ProxyHealthPolicy.
+type ProxyHealthConfig struct {
+	Window         int      `json:"window" yaml:"window"`
+	MinRequests    int      `json:"minRequests" yaml:"minRequests"`
+	MaxFailureRate float64  `json:"maxFailureRate" yaml:"maxFailureRate"`
+	MaxBlockRate   float64  `json:"maxBlockRate" yaml:"maxBlockRate"`
+	Cooldown       Duration `json:"cooldown" yaml:"cooldown"`
+}
+
+// CacheConfig see flaresolverr.WithResponseCache.
+type CacheConfig struct {
+	TTL        Duration `json:"ttl" yaml:"ttl"`
+	MaxEntries int      `json:"maxEntries" yaml:"maxEntries"`
+
+	// Revalidation see flaresolverr.WithRevalidation.
+	Revalidation bool `json:"revalidation" yaml:"revalidation"`
+}
+
+// DomainConfig see flaresolverr.DomainConfig.
+type DomainConfig struct {
+	Timeout Duration `json:"timeout" yaml:"timeout"`
+	Proxy   string   `json:"proxy" yaml:"proxy"`
+
+	// Session is the session policy: "default", "none" or "perDomain".
+	Session         string   `json:"session" yaml:"session"`
+	CacheTTL        Duration `json:"cacheTTL" yaml:"cacheTTL"`
+	PolitenessDelay Duration `json:"politenessDelay" yaml:"politenessDelay"`
+}
+
+// Duration is a time.Duration written as a string such as "1m30s".
+type Duration time.Duration
+
+// UnmarshalText parses a duration with time.ParseDuration.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalText formats the duration with time.Duration.String.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// NewFromConfig builds a client from the YAML or JSON configuration file at path,
+// overridden by the environment.
+func NewFromConfig(path string) (flaresolverr.Client, error) {
+	config, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.build()
+}
+
+// NewFromReader is like NewFromConfig, reading the configuration from r.
+func NewFromReader(r io.Reader) (flaresolverr.Client, error) {
+	config, err := Load(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.build()
+}
+
+func (c *Config) build() (flaresolverr.Client, error) {
+	if err := c.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	return c.New()
+}
+
+// LoadFile reads the YAML or JSON configuration file at path. Errors wrap ErrInvalidConfig.
+func LoadFile(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	defer f.Close()
+
+	return Load(f)
+}
+
+// Load reads a YAML or JSON configuration from r. Unknown fields are rejected.
+// Errors wrap ErrInvalidConfig.
+func Load(r io.Reader) (*Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	config := new(Config)
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(config)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(config); errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	return config, nil
+}
+
+// ApplyEnv overrides the configuration with the environment variables returned by lookup, e.g. os.LookupEnv:
+//
+//   - FLARESOLVERR_URL sets URL.
+//   - FLARESOLVERR_TIMEOUT sets Timeout, e.g. "90s".
+//   - FLARESOLVERR_BEARER_TOKEN sets BearerToken.
+//   - FLARESOLVERR_PROXIES sets Proxies, separated by commas.
+//   - FLARESOLVERR_RETRY_MAX_ATTEMPTS sets Retry.MaxAttempts.
+//
+// Errors wrap ErrInvalidConfig.
+func (c *Config) ApplyEnv(lookup func(key string) (string, bool)) error {
+	if value, ok := lookup("FLARESOLVERR_URL"); ok {
+		c.URL = value
+	}
+
+	if value, ok := lookup("FLARESOLVERR_TIMEOUT"); ok {
+		if err := c.Timeout.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("%w: FLARESOLVERR_TIMEOUT: %v", ErrInvalidConfig, err)
+		}
+	}
+
+	if value, ok := lookup("FLARESOLVERR_BEARER_TOKEN"); ok {
+		c.BearerToken = value
+	}
+
+	if value, ok := lookup("FLARESOLVERR_PROXIES"); ok {
+		c.Proxies = nil
+		for _, proxy := range strings.Split(value, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				c.Proxies = append(c.Proxies, proxy)
+			}
+		}
+	}
+
+	if value, ok := lookup("FLARESOLVERR_RETRY_MAX_ATTEMPTS"); ok {
+		attempts, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%w: FLARESOLVERR_RETRY_MAX_ATTEMPTS: %v", ErrInvalidConfig, err)
+		}
+		if c.Retry == nil {
+			c.Retry = new(RetryConfig)
+		}
+		c.Retry.MaxAttempts = attempts
+	}
+
+	return nil
+}
+
+// New builds a client from the configuration, without reading the environment.
+// Errors wrap ErrInvalidConfig.
+func (c *Config) New() (flaresolverr.Client, error) {
+	opts, err := c.Options()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := flaresolverr.NewWithValidation(c.URL, time.Duration(c.Timeout), nil, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	return client, nil
+}
+
+// Options returns the client options of the configuration, to build a client with flaresolverr.New
+// and options set in code. Errors wrap ErrInvalidConfig.
+func (c *Config) Options() ([]flaresolverr.Option, error) {
+	var opts []flaresolverr.Option
+	if c.TimeoutPadding > 0 {
+		opts = append(opts, flaresolverr.WithTimeoutPadding(time.Duration(c.TimeoutPadding)))
+	}
+
+	for key, value := range c.Headers {
+		opts = append(opts, flaresolverr.WithHeader(key, value))
+	}
+
+	if c.BearerToken != "" {
+		opts = append(opts, flaresolverr.WithBearerToken(c.BearerToken))
+	}
+
+	if c.Retry != nil {
+		opts = append(opts, flaresolverr.WithRetryPolicy(flaresolverr.RetryPolicy{
+			MaxAttempts:    c.Retry.MaxAttempts,
+			InitialBackoff: time.Duration(c.Retry.InitialBackoff),
+			MaxBackoff:     time.Duration(c.Retry.MaxBackoff),
+			RetryPOST:      c.Retry.RetryPOST,
+		}))
+	}
+
+	if len(c.Proxies) > 0 {
+		for _, proxy := range c.Proxies {
+			if _, err := flaresolverr.NormalizeProxy(proxy); err != nil {
+				return nil, fmt.Errorf("%w: proxies: %w", ErrInvalidConfig, err)
+			}
+		}
+
+		var policy flaresolverr.ProxyHealthPolicy
+		if h := c.ProxyHealth; h != nil {
+			policy = flaresolverr.ProxyHealthPolicy{
+				Window:         h.Window,
+				MinRequests:    h.MinRequests,
+				MaxFailureRate: h.MaxFailureRate,
+				MaxBlockRate:   h.MaxBlockRate,
+				Cooldown:       time.Duration(h.Cooldown),
+			}
+		}
+		opts = append(opts, flaresolverr.WithProxyRotation(c.Proxies, policy))
+	}
+
+	if c.Cache != nil {
+		opts = append(opts, flaresolverr.WithResponseCache(time.Duration(c.Cache.TTL), c.Cache.MaxEntries))
+		if c.Cache.Revalidation {
+			opts = append(opts, flaresolverr.WithRevalidation())
+		}
+	}
+
+	if c.PolitenessDelay > 0 {
+		opts = append(opts, flaresolverr.WithPoliteness(time.Duration(c.PolitenessDelay), nil))
+	}
+
+	if c.SessionPerDomainIdle > 0 {
+		opts = append(opts, flaresolverr.WithSessionPerDomain(time.Duration(c.SessionPerDomainIdle)))
+	}
+
+	for domain, d := range c.Domains {
+		session, err := parseSessionPolicy(d.Session)
+		if err != nil {
+			return nil, fmt.Errorf("%w: domains: %s: %v", ErrInvalidConfig, domain, err)
+		}
+
+		opts = append(opts, flaresolverr.WithDomainConfig(domain, flaresolverr.DomainConfig{
+			Timeout:         time.Duration(d.Timeout),
+			Proxy:           d.Proxy,
+			Session:         session,
+			CacheTTL:        time.Duration(d.CacheTTL),
+			PolitenessDelay: time.Duration(d.PolitenessDelay),
+		}))
+	}
+
+	return opts, nil
+}
+
+func parseSessionPolicy(policy string) (flaresolverr.SessionPolicy, error) {
+	switch policy {
+	case "", "default":
+		return flaresolverr.SessionPolicyDefault, nil
+	case "none":
+		return flaresolverr.SessionPolicyNone, nil
+	case "perDomain":
+		return flaresolverr.SessionPolicyPerDomain, nil
+	default:
+		return 0, fmt.Errorf("unknown session policy %q, must be default, none or perDomain", policy)
+	}
+}
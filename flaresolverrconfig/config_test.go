@@ -0,0 +1,190 @@
+package flaresolverrconfig
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+	"github.com/SkYNewZ/go-flaresolverr/flaresolverrtest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+const yamlConfig = `
+url: http://flaresolverr:8191
+timeout: 90s
+retry:
+  maxAttempts: 3
+  initialBackoff: 1s
+proxies:
+  - http://proxy-1:8080
+  - socks5://proxy-2:1080
+cache:
+  ttl: 5m
+  maxEntries: 100
+domains:
+  example.com:
+    timeout: 2m
+    proxy: http://proxy-1:8080
+    session: perDomain
+`
+
+const jsonConfig = `{
+	"url": "http://flaresolverr:8191",
+	"timeout": "90s",
+	"retry": {"maxAttempts": 3, "initialBackoff": "1s"},
+	"proxies": ["http://proxy-1:8080", "socks5://proxy-2:1080"],
+	"cache": {"ttl": "5m", "maxEntries": 100},
+	"domains": {
+		"example.com": {"timeout": "2m", "proxy": "http://proxy-1:8080", "session": "perDomain"}
+	}
+}`
+
+func TestLoad(t *testing.T) {
+	want := &Config{
+		URL:     "http://flaresolverr:8191",
+		Timeout: Duration(90 * time.Second),
+		Retry:   &RetryConfig{MaxAttempts: 3, InitialBackoff: Duration(time.Second)},
+		Proxies: []string{"http://proxy-1:8080", "socks5://proxy-2:1080"},
+		Cache:   &CacheConfig{TTL: Duration(5 * time.Minute), MaxEntries: 100},
+		Domains: map[string]DomainConfig{
+			"example.com": {Timeout: Duration(2 * time.Minute), Proxy: "http://proxy-1:8080", Session: "perDomain"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		want    *Config
+		wantErr bool
+	}{
+		{name: "YAML", data: yamlConfig, want: want},
+		{name: "JSON", data: jsonConfig, want: want},
+		{name: "Empty", data: "", want: &Config{}},
+		{name: "Unknown YAML field", data: "url: http://flaresolverr:8191\nendpoint: http://other:8191\n", wantErr: true},
+		{name: "Unknown JSON field", data: `{"endpoint": "http://other:8191"}`, wantErr: true},
+		{name: "Invalid duration", data: "timeout: 90\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(strings.NewReader(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidConfig) {
+					t.Errorf("Load() error = %v, want ErrInvalidConfig", err)
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfig_ApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"FLARESOLVERR_URL":                "http://other:8191/v1",
+		"FLARESOLVERR_TIMEOUT":            "2m",
+		"FLARESOLVERR_BEARER_TOKEN":       "secret",
+		"FLARESOLVERR_PROXIES":            "http://proxy-3:8080, http://proxy-4:8080",
+		"FLARESOLVERR_RETRY_MAX_ATTEMPTS": "5",
+	}
+	lookup := func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+
+	config, err := Load(strings.NewReader(yamlConfig))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := config.ApplyEnv(lookup); err != nil {
+		t.Fatalf("ApplyEnv() error = %v", err)
+	}
+
+	if config.URL != "http://other:8191/v1" || config.Timeout != Duration(2*time.Minute) || config.BearerToken != "secret" {
+		t.Errorf("ApplyEnv() = %+v", config)
+	}
+	if diff := cmp.Diff([]string{"http://proxy-3:8080", "http://proxy-4:8080"}, config.Proxies); diff != "" {
+		t.Errorf("ApplyEnv() proxies mismatch (-want +got):\n%s", diff)
+	}
+	if config.Retry.MaxAttempts != 5 || config.Retry.InitialBackoff != Duration(time.Second) {
+		t.Errorf("ApplyEnv() retry = %+v", config.Retry)
+	}
+
+	env["FLARESOLVERR_TIMEOUT"] = "soon"
+	if err := config.ApplyEnv(lookup); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("ApplyEnv() error = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestNewFromReader(t *testing.T) {
+	var mu sync.Mutex
+	var commands []*flaresolverrtest.Command
+	srv := flaresolverrtest.NewServer(flaresolverrtest.WithHandler(func(cmd *flaresolverrtest.Command) (int, *flaresolverr.Response) {
+		mu.Lock()
+		commands = append(commands, cmd)
+		mu.Unlock()
+		return flaresolverrtest.Page("<html></html>")(cmd)
+	}))
+	defer srv.Close()
+
+	// the environment takes precedence over the file
+	t.Setenv("FLARESOLVERR_URL", srv.URL)
+	c, err := NewFromReader(strings.NewReader(`
+url: http://unreachable:8191
+timeout: 30s
+domains:
+  example.com:
+    timeout: 45s
+    proxy: http://proxy:8080
+`))
+	if err != nil {
+		t.Fatalf("NewFromReader() error = %v", err)
+	}
+
+	ctx := context.Background()
+	for _, u := range []string{"https://www.example.com", "https://example.org"} {
+		if _, err := c.Get(ctx, u, uuid.Nil); err != nil {
+			t.Fatalf("Get(%s) error = %v", u, err)
+		}
+	}
+
+	got := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		proxy, _ := cmd.Proxy.(string)
+		got = append(got, cmd.URL+" "+proxy+" "+(time.Duration(cmd.MaxTimeout)*time.Millisecond).String())
+	}
+	want := []string{
+		"https://www.example.com http://proxy:8080 45s",
+		"https://example.org  30s",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("commands mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestConfig_New(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{name: "Invalid URL", config: Config{URL: "ftp://flaresolverr:8191"}},
+		{name: "Invalid proxy", config: Config{URL: "http://flaresolverr:8191", Proxies: []string{"ftp://proxy:21"}}},
+		{name: "Unknown session policy", config: Config{URL: "http://flaresolverr:8191", Domains: map[string]DomainConfig{"example.com": {Session: "always"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.config.New(); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("New() error = %v, want ErrInvalidConfig", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+// Code generated by genmock; DO NOT EDIT.
+
+package flaresolverrmock
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+	"github.com/google/uuid"
+)
+
+var _ flaresolverr.Client = (*ClientMock)(nil)
+
+// ClientMock is a mock implementation of flaresolverr.Client.
+//
+// Set the func field of every method the code under test calls. Calling a
+// method whose func field is nil panics.
+type ClientMock struct {
+	// GetFunc mocks the Get method.
+	GetFunc func(ctx context.Context, u string, session uuid.UUID, proxy ...string) (*flaresolverr.Response, error)
+
+	// GetWithOptionsFunc mocks the GetWithOptions method.
+	GetWithOptionsFunc func(ctx context.Context, u string, session uuid.UUID, opts ...flaresolverr.RequestOption) (*flaresolverr.Response, error)
+
+	// PostFunc mocks the Post method.
+	PostFunc func(ctx context.Context, u string, session uuid.UUID, data string, proxy ...string) (*flaresolverr.Response, error)
+
+	// PostWithOptionsFunc mocks the PostWithOptions method.
+	PostWithOptionsFunc func(ctx context.Context, u string, session uuid.UUID, data string, opts ...flaresolverr.RequestOption) (*flaresolverr.Response, error)
+
+	// DownloadFunc mocks the Download method.
+	DownloadFunc func(ctx context.Context, u string, w io.Writer, opts ...flaresolverr.RequestOption) (int64, error)
+
+	// DownloadDirectFunc mocks the DownloadDirect method.
+	DownloadDirectFunc func(ctx context.Context, session uuid.UUID, u string, w io.Writer, opts ...flaresolverr.DownloadOption) (int64, error)
+
+	// SubmitFormFunc mocks the SubmitForm method.
+	SubmitFormFunc func(ctx context.Context, form *flaresolverr.Form, opts ...flaresolverr.RequestOption) (*flaresolverr.Response, error)
+
+	// HTTPClientForFunc mocks the HTTPClientFor method.
+	HTTPClientForFunc func(ctx context.Context, u string, opts ...flaresolverr.RequestOption) (*http.Client, error)
+
+	// GetBatchFunc mocks the GetBatch method.
+	GetBatchFunc func(ctx context.Context, urls []string, opts ...flaresolverr.BatchOption) ([]*flaresolverr.Response, []error)
+
+	// WarmupFunc mocks the Warmup method.
+	WarmupFunc func(ctx context.Context, domains []string, opts ...flaresolverr.BatchOption) error
+
+	// CreateSessionFunc mocks the CreateSession method.
+	CreateSessionFunc func(ctx context.Context, session uuid.UUID, proxy ...string) (*flaresolverr.Response, error)
+
+	// CreateSessionWithOptionsFunc mocks the CreateSessionWithOptions method.
+	CreateSessionWithOptionsFunc func(ctx context.Context, session uuid.UUID, opts ...flaresolverr.RequestOption) (*flaresolverr.Response, error)
+
+	// CreateSessionAutoFunc mocks the CreateSessionAuto method.
+	CreateSessionAutoFunc func(ctx context.Context, opts ...flaresolverr.RequestOption) (flaresolverr.SessionID, error)
+
+	// ListSessionsFunc mocks the ListSessions method.
+	ListSessionsFunc func(ctx context.Context) (*flaresolverr.Response, error)
+
+	// ListSessionsWithOptionsFunc mocks the ListSessionsWithOptions method.
+	ListSessionsWithOptionsFunc func(ctx context.Context, opts ...flaresolverr.RequestOption) (*flaresolverr.Response, error)
+
+	// ListSessionInfoFunc mocks the ListSessionInfo method.
+	ListSessionInfoFunc func(ctx context.Context, opts ...flaresolverr.RequestOption) ([]flaresolverr.SessionInfo, error)
+
+	// DestroySessionFunc mocks the DestroySession method.
+	DestroySessionFunc func(ctx context.Context, session uuid.UUID) error
+
+	// DestroySessionWithOptionsFunc mocks the DestroySessionWithOptions method.
+	DestroySessionWithOptionsFunc func(ctx context.Context, session uuid.UUID, opts ...flaresolverr.RequestOption) error
+
+	// SessionFunc mocks the Session method.
+	SessionFunc func(id uuid.UUID) *flaresolverr.Session
+
+	// FindSessionByLabelFunc mocks the FindSessionByLabel method.
+	FindSessionByLabelFunc func(key string, value string) *flaresolverr.Session
+
+	// ExportSessionsFunc mocks the ExportSessions method.
+	ExportSessionsFunc func() ([]byte, error)
+
+	// ImportSessionsFunc mocks the ImportSessions method.
+	ImportSessionsFunc func(data []byte) error
+
+	// HealthFunc mocks the Health method.
+	HealthFunc func(ctx context.Context) error
+
+	// StatsFunc mocks the Stats method.
+	StatsFunc func() flaresolverr.Stats
+
+	// CloseFunc mocks the Close method.
+	CloseFunc func(ctx context.Context) error
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// Calls returns how many times method has been called.
+func (m *ClientMock) Calls(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls[method]
+}
+
+func (m *ClientMock) record(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.calls == nil {
+		m.calls = make(map[string]int)
+	}
+	m.calls[method]++
+}
+
+// Get calls GetFunc.
+func (m *ClientMock) Get(ctx context.Context, u string, session uuid.UUID, proxy ...string) (*flaresolverr.Response, error) {
+	if m.GetFunc == nil {
+		panic("ClientMock.GetFunc: method is nil but Client.Get was just called")
+	}
+	m.record("Get")
+	return m.GetFunc(ctx, u, session, proxy...)
+}
+
+// GetWithOptions calls GetWithOptionsFunc.
+func (m *ClientMock) GetWithOptions(ctx context.Context, u string, session uuid.UUID, opts ...flaresolverr.RequestOption) (*flaresolverr.Response, error) {
+	if m.GetWithOptionsFunc == nil {
+		panic("ClientMock.GetWithOptionsFunc: method is nil but Client.GetWithOptions was just called")
+	}
+	m.record("GetWithOptions")
+	return m.GetWithOptionsFunc(ctx, u, session, opts...)
+}
+
+// Post calls PostFunc.
+func (m *ClientMock) Post(ctx context.Context, u string, session uuid.UUID, data string, proxy ...string) (*flaresolverr.Response, error) {
+	if m.PostFunc == nil {
+		panic("ClientMock.PostFunc: method is nil but Client.Post was just called")
+	}
+	m.record("Post")
+	return m.PostFunc(ctx, u, session, data, proxy...)
+}
+
+// PostWithOptions calls PostWithOptionsFunc.
+func (m *ClientMock) PostWithOptions(ctx context.Context, u string, session uuid.UUID, data string, opts ...flaresolverr.RequestOption) (*flaresolverr.Response, error) {
+	if m.PostWithOptionsFunc == nil {
+		panic("ClientMock.PostWithOptionsFunc: method is nil but Client.PostWithOptions was just called")
+	}
+	m.record("PostWithOptions")
+	return m.PostWithOptionsFunc(ctx, u, session, data, opts...)
+}
+
+// Download calls DownloadFunc.
+func (m *ClientMock) Download(ctx context.Context, u string, w io.Writer, opts ...flaresolverr.RequestOption) (int64, error) {
+	if m.DownloadFunc == nil {
+		panic("ClientMock.DownloadFunc: method is nil but Client.Download was just called")
+	}
+	m.record("Download")
+	return m.DownloadFunc(ctx, u, w, opts...)
+}
+
+// DownloadDirect calls DownloadDirectFunc.
+func (m *ClientMock) DownloadDirect(ctx context.Context, session uuid.UUID, u string, w io.Writer, opts ...flaresolverr.DownloadOption) (int64, error) {
+	if m.DownloadDirectFunc == nil {
+		panic("ClientMock.DownloadDirectFunc: method is nil but Client.DownloadDirect was just called")
+	}
+	m.record("DownloadDirect")
+	return m.DownloadDirectFunc(ctx, session, u, w, opts...)
+}
+
+// SubmitForm calls SubmitFormFunc.
+func (m *ClientMock) SubmitForm(ctx context.Context, form *flaresolverr.Form, opts ...flaresolverr.RequestOption) (*flaresolverr.Response, error) {
+	if m.SubmitFormFunc == nil {
+		panic("ClientMock.SubmitFormFunc: method is nil but Client.SubmitForm was just called")
+	}
+	m.record("SubmitForm")
+	return m.SubmitFormFunc(ctx, form, opts...)
+}
+
+// HTTPClientFor calls HTTPClientForFunc.
+func (m *ClientMock) HTTPClientFor(ctx context.Context, u string, opts ...flaresolverr.RequestOption) (*http.Client, error) {
+	if m.HTTPClientForFunc == nil {
+		panic("ClientMock.HTTPClientForFunc: method is nil but Client.HTTPClientFor was just called")
+	}
+	m.record("HTTPClientFor")
+	return m.HTTPClientForFunc(ctx, u, opts...)
+}
+
+// GetBatch calls GetBatchFunc.
+func (m *ClientMock) GetBatch(ctx context.Context, urls []string, opts ...flaresolverr.BatchOption) ([]*flaresolverr.Response, []error) {
+	if m.GetBatchFunc == nil {
+		panic("ClientMock.GetBatchFunc: method is nil but Client.GetBatch was just called")
+	}
+	m.record("GetBatch")
+	return m.GetBatchFunc(ctx, urls, opts...)
+}
+
+// Warmup calls WarmupFunc.
+func (m *ClientMock) Warmup(ctx context.Context, domains []string, opts ...flaresolverr.BatchOption) error {
+	if m.WarmupFunc == nil {
+		panic("ClientMock.WarmupFunc: method is nil but Client.Warmup was just called")
+	}
+	m.record("Warmup")
+	return m.WarmupFunc(ctx, domains, opts...)
+}
+
+// CreateSession calls CreateSessionFunc.
+func (m *ClientMock) CreateSession(ctx context.Context, session uuid.UUID, proxy ...string) (*flaresolverr.Response, error) {
+	if m.CreateSessionFunc == nil {
+		panic("ClientMock.CreateSessionFunc: method is nil but Client.CreateSession was just called")
+	}
+	m.record("CreateSession")
+	return m.CreateSessionFunc(ctx, session, proxy...)
+}
+
+// CreateSessionWithOptions calls CreateSessionWithOptionsFunc.
+func (m *ClientMock) CreateSessionWithOptions(ctx context.Context, session uuid.UUID, opts ...flaresolverr.RequestOption) (*flaresolverr.Response, error) {
+	if m.CreateSessionWithOptionsFunc == nil {
+		panic("ClientMock.CreateSessionWithOptionsFunc: method is nil but Client.CreateSessionWithOptions was just called")
+	}
+	m.record("CreateSessionWithOptions")
+	return m.CreateSessionWithOptionsFunc(ctx, session, opts...)
+}
+
+// CreateSessionAuto calls CreateSessionAutoFunc.
+func (m *ClientMock) CreateSessionAuto(ctx context.Context, opts ...flaresolverr.RequestOption) (flaresolverr.SessionID, error) {
+	if m.CreateSessionAutoFunc == nil {
+		panic("ClientMock.CreateSessionAutoFunc: method is nil but Client.CreateSessionAuto was just called")
+	}
+	m.record("CreateSessionAuto")
+	return m.CreateSessionAutoFunc(ctx, opts...)
+}
+
+// ListSessions calls ListSessionsFunc.
+func (m *ClientMock) ListSessions(ctx context.Context) (*flaresolverr.Response, error) {
+	if m.ListSessionsFunc == nil {
+		panic("ClientMock.ListSessionsFunc: method is nil but Client.ListSessions was just called")
+	}
+	m.record("ListSessions")
+	return m.ListSessionsFunc(ctx)
+}
+
+// ListSessionsWithOptions calls ListSessionsWithOptionsFunc.
+func (m *ClientMock) ListSessionsWithOptions(ctx context.Context, opts ...flaresolverr.RequestOption) (*flaresolverr.Response, error) {
+	if m.ListSessionsWithOptionsFunc == nil {
+		panic("ClientMock.ListSessionsWithOptionsFunc: method is nil but Client.ListSessionsWithOptions was just called")
+	}
+	m.record("ListSessionsWithOptions")
+	return m.ListSessionsWithOptionsFunc(ctx, opts...)
+}
+
+// ListSessionInfo calls ListSessionInfoFunc.
+func (m *ClientMock) ListSessionInfo(ctx context.Context, opts ...flaresolverr.RequestOption) ([]flaresolverr.SessionInfo, error) {
+	if m.ListSessionInfoFunc == nil {
+		panic("ClientMock.ListSessionInfoFunc: method is nil but Client.ListSessionInfo was just called")
+	}
+	m.record("ListSessionInfo")
+	return m.ListSessionInfoFunc(ctx, opts...)
+}
+
+// DestroySession calls DestroySessionFunc.
+func (m *ClientMock) DestroySession(ctx context.Context, session uuid.UUID) error {
+	if m.DestroySessionFunc == nil {
+		panic("ClientMock.DestroySessionFunc: method is nil but Client.DestroySession was just called")
+	}
+	m.record("DestroySession")
+	return m.DestroySessionFunc(ctx, session)
+}
+
+// DestroySessionWithOptions calls DestroySessionWithOptionsFunc.
+func (m *ClientMock) DestroySessionWithOptions(ctx context.Context, session uuid.UUID, opts ...flaresolverr.RequestOption) error {
+	if m.DestroySessionWithOptionsFunc == nil {
+		panic("ClientMock.DestroySessionWithOptionsFunc: method is nil but Client.DestroySessionWithOptions was just called")
+	}
+	m.record("DestroySessionWithOptions")
+	return m.DestroySessionWithOptionsFunc(ctx, session, opts...)
+}
+
+// Session calls SessionFunc.
+func (m *ClientMock) Session(id uuid.UUID) *flaresolverr.Session {
+	if m.SessionFunc == nil {
+		panic("ClientMock.SessionFunc: method is nil but Client.Session was just called")
+	}
+	m.record("Session")
+	return m.SessionFunc(id)
+}
+
+// FindSessionByLabel calls FindSessionByLabelFunc.
+func (m *ClientMock) FindSessionByLabel(key string, value string) *flaresolverr.Session {
+	if m.FindSessionByLabelFunc == nil {
+		panic("ClientMock.FindSessionByLabelFunc: method is nil but Client.FindSessionByLabel was just called")
+	}
+	m.record("FindSessionByLabel")
+	return m.FindSessionByLabelFunc(key, value)
+}
+
+// ExportSessions calls ExportSessionsFunc.
+func (m *ClientMock) ExportSessions() ([]byte, error) {
+	if m.ExportSessionsFunc == nil {
+		panic("ClientMock.ExportSessionsFunc: method is nil but Client.ExportSessions was just called")
+	}
+	m.record("ExportSessions")
+	return m.ExportSessionsFunc()
+}
+
+// ImportSessions calls ImportSessionsFunc.
+func (m *ClientMock) ImportSessions(data []byte) error {
+	if m.ImportSessionsFunc == nil {
+		panic("ClientMock.ImportSessionsFunc: method is nil but Client.ImportSessions was just called")
+	}
+	m.record("ImportSessions")
+	return m.ImportSessionsFunc(data)
+}
+
+// Health calls HealthFunc.
+func (m *ClientMock) Health(ctx context.Context) error {
+	if m.HealthFunc == nil {
+		panic("ClientMock.HealthFunc: method is nil but Client.Health was just called")
+	}
+	m.record("Health")
+	return m.HealthFunc(ctx)
+}
+
+// Stats calls StatsFunc.
+func (m *ClientMock) Stats() flaresolverr.Stats {
+	if m.StatsFunc == nil {
+		panic("ClientMock.StatsFunc: method is nil but Client.Stats was just called")
+	}
+	m.record("Stats")
+	return m.StatsFunc()
+}
+
+// Close calls CloseFunc.
+func (m *ClientMock) Close(ctx context.Context) error {
+	if m.CloseFunc == nil {
+		panic("ClientMock.CloseFunc: method is nil but Client.Close was just called")
+	}
+	m.record("Close")
+	return m.CloseFunc(ctx)
+}
@@ -0,0 +1,7 @@
+// Package flaresolverrmock provides generated implementations of flaresolverr.Client for tests.
+//
+// ClientMock lets tests script the behaviour of every method and count calls,
+// Noop can be used where a client is required but never exercised.
+//
+// Both are regenerated with go generate whenever the Client interface changes.
+package flaresolverrmock
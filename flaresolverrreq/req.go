@@ -0,0 +1,29 @@
+// Package flaresolverrreq plugs FlareSolverr into imroc/req clients.
+//
+// Requests are made directly, and only go through FlareSolverr when a challenge is detected,
+// as with flaresolverr.HybridClient. The solved cookies and user agent are then sent with
+// every request to the same hostname.
+//
+// The req transport, with its TLS and HTTP fingerprint settings, is kept for the direct requests:
+//
+//	r := req.C().ImpersonateChrome()
+//	r.GetTransport().WrapRoundTrip(flaresolverrreq.RoundTripWrapper(c))
+//	resp, err := r.R().Get("https://example.com")
+//
+// The package does not depend on req, RoundTripWrapper returns a function
+// usable as a req.HttpRoundTripWrapper.
+package flaresolverrreq
+
+import (
+	"net/http"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+)
+
+// RoundTripWrapper returns a req transport middleware solving challenges with c.
+// opts are applied to the FlareSolverr requests.
+func RoundTripWrapper(c flaresolverr.Client, opts ...flaresolverr.RequestOption) func(rt http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return flaresolverr.NewTransport(c, rt, opts...)
+	}
+}
@@ -0,0 +1,79 @@
+package flaresolverrreq
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+	"github.com/SkYNewZ/go-flaresolverr/flaresolverrtest"
+)
+
+func TestRoundTripWrapper(t *testing.T) {
+	const userAgent = "Mozilla/5.0 (X11; Linux x86_64)"
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("cf_clearance"); err != nil || cookie.Value != "solved" || r.UserAgent() != userAgent {
+			w.Header().Set("cf-mitigated", "challenge")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("protected"))
+	}))
+	defer target.Close()
+
+	var commands atomic.Int32
+	srv := flaresolverrtest.NewServer(flaresolverrtest.WithHandler(func(cmd *flaresolverrtest.Command) (int, *flaresolverr.Response) {
+		commands.Add(1)
+		return http.StatusOK, &flaresolverr.Response{Status: flaresolverr.StatusOK, Solution: &flaresolverr.ResponseSolution{
+			URL:       cmd.URL,
+			Status:    http.StatusOK,
+			UserAgent: userAgent,
+			Cookies:   []flaresolverr.Cookie{{Name: "cf_clearance", Value: "solved", Expires: float64(time.Now().Add(time.Hour).Unix())}},
+		}}
+	}))
+	defer srv.Close()
+
+	// what req does with its transport middlewares
+	var base atomic.Int32
+	rt := RoundTripWrapper(flaresolverr.New(srv.Endpoint(), time.Minute, srv.Client()))(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		base.Add(1)
+		return target.Client().Transport.RoundTrip(req)
+	}))
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest(http.MethodGet, target.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(body) != "protected" {
+			t.Errorf("RoundTrip() = %d %q, want %d %q", resp.StatusCode, body, http.StatusOK, "protected")
+		}
+	}
+
+	if got := commands.Load(); got != 1 {
+		t.Errorf("sent %d FlareSolverr commands, want 1", got)
+	}
+
+	// challenged, retried once solved, then direct with the cached clearance
+	if got := base.Load(); got != 3 {
+		t.Errorf("made %d direct requests, want 3", got)
+	}
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
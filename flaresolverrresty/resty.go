@@ -0,0 +1,26 @@
+// Package flaresolverrresty plugs FlareSolverr into go-resty clients.
+//
+// Requests are made directly, and only go through FlareSolverr when a challenge is detected,
+// as with flaresolverr.HybridClient. The solved cookies and user agent are then sent with
+// every request to the same hostname.
+//
+//	r := flaresolverrresty.New(c)
+//	resp, err := r.R().Get("https://example.com")
+package flaresolverrresty
+
+import (
+	"github.com/SkYNewZ/go-flaresolverr"
+	"github.com/go-resty/resty/v2"
+)
+
+// New returns a resty client solving challenges with c.
+// opts are applied to the FlareSolverr requests.
+func New(c flaresolverr.Client, opts ...flaresolverr.RequestOption) *resty.Client {
+	return Wrap(resty.New(), c, opts...)
+}
+
+// Wrap makes r solve challenges with c. The current transport of r is kept for the direct requests.
+// opts are applied to the FlareSolverr requests.
+func Wrap(r *resty.Client, c flaresolverr.Client, opts ...flaresolverr.RequestOption) *resty.Client {
+	return r.SetTransport(flaresolverr.NewTransport(c, r.GetClient().Transport, opts...))
+}
@@ -0,0 +1,85 @@
+package flaresolverrresty
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+	"github.com/SkYNewZ/go-flaresolverr/flaresolverrtest"
+	"github.com/go-resty/resty/v2"
+)
+
+const userAgent = "Mozilla/5.0 (X11; Linux x86_64)"
+
+// newProtectedServer returns a target challenging the requests without the solved cookie and user agent.
+func newProtectedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("cf_clearance"); err != nil || cookie.Value != "solved" || r.UserAgent() != userAgent {
+			w.Header().Set("cf-mitigated", "challenge")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		_ = r.ParseForm()
+		_, _ = w.Write([]byte("protected " + r.PostForm.Get("foo")))
+	}))
+	t.Cleanup(target.Close)
+
+	return target
+}
+
+func newFlareSolverr(t *testing.T, commands *atomic.Int32) flaresolverr.Client {
+	t.Helper()
+
+	srv := flaresolverrtest.NewServer(flaresolverrtest.WithHandler(func(cmd *flaresolverrtest.Command) (int, *flaresolverr.Response) {
+		commands.Add(1)
+		return http.StatusOK, &flaresolverr.Response{Status: flaresolverr.StatusOK, Solution: &flaresolverr.ResponseSolution{
+			URL:       cmd.URL,
+			Status:    http.StatusOK,
+			UserAgent: userAgent,
+			Cookies:   []flaresolverr.Cookie{{Name: "cf_clearance", Value: "solved", Expires: float64(time.Now().Add(time.Hour).Unix())}},
+		}}
+	}))
+	t.Cleanup(srv.Close)
+
+	return flaresolverr.New(srv.Endpoint(), time.Minute, srv.Client())
+}
+
+func TestWrap(t *testing.T) {
+	target := newProtectedServer(t)
+
+	var commands atomic.Int32
+	r := Wrap(resty.NewWithClient(target.Client()), newFlareSolverr(t, &commands))
+
+	tests := []struct {
+		name string
+		do   func(req *resty.Request) (*resty.Response, error)
+		want string
+	}{
+		{name: "get", do: func(req *resty.Request) (*resty.Response, error) { return req.Get(target.URL) }, want: "protected"},
+		{name: "post", do: func(req *resty.Request) (*resty.Response, error) {
+			return req.SetFormData(map[string]string{"foo": "bar"}).Post(target.URL)
+		}, want: "protected bar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.do(r.R())
+			if err != nil {
+				t.Fatalf("request error = %v", err)
+			}
+
+			if resp.StatusCode() != http.StatusOK || resp.String() != tt.want {
+				t.Errorf("response = %d %q, want %d %q", resp.StatusCode(), resp.String(), http.StatusOK, tt.want)
+			}
+		})
+	}
+
+	if got := commands.Load(); got != 1 {
+		t.Errorf("sent %d FlareSolverr commands, want 1", got)
+	}
+}
@@ -0,0 +1,240 @@
+// Package flaresolverrtest provides an in-memory FlareSolverr server for tests and benchmarks.
+//
+// The server implements the session commands and answers request commands
+// without a browser, so clients can be exercised without network or a live FlareSolverr.
+//
+//	srv := flaresolverrtest.NewServer()
+//	defer srv.Close()
+//
+//	c := flaresolverr.New(srv.Endpoint(), time.Minute, srv.Client())
+package flaresolverrtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+	"github.com/google/uuid"
+)
+
+// Version is the FlareSolverr version reported by the server.
+const Version = "3.3.21"
+
+// Command is a command received by the server.
+type Command struct {
+	Cmd        flaresolverr.Command `json:"cmd"`
+	URL        string               `json:"url"`
+	Session    string               `json:"session"`
+	MaxTimeout int                  `json:"maxTimeout"`
+	Proxy      any                  `json:"proxy"`
+	PostData   string               `json:"postData"`
+}
+
+// HandlerFunc answers the request.get and request.post commands,
+// returning the HTTP status and the response sent to the client.
+type HandlerFunc func(cmd *Command) (int, *flaresolverr.Response)
+
+// Option configures a Server.
+type Option func(s *Server)
+
+// WithHandler sets the handler answering request commands, see Page for the default one.
+func WithHandler(handler HandlerFunc) Option {
+	return func(s *Server) {
+		s.handler = handler
+	}
+}
+
+// WithLatency delays every command, simulating the time taken by the browser.
+func WithLatency(latency time.Duration) Option {
+	return func(s *Server) {
+		s.latency = latency
+	}
+}
+
+// Server is an in-memory FlareSolverr server.
+type Server struct {
+	*httptest.Server
+
+	handler  HandlerFunc
+	latency  time.Duration
+	commands atomic.Int64
+
+	mu       sync.Mutex
+	sessions map[string]struct{}
+}
+
+// NewServer starts a server answering request commands with an empty page. Close it when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		handler:  Page("<html><body></body></html>"),
+		sessions: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1", s.serveCommand)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Endpoint returns the /v1 endpoint to give to flaresolverr.New.
+func (s *Server) Endpoint() string {
+	return s.URL + "/v1"
+}
+
+// Commands returns the number of commands received.
+func (s *Server) Commands() int64 {
+	return s.commands.Load()
+}
+
+// Sessions returns the active sessions whose ID is a UUID, see SessionIDs for every session.
+func (s *Server) Sessions() []uuid.UUID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions := make([]uuid.UUID, 0, len(s.sessions))
+	for session := range s.sessions {
+		if id, err := uuid.Parse(session); err == nil {
+			sessions = append(sessions, id)
+		}
+	}
+
+	return sessions
+}
+
+// SessionIDs returns the active sessions.
+func (s *Server) SessionIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions := make([]string, 0, len(s.sessions))
+	for session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+
+	return sessions
+}
+
+// listResponse is a sessions.list response, listing every session whatever its ID.
+type listResponse struct {
+	*flaresolverr.Response
+	Sessions []string `json:"sessions"`
+}
+
+func (s *Server) serveCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var cmd Command
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("Error: invalid command: "+err.Error()))
+		return
+	}
+	s.commands.Add(1)
+
+	if s.latency > 0 {
+		select {
+		case <-time.After(s.latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	start := time.Now()
+	status, resp := s.handle(&cmd)
+	resp.StartTimestamp = start.UnixMilli()
+	resp.EndTimestamp = time.Now().UnixMilli()
+	resp.Version = Version
+
+	if cmd.Cmd == flaresolverr.CommandSessionslist {
+		writeJSON(w, status, listResponse{Response: resp, Sessions: s.SessionIDs()})
+		return
+	}
+	writeJSON(w, status, resp)
+}
+
+func (s *Server) handle(cmd *Command) (int, *flaresolverr.Response) {
+	switch cmd.Cmd {
+	case flaresolverr.CommandSessionscreate:
+		session := cmd.Session
+		if session == "" {
+			session = uuid.NewString()
+		}
+
+		s.mu.Lock()
+		_, exists := s.sessions[session]
+		s.sessions[session] = struct{}{}
+		s.mu.Unlock()
+
+		message := "Session created successfully."
+		if exists {
+			message = "Session already exists."
+		}
+		return http.StatusOK, &flaresolverr.Response{Status: flaresolverr.StatusOK, Message: message, Session: session}
+	case flaresolverr.CommandSessionslist:
+		return http.StatusOK, &flaresolverr.Response{Status: flaresolverr.StatusOK}
+	case flaresolverr.CommandSessionsdestroy:
+		s.mu.Lock()
+		_, exists := s.sessions[cmd.Session]
+		delete(s.sessions, cmd.Session)
+		s.mu.Unlock()
+
+		if !exists {
+			return http.StatusInternalServerError, errorResponse("Error: The session doesn't exist.")
+		}
+		return http.StatusOK, &flaresolverr.Response{Status: flaresolverr.StatusOK, Message: "The session has been removed."}
+	case flaresolverr.CommandRequestget, flaresolverr.CommandRequestpost:
+		if cmd.Session != "" && !s.hasSession(cmd.Session) {
+			return http.StatusInternalServerError, errorResponse("Error: The session doesn't exist.")
+		}
+		return s.handler(cmd)
+	default:
+		return http.StatusBadRequest, errorResponse("Error: Request parameter 'cmd' = '" + cmd.Cmd.String() + "' is invalid.")
+	}
+}
+
+func (s *Server) hasSession(session string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.sessions[session]
+	return ok
+}
+
+// Page returns a handler answering every request with body, as a page not protected by a challenge.
+func Page(body string) HandlerFunc {
+	return func(cmd *Command) (int, *flaresolverr.Response) {
+		return http.StatusOK, &flaresolverr.Response{
+			Status:  flaresolverr.StatusOK,
+			Message: "Challenge not detected!",
+			Solution: &flaresolverr.ResponseSolution{
+				URL:       cmd.URL,
+				Status:    http.StatusOK,
+				Response:  body,
+				UserAgent: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			},
+		}
+	}
+}
+
+func errorResponse(message string) *flaresolverr.Response {
+	return &flaresolverr.Response{Status: flaresolverr.StatusError, Message: message}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,75 @@
+package flaresolverrtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SkYNewZ/go-flaresolverr"
+	"github.com/google/uuid"
+)
+
+func TestServer(t *testing.T) {
+	srv := NewServer(WithHandler(Page("<p>hello</p>")))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := flaresolverr.New(srv.Endpoint(), time.Second, srv.Client())
+
+	if err := c.Health(ctx); err != nil {
+		t.Fatalf("Health() error = %v", err)
+	}
+
+	session := uuid.New()
+	if _, err := c.CreateSession(ctx, session); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	resp, err := c.ListSessions(ctx)
+	if err != nil || len(resp.Sessions) != 1 || resp.Sessions[0] != session {
+		t.Fatalf("ListSessions() = %v, %v, want [%s]", resp, err, session)
+	}
+
+	resp, err = c.Get(ctx, "https://example.com", session)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if resp.Solution.URL != "https://example.com" || resp.Solution.Response != "<p>hello</p>" || resp.Version != Version {
+		t.Errorf("Get() = %+v, want the page", resp.Solution)
+	}
+
+	if err := c.DestroySession(ctx, session); err != nil {
+		t.Fatalf("DestroySession() error = %v", err)
+	}
+
+	if err := c.DestroySession(ctx, session); !errors.Is(err, flaresolverr.ErrSessionNotFound) {
+		t.Errorf("DestroySession() error = %v, want %v", err, flaresolverr.ErrSessionNotFound)
+	}
+
+	if _, err := c.Get(ctx, "https://example.com", session); !errors.Is(err, flaresolverr.ErrSessionNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, flaresolverr.ErrSessionNotFound)
+	}
+
+	if got := srv.Commands(); got != 6 {
+		t.Errorf("Commands() = %d, want 6", got)
+	}
+}
+
+func TestServer_nonUUIDSessions(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := flaresolverr.New(srv.Endpoint(), time.Second, srv.Client())
+
+	if _, err := c.CreateSessionWithOptions(ctx, uuid.Nil, flaresolverr.WithSessionID("session_1714")); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	infos, err := c.ListSessionInfo(ctx)
+	if err != nil || len(infos) != 1 || infos[0].ID != "session_1714" {
+		t.Fatalf("ListSessionInfo() = %v, %v, want [session_1714]", infos, err)
+	}
+}
@@ -0,0 +1,232 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ErrFormNotFound when the solution body does not contain the requested form.
+var ErrFormNotFound = errors.New("form not found")
+
+// Form is an HTML form found in a solution body.
+type Form struct {
+	// ID and Name are the form id and name attributes.
+	ID   string
+	Name string
+
+	// Action is the absolute URL the form is submitted to.
+	Action string
+
+	// Method is the upper-cased form method, GET or POST.
+	Method string
+
+	// Values holds the form fields with their default values,
+	// including hidden inputs such as CSRF tokens.
+	Values url.Values
+}
+
+// Set sets a form field, replacing any existing value.
+func (f *Form) Set(name, value string) {
+	f.Values.Set(name, value)
+}
+
+// Encode returns the form fields as application/x-www-form-urlencoded data.
+func (f *Form) Encode() string {
+	return f.Values.Encode()
+}
+
+// Forms parses the forms found in the solution body.
+func (s *ResponseSolution) Forms() ([]*Form, error) {
+	doc, err := s.Document()
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse HTML: %w", err)
+	}
+
+	return parseForms(doc, s.URL)
+}
+
+// Form returns the first form in the solution body whose id or name is nameOrID.
+// An empty nameOrID returns the first form.
+func (s *ResponseSolution) Form(nameOrID string) (*Form, error) {
+	forms, err := s.Forms()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, form := range forms {
+		if nameOrID == "" || form.ID == nameOrID || form.Name == nameOrID {
+			return form, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrFormNotFound, nameOrID)
+}
+
+// ParseForms parses the forms found in an HTML body.
+// Relative actions are resolved against baseURL.
+func ParseForms(body, baseURL string) ([]*Form, error) {
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse HTML: %w", err)
+	}
+
+	return parseForms(doc, baseURL)
+}
+
+func parseForms(doc *html.Node, baseURL string) ([]*Form, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	var forms []*Form
+	var walk func(n *html.Node, form *Form)
+	walk = func(n *html.Node, form *Form) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Form:
+				form = newForm(n, base)
+				forms = append(forms, form)
+			case atom.Input:
+				if form != nil {
+					addInput(form, n)
+				}
+			case atom.Textarea:
+				if form != nil && attr(n, "name") != "" {
+					form.Values.Add(attr(n, "name"), text(n))
+				}
+			case atom.Select:
+				if form != nil && attr(n, "name") != "" {
+					addSelect(form, n)
+				}
+				return
+			}
+		}
+
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child, form)
+		}
+	}
+	walk(doc, nil)
+
+	return forms, nil
+}
+
+func newForm(n *html.Node, base *url.URL) *Form {
+	form := &Form{
+		ID:     attr(n, "id"),
+		Name:   attr(n, "name"),
+		Action: base.String(),
+		Method: http.MethodGet,
+		Values: make(url.Values),
+	}
+
+	if action, err := base.Parse(attr(n, "action")); err == nil {
+		form.Action = action.String()
+	}
+
+	if strings.EqualFold(attr(n, "method"), http.MethodPost) {
+		form.Method = http.MethodPost
+	}
+
+	return form
+}
+
+func addInput(form *Form, n *html.Node) {
+	name := attr(n, "name")
+	if name == "" {
+		return
+	}
+
+	switch strings.ToLower(attr(n, "type")) {
+	case "submit", "button", "image", "reset", "file":
+		return
+	case "checkbox", "radio":
+		if !hasAttr(n, "checked") {
+			return
+		}
+
+		value, ok := lookupAttr(n, "value")
+		if !ok {
+			value = "on"
+		}
+		form.Values.Add(name, value)
+	default:
+		form.Values.Add(name, attr(n, "value"))
+	}
+}
+
+func addSelect(form *Form, n *html.Node) {
+	var first, selected *html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && child.DataAtom == atom.Option {
+				if first == nil {
+					first = child
+				}
+				if selected == nil && hasAttr(child, "selected") {
+					selected = child
+				}
+				continue
+			}
+			walk(child)
+		}
+	}
+	walk(n)
+
+	if selected == nil {
+		selected = first
+	}
+
+	if selected == nil {
+		return
+	}
+
+	value, ok := lookupAttr(selected, "value")
+	if !ok {
+		value = strings.TrimSpace(text(selected))
+	}
+	form.Values.Add(attr(n, "name"), value)
+}
+
+// SubmitForm submits the form through FlareSolverr.
+// Use WithSession to submit it within the session the form was retrieved from.
+func (c *client) SubmitForm(ctx context.Context, form *Form, opts ...RequestOption) (*Response, error) {
+	o := c.requestOptionsFor(ctx, form.Action, opts)
+	cmd := &flaresolverrCommand{
+		Cmd:              CommandRequestpost,
+		URL:              form.Action,
+		Session:          o.sessionFor(uuid.Nil),
+		Proxy:            o.proxy,
+		UserAgent:        o.userAgent,
+		WaitInSeconds:    o.waitInSeconds(),
+		WaitForSelector:  o.waitForSelector,
+		ReturnScreenshot: o.screenshot,
+		ReturnBase64:     o.base64,
+	}
+
+	if form.Method == http.MethodPost {
+		cmd.PostData = form.Encode()
+		return c.follow(ctx, cmd, o)
+	}
+
+	u, err := url.Parse(form.Action)
+	if err != nil {
+		return nil, fmt.Errorf("invalid form action: %w", err)
+	}
+
+	u.RawQuery = form.Encode()
+	cmd.Cmd = CommandRequestget
+	cmd.URL = u.String()
+
+	return c.follow(ctx, cmd, o)
+}
@@ -0,0 +1,170 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
+)
+
+const loginPage = `<html><body>
+<form id="search" action="/search"><input name="q"></form>
+<form name="login" action="/login?next=%2F" method="post">
+	<input type="hidden" name="csrf_token" value="abc123">
+	<input type="text" name="username">
+	<input type="password" name="password">
+	<input type="checkbox" name="remember" checked>
+	<input type="checkbox" name="newsletter" value="yes">
+	<select name="lang"><option value="en">English</option><option value="fr" selected>Français</option></select>
+	<textarea name="comment">hello</textarea>
+	<input type="submit" name="submit" value="Log in">
+</form>
+</body></html>`
+
+func TestResponseSolution_Form(t *testing.T) {
+	solution := &ResponseSolution{URL: "https://example.com/account/", Response: loginPage}
+
+	tests := []struct {
+		name     string
+		nameOrID string
+		want     *Form
+		wantErr  error
+	}{
+		{
+			name:     "First form",
+			nameOrID: "",
+			want: &Form{
+				ID:     "search",
+				Action: "https://example.com/search",
+				Method: http.MethodGet,
+				Values: url.Values{"q": {""}},
+			},
+		},
+		{
+			name:     "Form by name",
+			nameOrID: "login",
+			want: &Form{
+				Name:   "login",
+				Action: "https://example.com/login?next=%2F",
+				Method: http.MethodPost,
+				Values: url.Values{
+					"csrf_token": {"abc123"},
+					"username":   {""},
+					"password":   {""},
+					"remember":   {"on"},
+					"lang":       {"fr"},
+					"comment":    {"hello"},
+				},
+			},
+		},
+		{
+			name:     "Form not found",
+			nameOrID: "register",
+			wantErr:  ErrFormNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := solution.Form(tt.nameOrID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Form() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Form() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_client_SubmitForm(t *testing.T) {
+	session := uuid.New()
+	var got *flaresolverrCommand
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		got = cmd
+		return http.StatusOK, &Response{Status: "ok"}
+	})
+
+	tests := []struct {
+		name string
+		form *Form
+		want *flaresolverrCommand
+	}{
+		{
+			name: "POST form",
+			form: &Form{
+				Action: "https://example.com/login",
+				Method: http.MethodPost,
+				Values: url.Values{"csrf_token": {"abc123"}, "username": {"foo"}},
+			},
+			want: &flaresolverrCommand{
+				Cmd:        CommandRequestpost,
+				URL:        "https://example.com/login",
+				Session:    session.String(),
+				MaxTimeout: 1000,
+				PostData:   "csrf_token=abc123&username=foo",
+			},
+		},
+		{
+			name: "GET form",
+			form: &Form{
+				Action: "https://example.com/search?page=1",
+				Method: http.MethodGet,
+				Values: url.Values{"q": {"foo bar"}},
+			},
+			want: &flaresolverrCommand{
+				Cmd:        CommandRequestget,
+				URL:        "https://example.com/search?q=foo+bar",
+				Session:    session.String(),
+				MaxTimeout: 1000,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.SubmitForm(context.Background(), tt.form, WithSession(session)); err != nil {
+				t.Errorf("SubmitForm() error = %v", err)
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(flaresolverrCommand{})); diff != "" {
+				t.Errorf("SubmitForm() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_client_SubmitForm_domainConfigAndRedirects(t *testing.T) {
+	var commands []*flaresolverrCommand
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		commands = append(commands, cmd)
+		body := "welcome"
+		if cmd.Cmd == CommandRequestpost {
+			body = `<meta http-equiv="refresh" content="0; url=/home">`
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Response: body}}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithDomainConfig("example.com", DomainConfig{Proxy: "http://proxy:8080"}))
+
+	form := &Form{Action: "https://example.com/login", Method: http.MethodPost, Values: url.Values{"username": {"foo"}}}
+	resp, err := c.SubmitForm(context.Background(), form, WithFollowRedirects(1))
+	if err != nil {
+		t.Fatalf("SubmitForm() error = %v", err)
+	}
+
+	if resp.Solution.URL != "https://example.com/home" || len(commands) != 2 {
+		t.Fatalf("SubmitForm() = %s after %d commands, want the redirect followed", resp.Solution.URL, len(commands))
+	}
+	for _, cmd := range commands {
+		if cmd.Proxy != "http://proxy:8080" {
+			t.Errorf("%s sent through proxy %q, want the proxy of the domain", cmd.Cmd, cmd.Proxy)
+		}
+	}
+}
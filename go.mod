@@ -1,8 +1,20 @@
 module github.com/SkYNewZ/go-flaresolverr
 
-go 1.20
+go 1.21
 
 require (
+	github.com/chromedp/cdproto v0.0.0-20220208224320-6efb837e6bc2
+	github.com/go-resty/resty/v2 v2.16.5
 	github.com/google/go-cmp v0.5.9
 	github.com/google/uuid v1.3.0
+	golang.org/x/net v0.33.0
+	golang.org/x/sync v0.10.0
+	golang.org/x/time v0.9.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/chromedp/sysutil v1.0.0 // indirect
+	github.com/josharian/intern v1.0.0 // indirect
+	github.com/mailru/easyjson v0.7.7 // indirect
 )
@@ -0,0 +1,62 @@
+package flaresolverr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Health checks the FlareSolverr server is up, using its health endpoint.
+func (c *client) Health(ctx context.Context) error {
+	u, err := healthURL(c.baseURL)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("cannot make request: %w", err)
+	}
+
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+
+	resp, err := c.api().Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request to flaresolverr: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var health struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return fmt.Errorf("cannot read flaresolverr response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || health.Status != "ok" {
+		return fmt.Errorf("%w: unhealthy server: %s %q", ErrUnexpectedError, resp.Status, health.Status)
+	}
+
+	return nil
+}
+
+// healthURL returns the health endpoint, next to the /v1 endpoint.
+func healthURL(baseURL string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/v1") + "/health"
+	u.RawQuery = ""
+
+	return u.String(), nil
+}
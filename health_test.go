@@ -0,0 +1,56 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_healthURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+	}{
+		{baseURL: "http://127.0.0.1:8191/v1", want: "http://127.0.0.1:8191/health"},
+		{baseURL: "http://127.0.0.1:8191/v1/", want: "http://127.0.0.1:8191/health"},
+		{baseURL: "https://example.com/flaresolverr/v1?token=foo", want: "https://example.com/flaresolverr/health"},
+	}
+	for _, tt := range tests {
+		got, err := healthURL(tt.baseURL)
+		if err != nil || got != tt.want {
+			t.Errorf("healthURL(%q) = %q, %v, want %q", tt.baseURL, got, err, tt.want)
+		}
+	}
+}
+
+func Test_client_Health(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{name: "Healthy", status: http.StatusOK, body: `{"status":"ok"}`},
+		{name: "Unhealthy", status: http.StatusInternalServerError, body: `{"status":"error"}`, wantErr: ErrUnexpectedError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/health" {
+					t.Errorf("Health() requested %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := New(srv.URL+"/v1", time.Second, srv.Client())
+			if err := c.Health(context.Background()); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Health() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package flaresolverr
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Hooks are called during the commands lifecycle, to plug metrics, audit logging or alerting.
+// Every hook is optional, and must be safe for concurrent use.
+type Hooks struct {
+	// OnRequest is called before every command attempt is sent.
+	OnRequest func(ctx context.Context, event HookEvent)
+
+	// OnResponse is called after every successful command.
+	OnResponse func(ctx context.Context, event HookEvent, resp *Response)
+
+	// OnError is called after every failed command attempt.
+	OnError func(ctx context.Context, event HookEvent, err error)
+
+	// OnSessionCreated and OnSessionDestroyed are called after a session is created or destroyed.
+	OnSessionCreated   func(ctx context.Context, session uuid.UUID)
+	OnSessionDestroyed func(ctx context.Context, session uuid.UUID)
+
+	// OnChallengeSolved is called after a request for which FlareSolverr solved a challenge.
+	OnChallengeSolved func(ctx context.Context, event HookEvent, resp *Response)
+
+	// OnProxyScored is called after every command attempt through a proxy of WithProxyRotation,
+	// with its updated score.
+	OnProxyScored func(ctx context.Context, score ProxyScore)
+}
+
+// HookEvent describes the command a hook is called for.
+type HookEvent struct {
+	Command   string
+	URL       string
+	Session   string
+	RequestID string
+	Attempt   int
+
+	// Duration is how long the attempt took, zero in OnRequest.
+	Duration time.Duration
+}
+
+func newHookEvent(cmd *flaresolverrCommand, o *requestOptions, attempt int) HookEvent {
+	return HookEvent{Command: cmd.Cmd.String(), URL: cmd.URL, Session: cmd.Session, RequestID: o.requestID, Attempt: attempt}
+}
+
+func (h *Hooks) request(ctx context.Context, event HookEvent) {
+	if h != nil && h.OnRequest != nil {
+		h.OnRequest(ctx, event)
+	}
+}
+
+func (h *Hooks) failed(ctx context.Context, event HookEvent, err error) {
+	if h != nil && h.OnError != nil {
+		h.OnError(ctx, event, err)
+	}
+}
+
+// succeeded calls the hooks of a successful command.
+func (h *Hooks) succeeded(ctx context.Context, event HookEvent, cmd *flaresolverrCommand, resp *Response) {
+	if h == nil {
+		return
+	}
+
+	if h.OnResponse != nil {
+		h.OnResponse(ctx, event, resp)
+	}
+
+	// sessions created without ID are reported with the ID assigned by FlareSolverr
+	switch id, _ := uuid.Parse(withAssignedSession(cmd, resp).Session); cmd.Cmd {
+	case CommandSessionscreate:
+		if h.OnSessionCreated != nil {
+			h.OnSessionCreated(ctx, id)
+		}
+	case CommandSessionsdestroy:
+		if h.OnSessionDestroyed != nil {
+			h.OnSessionDestroyed(ctx, id)
+		}
+	case CommandRequestget, CommandRequestpost:
+		if h.OnChallengeSolved != nil && resp.ChallengeSolved() {
+			h.OnChallengeSolved(ctx, event, resp)
+		}
+	}
+}
+
+func (h *Hooks) proxyScored(ctx context.Context, score ProxyScore) {
+	if h != nil && h.OnProxyScored != nil {
+		h.OnProxyScored(ctx, score)
+	}
+}
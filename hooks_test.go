@@ -0,0 +1,74 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_client_WithHooks(t *testing.T) {
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		if strings.HasSuffix(cmd.URL, "/timeout") {
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: maximum timeout reached"}
+		}
+		return http.StatusOK, &Response{Status: "ok", Message: "Challenge solved!", Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+
+	var mu sync.Mutex
+	var calls []string
+	record := func(call string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, call)
+	}
+
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithHooks(Hooks{
+		OnRequest:          func(_ context.Context, e HookEvent) { record("request " + e.Command) },
+		OnResponse:         func(_ context.Context, e HookEvent, _ *Response) { record("response " + e.Command) },
+		OnError:            func(_ context.Context, e HookEvent, _ error) { record("error " + e.Command) },
+		OnSessionCreated:   func(context.Context, uuid.UUID) { record("session created") },
+		OnSessionDestroyed: func(context.Context, uuid.UUID) { record("session destroyed") },
+		OnChallengeSolved:  func(_ context.Context, e HookEvent, _ *Response) { record("challenge solved " + e.URL) },
+	}))
+
+	ctx := context.Background()
+	session := uuid.New()
+	_, _ = c.CreateSession(ctx, session)
+	_, _ = c.Get(ctx, "https://example.com", session)
+	_, _ = c.Get(ctx, "https://example.com/timeout", session)
+	_ = c.DestroySession(ctx, session)
+
+	want := []string{
+		"request sessions.create", "response sessions.create", "session created",
+		"request request.get", "response request.get", "challenge solved https://example.com",
+		"request request.get", "error request.get",
+		"request sessions.destroy", "response sessions.destroy", "session destroyed",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("hooks calls = %q, want %q", calls, want)
+	}
+}
+
+func Test_client_WithHooks_assignedSession(t *testing.T) {
+	assigned := uuid.New()
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		return http.StatusOK, &Response{Status: "ok", Message: "Session created successfully.", Session: assigned.String()}
+	})
+
+	var got uuid.UUID
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithHooks(Hooks{
+		OnSessionCreated: func(_ context.Context, session uuid.UUID) { got = session },
+	}))
+
+	if _, err := c.CreateSessionAuto(context.Background()); err != nil {
+		t.Fatalf("CreateSessionAuto() error = %v", err)
+	}
+	if got != assigned {
+		t.Errorf("OnSessionCreated() session = %v, want the assigned %v", got, assigned)
+	}
+}
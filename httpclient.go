@@ -0,0 +1,45 @@
+package flaresolverr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// HTTPClientFor solves the challenge protecting u once, and returns an http.Client
+// ready to reach its origin directly: its cookie jar holds the solved cookies
+// and its transport sends the solved user agent.
+//
+// The returned client is based on the client http.Client, which must reach the target
+// with the same IP address as FlareSolverr for the cookies to be accepted.
+func (c *client) HTTPClientFor(ctx context.Context, u string, opts ...RequestOption) (*http.Client, error) {
+	target, err := url.Parse(u)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	clearance, err := c.solveClearance(ctx, u, newRequestOptions(ctx, opts))
+	if err != nil {
+		return nil, err
+	}
+
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create cookie jar: %w", err)
+	}
+	jar.SetCookies(target, clearance.Cookies)
+
+	// cookies are sent by the jar, the transport only pins the user agent
+	transport := (&Clearance{UserAgent: clearance.UserAgent}).Transport(c.httpClient.Transport)
+
+	return &http.Client{
+		Transport:     transport,
+		Jar:           jar,
+		CheckRedirect: c.httpClient.CheckRedirect,
+		Timeout:       c.httpClient.Timeout,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_client_HTTPClientFor(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("cf_clearance"); err != nil || cookie.Value != "foo" || r.UserAgent() != "bar" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer target.Close()
+
+	solves := 0
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		solves++
+		return http.StatusOK, &Response{
+			Status: "ok",
+			Solution: &ResponseSolution{
+				URL:       cmd.URL,
+				Cookies:   []Cookie{{Name: "cf_clearance", Value: "foo", Path: "/", Expires: -1, Session: true}},
+				UserAgent: "bar",
+			},
+		}
+	})
+
+	httpClient, err := c.HTTPClientFor(context.Background(), target.URL)
+	if err != nil {
+		t.Fatalf("HTTPClientFor() error = %v", err)
+	}
+
+	for _, path := range []string{"/", "/api/items", "/api/items/1"} {
+		resp, err := httpClient.Get(target.URL + path)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("Get(%s) status = %v, want %v", path, resp.StatusCode, http.StatusNoContent)
+		}
+	}
+
+	if solves != 1 {
+		t.Errorf("HTTPClientFor() solved %d times, want 1", solves)
+	}
+}
@@ -0,0 +1,63 @@
+package flaresolverr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTargetHTTPStatus when the target website answered with a 4xx or 5xx status,
+// with the FailOnHTTPError option. The error is a *HTTPStatusError.
+var ErrTargetHTTPStatus = errors.New("target returned an HTTP error")
+
+// IsSuccess reports whether the target answered with a 2xx status.
+func (s *ResponseSolution) IsSuccess() bool {
+	return s.Status >= 200 && s.Status < 300
+}
+
+// IsRedirect reports whether the target answered with a 3xx status.
+func (s *ResponseSolution) IsRedirect() bool {
+	return s.Status >= 300 && s.Status < 400
+}
+
+// IsClientError reports whether the target answered with a 4xx status.
+func (s *ResponseSolution) IsClientError() bool {
+	return s.Status >= 400 && s.Status < 500
+}
+
+// IsServerError reports whether the target answered with a 5xx status.
+func (s *ResponseSolution) IsServerError() bool {
+	return s.Status >= 500 && s.Status < 600
+}
+
+// HTTPStatusError is returned when the target website answered with a 4xx or 5xx status,
+// with the FailOnHTTPError option.
+type HTTPStatusError struct {
+	// StatusCode is the status of the target website.
+	StatusCode int
+
+	// Body is the page returned by the target website.
+	Body string
+
+	// Response is the whole FlareSolverr response, with the cookies and headers.
+	Response *Response
+}
+
+// Error returns a message such as "target returned an HTTP error: HTTP 404".
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("%s: HTTP %d", ErrTargetHTTPStatus, e.StatusCode)
+}
+
+// Unwrap returns ErrTargetHTTPStatus.
+func (e *HTTPStatusError) Unwrap() error {
+	return ErrTargetHTTPStatus
+}
+
+// httpStatusError returns a *HTTPStatusError if the target answered with a 4xx or 5xx status.
+func httpStatusError(response *Response) error {
+	s := response.Solution
+	if s == nil || !(s.IsClientError() || s.IsServerError()) {
+		return nil
+	}
+
+	return &HTTPStatusError{StatusCode: s.Status, Body: s.Response, Response: response}
+}
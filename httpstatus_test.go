@@ -0,0 +1,83 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestResponseSolution_status(t *testing.T) {
+	tests := []struct {
+		status                                      int
+		success, redirect, clientError, serverError bool
+	}{
+		{status: http.StatusOK, success: true},
+		{status: http.StatusNoContent, success: true},
+		{status: http.StatusFound, redirect: true},
+		{status: http.StatusNotFound, clientError: true},
+		{status: http.StatusServiceUnavailable, serverError: true},
+		{status: 0},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			s := &ResponseSolution{Status: tt.status}
+			if got := s.IsSuccess(); got != tt.success {
+				t.Errorf("IsSuccess() = %v, want %v", got, tt.success)
+			}
+			if got := s.IsRedirect(); got != tt.redirect {
+				t.Errorf("IsRedirect() = %v, want %v", got, tt.redirect)
+			}
+			if got := s.IsClientError(); got != tt.clientError {
+				t.Errorf("IsClientError() = %v, want %v", got, tt.clientError)
+			}
+			if got := s.IsServerError(); got != tt.serverError {
+				t.Errorf("IsServerError() = %v, want %v", got, tt.serverError)
+			}
+		})
+	}
+}
+
+func Test_client_FailOnHTTPError(t *testing.T) {
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		status := http.StatusOK
+		if cmd.URL == "https://example.com/missing" {
+			status = http.StatusNotFound
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Status: status, Response: "<html>page</html>"}}
+	})
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		url     string
+		wantErr bool
+	}{
+		{name: "disabled", url: "https://example.com/missing", wantErr: false},
+		{name: "success", opts: []Option{FailOnHTTPError()}, url: "https://example.com/", wantErr: false},
+		{name: "not found", opts: []Option{FailOnHTTPError()}, url: "https://example.com/missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(srv.baseURL, time.Second, srv.httpClient, tt.opts...)
+			_, err := c.Get(context.Background(), tt.url, uuid.Nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				return
+			}
+
+			var se *HTTPStatusError
+			if !errors.Is(err, ErrTargetHTTPStatus) || !errors.As(err, &se) {
+				t.Fatalf("Get() error = %v, want a *HTTPStatusError", err)
+			}
+			if se.StatusCode != http.StatusNotFound || se.Body != "<html>page</html>" {
+				t.Errorf("HTTPStatusError = %+v, want HTTP 404 with the body", se)
+			}
+		})
+	}
+}
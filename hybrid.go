@@ -0,0 +1,111 @@
+package flaresolverr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+)
+
+// HybridClient is an http.Client making requests directly, which only goes through FlareSolverr
+// when a challenge is detected with IsChallenge. The solved cookies and user agent are cached
+// per hostname and sent with the next requests, until a new challenge is detected.
+//
+// Direct requests must reach the target with the same IP address as FlareSolverr
+// for the cookies to be accepted.
+type HybridClient struct {
+	*http.Client
+}
+
+// NewHybridClient creates a HybridClient solving challenges with c.
+// Direct requests are made with a copy of httpClient, or the default http client if nil.
+// opts are applied to the FlareSolverr requests.
+func NewHybridClient(c Client, httpClient *http.Client, opts ...RequestOption) *HybridClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	hybrid := *httpClient
+	hybrid.Transport = NewTransport(c, httpClient.Transport, opts...)
+
+	return &HybridClient{Client: &hybrid}
+}
+
+// NewTransport returns the http.RoundTripper of a HybridClient, to plug FlareSolverr
+// into other http clients. Direct requests are made with base, or http.DefaultTransport if nil.
+// opts are applied to the FlareSolverr requests.
+func NewTransport(c Client, base http.RoundTripper, opts ...RequestOption) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &hybridTransport{
+		client:     c,
+		base:       base,
+		opts:       opts,
+		clearances: newClearanceCache(),
+	}
+}
+
+type hybridTransport struct {
+	client     Client
+	base       http.RoundTripper
+	opts       []RequestOption
+	clearances *clearanceCache
+	flight     singleflight.Group
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *hybridTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	direct := req.Clone(req.Context())
+	if clearance := t.clearances.get(req.URL.String()); clearance != nil {
+		clearance.Apply(direct)
+	}
+
+	resp, err := t.base.RoundTrip(direct)
+	if err != nil || !IsChallenge(resp) {
+		return resp, err
+	}
+
+	// the body has been consumed by the direct request
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	if !replayable {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	clearance, err := t.solve(req.Context(), req.URL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, fmt.Errorf("cannot replay request body: %w", err)
+		}
+	}
+	clearance.Apply(retry)
+
+	return t.base.RoundTrip(retry)
+}
+
+// solve solves the challenge protecting u, once for all the concurrent requests to its domain.
+func (t *hybridTransport) solve(ctx context.Context, u string) (*Clearance, error) {
+	return share(ctx, &t.flight, clearanceKey(u, newRequestOptions(ctx, t.opts)), func(ctx context.Context) (*Clearance, error) {
+		resp, err := t.client.GetWithOptions(ctx, u, uuid.Nil, t.opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		clearance := &Clearance{URL: u}
+		if resp.Solution != nil {
+			clearance = resp.Solution.Clearance()
+		}
+		t.clearances.put(u, clearance)
+
+		return clearance, nil
+	})
+}
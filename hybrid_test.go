@@ -0,0 +1,84 @@
+package flaresolverr
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHybridClient(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/public" {
+			_, _ = w.Write([]byte("public"))
+			return
+		}
+
+		if cookie, err := r.Cookie("cf_clearance"); err != nil || cookie.Value != "solved" || r.UserAgent() != "Mozilla/5.0" {
+			w.Header().Set("cf-mitigated", "challenge")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			body := new(bytes.Buffer)
+			_, _ = body.ReadFrom(r.Body)
+			_, _ = w.Write([]byte("posted " + body.String()))
+			return
+		}
+		_, _ = w.Write([]byte("protected"))
+	}))
+	defer target.Close()
+
+	var commands atomic.Int32
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		commands.Add(1)
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{
+			URL:       cmd.URL,
+			UserAgent: "Mozilla/5.0",
+			Cookies:   []Cookie{{Name: "cf_clearance", Value: "solved", Expires: float64(time.Now().Add(time.Hour).Unix())}},
+		}}
+	})
+	hybrid := NewHybridClient(c, target.Client())
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		want         string
+		wantCommands int32
+	}{
+		{name: "Not protected", method: http.MethodGet, path: "/public", want: "public", wantCommands: 0},
+		{name: "Challenge solved", method: http.MethodGet, path: "/protected", want: "protected", wantCommands: 1},
+		{name: "Cached clearance", method: http.MethodGet, path: "/protected", want: "protected", wantCommands: 1},
+		{name: "Post", method: http.MethodPost, path: "/protected", body: "foo=bar", want: "posted foo=bar", wantCommands: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, target.URL+tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := hybrid.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			body := new(bytes.Buffer)
+			_, _ = body.ReadFrom(resp.Body)
+			if body.String() != tt.want {
+				t.Errorf("Do() = %q, want %q", body.String(), tt.want)
+			}
+
+			if got := commands.Load(); got != tt.wantCommands {
+				t.Errorf("sent %d commands, want %d", got, tt.wantCommands)
+			}
+		})
+	}
+}
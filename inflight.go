@@ -0,0 +1,102 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// ErrTooManyInFlight when a command cannot wait for a slot, see WithMaxInFlight.
+var ErrTooManyInFlight = errors.New("too many commands in flight")
+
+// Priority orders the commands waiting for a slot of WithMaxInFlight, see WithPriority.
+type Priority int
+
+const (
+	// PriorityLow is for background work, such as clearance refreshes.
+	PriorityLow Priority = -1
+	// PriorityNormal is the default priority.
+	PriorityNormal Priority = 0
+	// PriorityHigh is for latency-sensitive requests, such as interactive ones.
+	PriorityHigh Priority = 1
+)
+
+// inFlightGuard bounds the number of commands sent to FlareSolverr at the same time.
+// Commands waiting for a slot are served by priority, then in order.
+type inFlightGuard struct {
+	limit    int
+	maxQueue int // negative for no limit
+
+	mu      sync.Mutex
+	active  int
+	waiters []*inFlightWaiter // sorted by priority, then arrival
+}
+
+type inFlightWaiter struct {
+	priority Priority
+	ready    chan struct{} // closed once the slot is handed over
+}
+
+func newInFlightGuard(limit, maxQueue int) *inFlightGuard {
+	return &inFlightGuard{limit: max(limit, 1), maxQueue: maxQueue}
+}
+
+// acquire waits for a slot, or fails with ErrTooManyInFlight when the queue is full.
+func (g *inFlightGuard) acquire(ctx context.Context, priority Priority) error {
+	g.mu.Lock()
+	if g.active < g.limit && len(g.waiters) == 0 {
+		g.active++
+		g.mu.Unlock()
+		return nil
+	}
+
+	if g.maxQueue >= 0 && len(g.waiters) >= g.maxQueue {
+		g.mu.Unlock()
+		return ErrTooManyInFlight
+	}
+
+	// after the waiters of the same or a higher priority
+	w := &inFlightWaiter{priority: priority, ready: make(chan struct{})}
+	i := len(g.waiters)
+	for i > 0 && g.waiters[i-1].priority < priority {
+		i--
+	}
+	g.waiters = slices.Insert(g.waiters, i, w)
+	g.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	g.mu.Lock()
+	for i, waiter := range g.waiters {
+		if waiter == w {
+			g.waiters = append(g.waiters[:i], g.waiters[i+1:]...)
+			g.mu.Unlock()
+			return ctx.Err()
+		}
+	}
+	g.mu.Unlock()
+
+	// the slot was handed over meanwhile, give it back
+	g.release()
+	return ctx.Err()
+}
+
+// release frees a slot, handing it over to the next waiting command.
+func (g *inFlightGuard) release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.waiters) == 0 {
+		g.active--
+		return
+	}
+
+	w := g.waiters[0]
+	g.waiters = g.waiters[1:]
+	close(w.ready)
+}
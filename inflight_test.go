@@ -0,0 +1,183 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_client_WithMaxInFlight(t *testing.T) {
+	started, release := make(chan struct{}, 3), make(chan struct{})
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		started <- struct{}{}
+		<-release
+		return http.StatusOK, &Response{Status: "ok"}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithMaxInFlight(2, 1)).(*client)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	get := func() {
+		defer wg.Done()
+		_, err := c.Get(ctx, "https://example.com", uuid.Nil)
+		errs <- err
+	}
+
+	wg.Add(2)
+	go get()
+	go get()
+	<-started
+	<-started
+
+	// the third command waits for a slot
+	wg.Add(1)
+	go get()
+	waitFor(t, func() bool {
+		c.inFlight.mu.Lock()
+		defer c.inFlight.mu.Unlock()
+		return len(c.inFlight.waiters) == 1
+	})
+
+	if _, err := c.Get(ctx, "https://example.com", uuid.Nil); !errors.Is(err, ErrTooManyInFlight) {
+		t.Errorf("Get() error = %v, want %v", err, ErrTooManyInFlight)
+	}
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Get() error = %v", err)
+		}
+	}
+
+	if len(started) != 1 {
+		t.Errorf("queued command sent %d times, want once", len(started))
+	}
+}
+
+func Test_client_WithMaxInFlight_backoff(t *testing.T) {
+	timedOut := make(chan struct{}, 1)
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		if cmd.URL == "https://example.com/timeout" {
+			timedOut <- struct{}{}
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: maximum timeout reached"}
+		}
+		return http.StatusOK, &Response{Status: "ok"}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient,
+		WithMaxInFlight(1, -1),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Hour}),
+	)
+
+	backoffCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = c.Get(backoffCtx, "https://example.com/timeout", uuid.Nil)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	<-timedOut
+
+	// the slot is free while the first command backs off
+	ctx, cancelGet := context.WithTimeout(context.Background(), time.Second)
+	defer cancelGet()
+	if _, err := c.Get(ctx, "https://example.com", uuid.Nil); err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+}
+
+func Test_inFlightGuard_acquire(t *testing.T) {
+	g := newInFlightGuard(1, -1)
+	if err := g.acquire(context.Background(), PriorityNormal); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := g.acquire(ctx, PriorityNormal); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("acquire() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	g.release()
+	if err := g.acquire(context.Background(), PriorityNormal); err != nil {
+		t.Errorf("acquire() error = %v once released", err)
+	}
+
+	if g.active != 1 || len(g.waiters) != 0 {
+		t.Errorf("guard has %d active and %d waiting, want 1 and 0", g.active, len(g.waiters))
+	}
+}
+
+// waitFor polls cond until it is true, failing the test after a second.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_inFlightGuard_priority(t *testing.T) {
+	g := newInFlightGuard(1, -1)
+	if err := g.acquire(context.Background(), PriorityNormal); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	var mu sync.Mutex
+	var got []string
+	var wg sync.WaitGroup
+	waiters := []struct {
+		name     string
+		priority Priority
+	}{
+		{"low", PriorityLow},
+		{"normal 1", PriorityNormal},
+		{"high", PriorityHigh},
+		{"normal 2", PriorityNormal},
+	}
+	for i, w := range waiters {
+		w := w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := g.acquire(context.Background(), w.priority); err != nil {
+				t.Errorf("acquire() error = %v", err)
+				return
+			}
+
+			mu.Lock()
+			got = append(got, w.name)
+			mu.Unlock()
+			g.release()
+		}()
+
+		// enqueue the waiters in order
+		waitFor(t, func() bool {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			return len(g.waiters) == i+1
+		})
+	}
+
+	g.release()
+	wg.Wait()
+
+	want := []string{"high", "normal 1", "normal 2", "low"}
+	if !slices.Equal(got, want) {
+		t.Errorf("slots granted to %v, want %v", got, want)
+	}
+}
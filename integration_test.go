@@ -0,0 +1,165 @@
+//go:build integration
+
+package flaresolverr
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// supportMatrix lists which features work against which FlareSolverr server.
+type supportMatrix struct {
+	GeneratedAt time.Time            `json:"generatedAt"`
+	Servers     []supportMatrixEntry `json:"servers"`
+}
+
+type supportMatrixEntry struct {
+	Name     string          `json:"name"`
+	Endpoint string          `json:"endpoint"`
+	Version  string          `json:"version"`
+	Features map[string]bool `json:"features"`
+}
+
+// integrationEndpoints reads FLARESOLVERR_ENDPOINTS, a comma separated list of name=endpoint,
+// e.g. "latest=http://127.0.0.1:8191/v1,v3.0.0=http://127.0.0.1:8192/v1".
+func integrationEndpoints(t *testing.T) map[string]string {
+	t.Helper()
+	env := os.Getenv("FLARESOLVERR_ENDPOINTS")
+	if env == "" {
+		env = "local=" + testEndpoint()
+	}
+
+	endpoints := make(map[string]string)
+	for _, entry := range strings.Split(env, ",") {
+		name, endpoint, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			t.Fatalf("invalid FLARESOLVERR_ENDPOINTS entry %q", entry)
+		}
+		endpoints[name] = endpoint
+	}
+
+	return endpoints
+}
+
+// integrationFeatures exercises every feature, each returning whether it is supported.
+var integrationFeatures = map[string]func(t *testing.T, c Client) bool{
+	"sessions.create": func(t *testing.T, c Client) bool {
+		session := uuid.New()
+		defer c.DestroySession(context.Background(), session)
+
+		resp, err := c.CreateSession(context.Background(), session)
+		return err == nil && resp.Session == session.String()
+	},
+	"sessions.list": func(t *testing.T, c Client) bool {
+		session := uuid.New()
+		if _, err := c.CreateSession(context.Background(), session); err != nil {
+			return false
+		}
+		defer c.DestroySession(context.Background(), session)
+
+		resp, err := c.ListSessions(context.Background())
+		if err != nil {
+			return false
+		}
+
+		for _, s := range resp.Sessions {
+			if s == session {
+				return true
+			}
+		}
+		return false
+	},
+	"sessions.destroy": func(t *testing.T, c Client) bool {
+		session := uuid.New()
+		if _, err := c.CreateSession(context.Background(), session); err != nil {
+			return false
+		}
+
+		return c.DestroySession(context.Background(), session) == nil
+	},
+	"request.get": func(t *testing.T, c Client) bool {
+		resp, err := c.Get(context.Background(), "https://httpbin.org/status/200", uuid.Nil)
+		return err == nil && resp.Solution != nil && resp.Solution.Status == http.StatusOK
+	},
+	"request.get.session": func(t *testing.T, c Client) bool {
+		session := uuid.New()
+		if _, err := c.CreateSession(context.Background(), session); err != nil {
+			return false
+		}
+		defer c.DestroySession(context.Background(), session)
+
+		resp, err := c.Get(context.Background(), "https://httpbin.org/cookies/set?foo=bar", session)
+		return err == nil && resp.Solution != nil && len(resp.Solution.Cookies) > 0
+	},
+	"request.post": func(t *testing.T, c Client) bool {
+		resp, err := c.Post(context.Background(), "https://httpbin.org/post", uuid.Nil, "foo=bar")
+		return err == nil && resp.Solution != nil && strings.Contains(resp.Solution.Response, "foo")
+	},
+	"returnOnlyCookies": func(t *testing.T, c Client) bool {
+		_, err := c.Download(context.Background(), "https://httpbin.org/bytes/16", io.Discard)
+		return err == nil
+	},
+	"userAgent": func(t *testing.T, c Client) bool {
+		resp, err := c.Get(context.Background(), "https://httpbin.org/user-agent", uuid.Nil)
+		return err == nil && resp.Solution != nil && resp.Solution.UserAgent != ""
+	},
+}
+
+func TestIntegration_SupportMatrix(t *testing.T) {
+	matrix := &supportMatrix{GeneratedAt: time.Now().UTC()}
+
+	endpoints := integrationEndpoints(t)
+	names := make([]string, 0, len(endpoints))
+	for name := range endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		entry := supportMatrixEntry{Name: name, Endpoint: endpoints[name], Features: make(map[string]bool)}
+		c := New(endpoints[name], 60*time.Second, nil)
+
+		t.Run(name, func(t *testing.T) {
+			resp, err := c.ListSessions(context.Background())
+			if err != nil {
+				t.Fatalf("FlareSolverr %s is not reachable: %v", name, err)
+			}
+			entry.Version = resp.Version
+
+			for feature, run := range integrationFeatures {
+				feature, run := feature, run
+				t.Run(feature, func(t *testing.T) {
+					entry.Features[feature] = run(t, c)
+					if !entry.Features[feature] {
+						t.Errorf("%s is not supported by %s (%s)", feature, name, entry.Version)
+					}
+				})
+			}
+		})
+
+		matrix.Servers = append(matrix.Servers, entry)
+	}
+
+	path := os.Getenv("FLARESOLVERR_MATRIX")
+	if path == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(matrix, "", "  ")
+	if err != nil {
+		t.Fatalf("cannot encode support matrix: %v", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("cannot write support matrix: %v", err)
+	}
+}
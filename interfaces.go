@@ -0,0 +1,212 @@
+package flaresolverr
+
+//go:generate go run ./internal/cmd/genmock -src=interfaces.go -iface=Client -import=github.com/SkYNewZ/go-flaresolverr -pkg=flaresolverrmock -out=flaresolverrmock
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Client is a FlareSolverr client. It is the union of Requester, SessionAPI and HealthAPI,
+// depend on the smaller interfaces when only some of the methods are used.
+//
+// Every method is safe for concurrent use. Failed commands return an *Error
+// wrapping one of the package sentinel errors, such as ErrRequestTimeout.
+type Client interface {
+	Requester
+	SessionAPI
+	HealthAPI
+
+	// Close stops the background work of the client and destroys the sessions it created
+	// for WithSessionPerDomain. The client must not be used after Close.
+	//
+	//	c := flaresolverr.New(baseURL, 0, nil, flaresolverr.WithSessionPerDomain(10*time.Minute))
+	//	defer c.Close(context.Background())
+	Close(ctx context.Context) error
+}
+
+// Requester makes requests through FlareSolverr.
+type Requester interface {
+	// Get requests u through FlareSolverr, solving the challenge protecting it.
+	// session is the session to use, or uuid.Nil for the session set by WithSession or
+	// ContextWithSession, if any, or a temporary browser.
+	// The first proxy, if any, is used for the request.
+	// The page content, cookies and user agent are returned in Response.Solution.
+	//
+	//	resp, err := c.Get(ctx, "https://example.com", uuid.Nil, "http://proxy:8080")
+	Get(ctx context.Context, u string, session uuid.UUID, proxy ...string) (*Response, error)
+
+	// GetWithOptions is like Get, with request options.
+	//
+	//	resp, err := c.GetWithOptions(ctx, "https://example.com", uuid.Nil, flaresolverr.WithProxy("http://proxy:8080"))
+	GetWithOptions(ctx context.Context, u string, session uuid.UUID, opts ...RequestOption) (*Response, error)
+
+	// Post is like Get with a POST request.
+	// data must be an application/x-www-form-urlencoded string.
+	//
+	//	resp, err := c.Post(ctx, "https://example.com/search", uuid.Nil, "q=flaresolverr")
+	Post(ctx context.Context, u string, session uuid.UUID, data string, proxy ...string) (*Response, error)
+
+	// PostWithOptions is like Post, with request options.
+	//
+	//	resp, err := c.PostWithOptions(ctx, "https://example.com/search", uuid.Nil, "q=flaresolverr", flaresolverr.WithRequestID(id))
+	PostWithOptions(ctx context.Context, u string, session uuid.UUID, data string, opts ...RequestOption) (*Response, error)
+
+	// Download solves the challenge protecting u, then downloads u directly with the solved
+	// cookies and user agent, streaming its content into w. It returns the number of bytes written.
+	// FlareSolverr cannot return binary content, use this for files and images.
+	// The direct request must reach the target with the same IP address as FlareSolverr.
+	//
+	//	n, err := c.Download(ctx, "https://example.com/file.zip", f)
+	Download(ctx context.Context, u string, w io.Writer, opts ...RequestOption) (int64, error)
+
+	// DownloadDirect downloads u without FlareSolverr, with the cookies and user agent
+	// of a session used through the client, streaming its content into w.
+	// The session must have solved the challenge protecting u beforehand.
+	//
+	//	n, err := c.DownloadDirect(ctx, session, "https://example.com/file.zip", f, flaresolverr.WithResume(offset))
+	DownloadDirect(ctx context.Context, session uuid.UUID, u string, w io.Writer, opts ...DownloadOption) (int64, error)
+
+	// SubmitForm submits a form found in a solution through FlareSolverr.
+	// Use WithSession to submit it within the session the form was retrieved from.
+	//
+	//	form, err := resp.Solution.Form("login")
+	//	form.Set("username", "foo")
+	//	resp, err = c.SubmitForm(ctx, form, flaresolverr.WithSession(session))
+	SubmitForm(ctx context.Context, form *Form, opts ...RequestOption) (*Response, error)
+
+	// HTTPClientFor solves the challenge protecting u once, and returns an http.Client
+	// reaching its origin directly with the solved cookies and user agent.
+	// The direct requests must reach the target with the same IP address as FlareSolverr.
+	//
+	//	hc, err := c.HTTPClientFor(ctx, "https://example.com")
+	//	resp, err := hc.Get("https://example.com/api/items")
+	HTTPClientFor(ctx context.Context, u string, opts ...RequestOption) (*http.Client, error)
+
+	// GetBatch gets every URL of urls, running a bounded number of requests at the same time.
+	// Responses and errors are returned in the order of urls: for each URL,
+	// either its response or its error is set.
+	//
+	//	responses, errs := c.GetBatch(ctx, urls, flaresolverr.WithBatchConcurrency(8))
+	GetBatch(ctx context.Context, urls []string, opts ...BatchOption) ([]*Response, []error)
+
+	// Warmup solves the challenges protecting domains, retrieving only the cookies, so the first
+	// requests to them are fast. It populates the site sessions of WithSessionPerDomain and the
+	// clearances of WithRevalidation. Domains are solved a bounded number at a time, see WithBatchConcurrency.
+	//
+	//	if err := c.Warmup(ctx, []string{"example.com", "example.org"}); err != nil {
+	//		log.Printf("warm up: %v", err)
+	//	}
+	Warmup(ctx context.Context, domains []string, opts ...BatchOption) error
+}
+
+// SessionAPI manages FlareSolverr sessions.
+type SessionAPI interface {
+	// CreateSession launches a browser instance which keeps its cookies until it is destroyed
+	// with DestroySession, so challenges are not solved again for every request.
+	// session is the ID of the new session, a random one is used by FlareSolverr if uuid.Nil.
+	// The first proxy, if any, is used by the whole session.
+	//
+	//	session := uuid.New()
+	//	if _, err := c.CreateSession(ctx, session); err != nil {
+	//		return err
+	//	}
+	//	defer c.DestroySession(context.Background(), session)
+	CreateSession(ctx context.Context, session uuid.UUID, proxy ...string) (*Response, error)
+
+	// CreateSessionWithOptions is like CreateSession, with request options.
+	// WithProxy sets the proxy used by the whole session, WithCookies seeds its browser with cookies.
+	//
+	//	_, err := c.CreateSessionWithOptions(ctx, session, flaresolverr.WithProxy("http://proxy:8080"))
+	CreateSessionWithOptions(ctx context.Context, session uuid.UUID, opts ...RequestOption) (*Response, error)
+
+	// CreateSessionAuto is like CreateSession, letting FlareSolverr generate the session ID.
+	// It returns the generated ID, use it with WithSessionID. Errors wrap ErrInvalidServerResponse
+	// if the server does not return a valid ID.
+	//
+	//	id, err := c.CreateSessionAuto(ctx)
+	//	if err != nil {
+	//		return err
+	//	}
+	//	defer c.DestroySessionWithOptions(context.Background(), uuid.Nil, flaresolverr.WithSessionID(id))
+	CreateSessionAuto(ctx context.Context, opts ...RequestOption) (SessionID, error)
+
+	// ListSessions returns the active sessions in Response.Sessions.
+	//
+	//	resp, err := c.ListSessions(ctx)
+	ListSessions(ctx context.Context) (*Response, error)
+
+	// ListSessionsWithOptions is like ListSessions, with request options.
+	//
+	//	resp, err := c.ListSessionsWithOptions(ctx, flaresolverr.WithRequestID(id))
+	ListSessionsWithOptions(ctx context.Context, opts ...RequestOption) (*Response, error)
+
+	// ListSessionInfo returns the sessions active on the server, with what the client knows
+	// about them: when they were created, their proxy and whether they are in use.
+	//
+	//	infos, err := c.ListSessionInfo(ctx)
+	ListSessionInfo(ctx context.Context, opts ...RequestOption) ([]SessionInfo, error)
+
+	// DestroySession shuts down a browser instance and frees its resources.
+	// Always destroy the sessions you create, too many of them slow the server down.
+	//
+	//	err := c.DestroySession(ctx, session)
+	DestroySession(ctx context.Context, session uuid.UUID) error
+
+	// DestroySessionWithOptions is like DestroySession, with request options.
+	//
+	//	err := c.DestroySessionWithOptions(ctx, session, flaresolverr.WithRequestID(id))
+	DestroySessionWithOptions(ctx context.Context, session uuid.UUID, opts ...RequestOption) error
+
+	// Session returns the session with the given ID if it has been used through the client, or nil.
+	// Its cookie jar mirrors every cookie returned within the session.
+	//
+	//	jar := c.Session(session).Jar()
+	Session(id uuid.UUID) *Session
+
+	// FindSessionByLabel returns the session created through the client with the label, or nil.
+	// When several sessions match, the oldest one is returned.
+	//
+	//	if _, err := c.CreateSessionWithOptions(ctx, uuid.New(), flaresolverr.WithLabel("site", "example.com")); err != nil {
+	//		return err
+	//	}
+	//	session := c.FindSessionByLabel("site", "example.com")
+	FindSessionByLabel(key, value string) *Session
+
+	// ExportSessions returns the sessions tracked by the client, with their proxy, labels and site,
+	// so another process can take them over with ImportSessions, e.g. during a blue/green deployment.
+	// The export holds the proxy credentials.
+	//
+	//	state, err := c.ExportSessions()
+	ExportSessions() ([]byte, error)
+
+	// ImportSessions tracks the sessions returned by ExportSessions in another process,
+	// as if they were created through the client. Errors wrap ErrInvalidSessionExport.
+	//
+	//	if err := c.ImportSessions(state); err != nil {
+	//		return err
+	//	}
+	ImportSessions(data []byte) error
+}
+
+// HealthAPI checks the FlareSolverr server health and reports the client statistics.
+type HealthAPI interface {
+	// Health checks the FlareSolverr server is up, using its health endpoint.
+	//
+	//	if err := c.Health(ctx); err != nil {
+	//		return fmt.Errorf("flaresolverr is down: %w", err)
+	//	}
+	Health(ctx context.Context) error
+
+	// Stats returns a snapshot of the client statistics: the commands sent, their failures by class
+	// and their durations, the active sessions and the cache hit rate. It does not need any metrics backend.
+	//
+	//	stats := c.Stats()
+	//	log.Printf("p99 %s, cache hit rate %.2f", stats.Commands[flaresolverr.CommandRequestget].P99Duration, stats.CacheHitRate())
+	Stats() Stats
+}
+
+var _ Client = (*client)(nil)
@@ -0,0 +1,93 @@
+package flaresolverr
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// keepAlive pings the sessions created through the client so their browser stays warm.
+type keepAlive struct {
+	interval time.Duration
+	url      string
+	clock    Clock
+
+	mu    sync.Mutex
+	stops map[uuid.UUID]context.CancelFunc
+	wg    sync.WaitGroup
+}
+
+func newKeepAlive(interval time.Duration, u string) *keepAlive {
+	return &keepAlive{interval: interval, url: u, clock: systemClock, stops: make(map[uuid.UUID]context.CancelFunc)}
+}
+
+// track starts or stops pinging the session after a successful command.
+func (k *keepAlive) track(c *client, cmd *flaresolverrCommand) {
+	id, err := uuid.Parse(cmd.Session)
+	if err != nil {
+		return
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	switch cmd.Cmd {
+	case CommandSessionscreate:
+		if _, ok := k.stops[id]; ok {
+			return
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		k.stops[id] = cancel
+		k.wg.Add(1)
+		go k.run(ctx, c, id)
+	case CommandSessionsdestroy:
+		if stop, ok := k.stops[id]; ok {
+			stop()
+			delete(k.stops, id)
+		}
+	}
+}
+
+// reset stops pinging every session.
+func (k *keepAlive) reset() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for id, stop := range k.stops {
+		stop()
+		delete(k.stops, id)
+	}
+}
+
+// close stops pinging every session, waiting for the pings in progress.
+func (k *keepAlive) close() {
+	k.reset()
+	k.wg.Wait()
+}
+
+func (k *keepAlive) run(ctx context.Context, c *client, id uuid.UUID) {
+	defer k.wg.Done()
+	for {
+		timer := k.clock.NewTimer(k.interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+
+		// pings bypass the cache and deduplication
+		cmd := &flaresolverrCommand{Cmd: CommandSessionslist}
+		if k.url != "" {
+			cmd = &flaresolverrCommand{Cmd: CommandRequestget, URL: k.url, Session: id.String(), ReturnOnlyCookies: true}
+		}
+
+		if _, err := c.run(ctx, cmd, &requestOptions{priority: PriorityLow}); err != nil && ctx.Err() == nil {
+			c.log(ctx, slog.LevelWarn, "flaresolverr session keep-alive failed", slog.String("session", id.String()), slog.Any("error", err))
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_client_WithSessionKeepAlive(t *testing.T) {
+	var pings atomic.Int32
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		if cmd.Cmd == CommandRequestget && cmd.URL == "https://example.com/ping" {
+			pings.Add(1)
+		}
+		return http.StatusOK, &Response{Status: "ok"}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithSessionKeepAlive(20*time.Millisecond, "https://example.com/ping"))
+
+	ctx := context.Background()
+	session := uuid.New()
+	if _, err := c.CreateSession(ctx, session); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	time.Sleep(110 * time.Millisecond)
+	if err := c.DestroySession(ctx, session); err != nil {
+		t.Fatalf("DestroySession() error = %v", err)
+	}
+
+	got := pings.Load()
+	if got < 3 {
+		t.Errorf("session pinged %d times, want at least 3", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if after := pings.Load(); after > got+1 {
+		t.Errorf("session pinged %d times after being destroyed", after-got)
+	}
+}
@@ -0,0 +1,138 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrBadCredentials when the login form was submitted but the success predicate did not match.
+	ErrBadCredentials = errors.New("bad credentials")
+
+	// ErrLoginChallenge when the challenge protecting the login page could not be solved.
+	ErrLoginChallenge = errors.New("login challenge not solved")
+
+	// ErrLoginCaptcha when the login page is protected by a captcha FlareSolverr cannot solve.
+	ErrLoginCaptcha = errors.New("login protected by a captcha")
+)
+
+// LoginFlow scripts a login through FlareSolverr:
+// it solves the login page, submits the credentials and checks the login succeeded.
+type LoginFlow struct {
+	// LoginURL is the URL of the page holding the login form.
+	LoginURL string
+
+	// Form is the name or id of the login form. The first form is used when empty.
+	Form string
+
+	// Credentials are the form fields to set before submitting it,
+	// such as username and password. Hidden fields are kept.
+	Credentials map[string]string
+
+	// Success reports whether the response to the submitted form is logged in.
+	Success func(*Response) bool
+
+	// Proxy is used for the whole flow when set.
+	Proxy string
+
+	// Retry controls how failed attempts are retried.
+	// Bad credentials and captchas are never retried.
+	Retry RetryPolicy
+}
+
+// Login is a successful login.
+type Login struct {
+	// Session holds the logged-in browser.
+	// Destroy it once done.
+	Session uuid.UUID
+
+	// Clearance holds the logged-in cookies and user agent.
+	Clearance *Clearance
+
+	// Response is the response to the submitted login form.
+	Response *Response
+}
+
+// Run logs in using c. Failures wrap ErrBadCredentials, ErrLoginChallenge or ErrLoginCaptcha.
+// Every attempt runs in a new session, which is destroyed if the attempt fails.
+func (f *LoginFlow) Run(ctx context.Context, c Client) (*Login, error) {
+	if f.Success == nil {
+		return nil, errors.New("login flow requires a success predicate")
+	}
+
+	var login *Login
+	err := f.Retry.retry(ctx, systemClock, func(int) error {
+		var err error
+		login, err = f.attempt(ctx, c)
+		return err
+	}, func(err error) bool {
+		return !errors.Is(err, ErrBadCredentials) && !errors.Is(err, ErrLoginCaptcha)
+	})
+
+	return login, err
+}
+
+func (f *LoginFlow) attempt(ctx context.Context, c Client) (_ *Login, err error) {
+	var opts []RequestOption
+	if f.Proxy != "" {
+		opts = append(opts, WithProxy(f.Proxy))
+	}
+
+	session := uuid.New()
+	if _, err := c.CreateSessionWithOptions(ctx, session, opts...); err != nil {
+		return nil, fmt.Errorf("cannot create login session: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			// ctx may be the reason of the failure
+			_ = c.DestroySession(context.Background(), session)
+		}
+	}()
+
+	page, err := c.GetWithOptions(ctx, f.LoginURL, session, opts...)
+	if err != nil {
+		return nil, loginError(err)
+	}
+
+	if page.Solution == nil {
+		return nil, fmt.Errorf("%w: empty solution", ErrLoginChallenge)
+	}
+
+	form, err := page.Solution.Form(f.Form)
+	if err != nil {
+		// the challenge page may have been returned instead of the login page
+		return nil, fmt.Errorf("%w: %w", ErrLoginChallenge, err)
+	}
+
+	for name, value := range f.Credentials {
+		form.Set(name, value)
+	}
+
+	resp, err := c.SubmitForm(ctx, form, append(opts, WithSession(session))...)
+	if err != nil {
+		return nil, loginError(err)
+	}
+
+	if !f.Success(resp) {
+		return nil, ErrBadCredentials
+	}
+
+	login := &Login{Session: session, Response: resp}
+	if resp.Solution != nil {
+		login.Clearance = resp.Solution.Clearance()
+	}
+
+	return login, nil
+}
+
+func loginError(err error) error {
+	if errors.Is(err, ErrCaptchaDetected) || errors.Is(err, ErrCustomCaptchaDetected) {
+		return fmt.Errorf("%w: %w", ErrLoginCaptcha, err)
+	}
+
+	return fmt.Errorf("%w: %w", ErrLoginChallenge, err)
+}
@@ -0,0 +1,106 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoginFlow_Run(t *testing.T) {
+	type args struct {
+		password string
+		loginMsg string
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantErr       error
+		wantDestroyed int
+	}{
+		{
+			name: "Logged in",
+			args: args{password: "secret"},
+		},
+		{
+			name:          "Bad credentials",
+			args:          args{password: "wrong"},
+			wantErr:       ErrBadCredentials,
+			wantDestroyed: 1,
+		},
+		{
+			name:          "Captcha",
+			args:          args{password: "secret", loginMsg: "Captcha detected but no automatic solver is configured."},
+			wantErr:       ErrLoginCaptcha,
+			wantDestroyed: 1,
+		},
+		{
+			name:          "Custom captcha",
+			args:          args{password: "secret", loginMsg: "Captcha detected but 'CAPTCHA_SOLVER' env var not set"},
+			wantErr:       ErrLoginCaptcha,
+			wantDestroyed: 1,
+		},
+		{
+			name:          "Challenge not solved",
+			args:          args{password: "secret", loginMsg: "Error solving the challenge. Timeout after 1.0 seconds."},
+			wantErr:       ErrLoginChallenge,
+			wantDestroyed: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			destroyed := 0
+			c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				switch cmd.Cmd {
+				case CommandSessionsdestroy:
+					destroyed++
+				case CommandRequestget:
+					if tt.args.loginMsg != "" {
+						return http.StatusInternalServerError, &Response{Status: "error", Message: tt.args.loginMsg}
+					}
+					return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Response: loginPage}}
+				case CommandRequestpost:
+					data, _ := url.ParseQuery(cmd.PostData)
+					body := "Invalid password"
+					if data.Get("csrf_token") == "abc123" && data.Get("password") == "secret" {
+						body = "Welcome foo"
+					}
+					return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Response: body, UserAgent: "bar"}}
+				}
+
+				return http.StatusOK, &Response{Status: "ok"}
+			})
+
+			flow := &LoginFlow{
+				LoginURL:    "https://example.com/account/",
+				Form:        "login",
+				Credentials: map[string]string{"username": "foo", "password": tt.args.password},
+				Success: func(resp *Response) bool {
+					return strings.Contains(resp.Solution.Response, "Welcome")
+				},
+				Retry: RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+			}
+
+			login, err := flow.Run(context.Background(), c)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if destroyed != tt.wantDestroyed {
+				t.Errorf("Run() destroyed %d sessions, want %d", destroyed, tt.wantDestroyed)
+			}
+
+			if err == nil && (login.Clearance.UserAgent != "bar" || login.Session.String() == "") {
+				t.Errorf("Run() = %+v", login)
+			}
+		})
+	}
+}
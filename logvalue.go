@@ -0,0 +1,146 @@
+package flaresolverr
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// The core types implement fmt.Stringer and slog.LogValuer with concise summaries:
+// page bodies and cookie values are never included.
+var (
+	_ slog.LogValuer = (*Response)(nil)
+	_ slog.LogValuer = (*ResponseSolution)(nil)
+	_ slog.LogValuer = (*Clearance)(nil)
+	_ slog.LogValuer = (*Session)(nil)
+	_ slog.LogValuer = Command("")
+)
+
+// LogValue implements slog.LogValuer.
+func (r *Response) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("status", r.Status.String())}
+	if r.Message != "" {
+		attrs = append(attrs, slog.String("message", r.Message))
+	}
+	if r.Session != "" {
+		attrs = append(attrs, slog.String("session", r.Session))
+	}
+	if len(r.Sessions) > 0 {
+		attrs = append(attrs, slog.Int("sessions", len(r.Sessions)))
+	}
+	if r.EndTimestamp > 0 {
+		attrs = append(attrs, slog.Duration("duration", r.Duration()))
+	}
+	if r.Solution != nil {
+		attrs = append(attrs, slog.Any("solution", r.Solution))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// String returns a summary of the response.
+func (r *Response) String() string {
+	return summary(r.LogValue())
+}
+
+// LogValue implements slog.LogValuer.
+func (s *ResponseSolution) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("url", redactURL(s.URL)),
+		slog.Int("status", s.Status),
+		slog.Int64("bytes", s.BodySize()),
+		slog.String("cookies", cookieNames(s.HTTPCookies())),
+		slog.String("user_agent", s.UserAgent),
+	)
+}
+
+// String returns a summary of the solution.
+func (s *ResponseSolution) String() string {
+	return summary(s.LogValue())
+}
+
+// LogValue implements slog.LogValuer.
+func (c *Clearance) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("url", redactURL(c.URL)),
+		slog.String("cookies", cookieNames(c.Cookies)),
+		slog.String("user_agent", c.UserAgent),
+	)
+}
+
+// String returns a summary of the clearance.
+func (c *Clearance) String() string {
+	return summary(c.LogValue())
+}
+
+// LogValue implements slog.LogValuer.
+func (s *Session) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", s.ID.String()),
+		slog.String("user_agent", s.UserAgent()),
+	)
+}
+
+// String returns a summary of the session.
+func (s *Session) String() string {
+	return summary(s.LogValue())
+}
+
+// LogValue implements slog.LogValuer.
+func (x Command) LogValue() slog.Value {
+	return slog.StringValue(x.String())
+}
+
+// summary formats a group value as space separated key=value pairs.
+func summary(v slog.Value) string {
+	var b strings.Builder
+	for i, attr := range v.Group() {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+
+		value := attr.Value.Resolve()
+		b.WriteString(attr.Key + "=")
+		switch value.Kind() {
+		case slog.KindGroup:
+			b.WriteString("{" + summary(value) + "}")
+		case slog.KindString:
+			if s := value.String(); s == "" || strings.ContainsAny(s, " ={}\"") {
+				b.WriteString(strconv.Quote(s))
+			} else {
+				b.WriteString(s)
+			}
+		default:
+			b.WriteString(value.String())
+		}
+	}
+
+	return b.String()
+}
+
+// redactURL removes the password, query and fragment of u, which may hold credentials.
+func redactURL(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+
+	if parsed.RawQuery != "" {
+		parsed.RawQuery = "redacted"
+	}
+	parsed.Fragment = ""
+
+	return parsed.Redacted()
+}
+
+// cookieNames returns the comma separated cookie names.
+func cookieNames(cookies []*http.Cookie) string {
+	names := make([]string, 0, len(cookies))
+	for _, cookie := range cookies {
+		names = append(names, cookie.Name)
+	}
+
+	return strings.Join(names, ",")
+}
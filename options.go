@@ -0,0 +1,604 @@
+package flaresolverr
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
+)
+
+// Option configures the client.
+type Option func(*client)
+
+// WithLogger logs the commands sent to FlareSolverr and their outcome.
+// Nothing is logged by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *client) {
+		c.logger = logger
+	}
+}
+
+// WithHeader adds a header to every request sent to FlareSolverr,
+// e.g. to authenticate against a reverse proxy in front of it.
+func WithHeader(key, value string) Option {
+	return func(c *client) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+		c.header.Add(key, value)
+	}
+}
+
+// WithBasicAuth authenticates every request sent to FlareSolverr with HTTP basic authentication.
+func WithBasicAuth(username, password string) Option {
+	credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	return withAuthorization("Basic " + credentials)
+}
+
+// WithBearerToken authenticates every request sent to FlareSolverr with a bearer token,
+// for deployments exposed behind an authentication gateway.
+func WithBearerToken(token string) Option {
+	return withAuthorization("Bearer " + token)
+}
+
+// withAuthorization replaces the Authorization header, only one scheme can be used.
+func withAuthorization(value string) Option {
+	return func(c *client) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+		c.header.Set("Authorization", value)
+	}
+}
+
+// WithTimeoutPadding sets how long to wait for the FlareSolverr answer after the client timeout,
+// which is also the challenge timeout sent to FlareSolverr. Defaults to 10 seconds.
+func WithTimeoutPadding(d time.Duration) Option {
+	return func(c *client) {
+		c.timeoutPadding = d
+	}
+}
+
+// WithCancelInFlight stops the FlareSolverr browser when the caller gives up on a request.
+// FlareSolverr cannot cancel a command, so every request made without a session
+// runs in a temporary session, which is destroyed once the request is done or abandoned.
+// This costs two more commands per request.
+func WithCancelInFlight() Option {
+	return func(c *client) {
+		c.cancelInFlight = true
+	}
+}
+
+// WithCompression compresses the commands of at least minSize bytes with gzip, such as large post data,
+// to reduce the transfer time to a remote FlareSolverr. Commands are only compressed once the server
+// advertised support with an Accept-Encoding response header, e.g. from a reverse proxy.
+// Compressed responses are decompressed transparently.
+func WithCompression(minSize int) Option {
+	return func(c *client) {
+		c.compression = &compression{minSize: minSize}
+	}
+}
+
+// WithTransport reaches FlareSolverr through rt, e.g. over an SSH tunnel.
+// Only the requests to FlareSolverr use it: direct requests to the targets,
+// such as downloads, keep using the client http.Client.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *client) {
+		apiClient := *c.httpClient
+		apiClient.Transport = rt
+		c.apiClient = &apiClient
+	}
+}
+
+// WithDialContext reaches FlareSolverr through connections opened by dial,
+// using a copy of http.DefaultTransport. See WithTransport.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dial
+	return WithTransport(transport)
+}
+
+// WithUnixSocket reaches FlareSolverr over the Unix domain socket at path.
+// The host of the base URL is ignored, e.g. use "http://flaresolverr/v1". See WithTransport.
+func WithUnixSocket(path string) Option {
+	return WithDialContext(func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", path)
+	})
+}
+
+// WithProfile adapts the client to the quirks of a FlareSolverr implementation,
+// such as ProfileByparr. Defaults to ProfileFlareSolverr.
+func WithProfile(profile ServerProfile) Option {
+	return func(c *client) {
+		c.profile = &profile
+	}
+}
+
+// WithSessionKeepAlive keeps the sessions created through the client warm,
+// for FlareSolverr versions expiring idle sessions: every interval, u is requested
+// within each session, or the sessions are listed if u is empty.
+// Pinging a session stops once it is destroyed through the client.
+func WithSessionKeepAlive(interval time.Duration, u string) Option {
+	return func(c *client) {
+		c.keepAlive = newKeepAlive(interval, u)
+	}
+}
+
+// WithSessionRecreate recreates the sessions which do not exist on the server anymore,
+// e.g. after a FlareSolverr restart: a request failing with ErrSessionNotFound creates
+// the session again with the same ID, and is retried once.
+// The cookies of the lost browser are not restored.
+func WithSessionRecreate() Option {
+	return func(c *client) {
+		c.recreateSessions = true
+	}
+}
+
+// WithSessionPerDomain routes the requests made without session to a session of their target site,
+// e.g. example.com for www.example.com, so cookies are never shared between sites and challenges
+// are solved once per site. Sessions are created on first use with a "site" label,
+// and destroyed once idle for the given duration.
+func WithSessionPerDomain(idle time.Duration) Option {
+	return func(c *client) {
+		c.domainSessions = newDomainSessions(idle)
+	}
+}
+
+// WithMaxInFlight protects FlareSolverr by sending at most n commands at the same time,
+// as the server degrades badly with many concurrent browser tabs.
+// Up to queue commands wait for a free slot, by priority then in order, see WithPriority.
+// The next ones fail with ErrTooManyInFlight;
+// a negative queue lets every command wait.
+func WithMaxInFlight(n, queue int) Option {
+	return func(c *client) {
+		c.inFlight = newInFlightGuard(n, queue)
+	}
+}
+
+// WithAdaptiveTimeout sets the maxTimeout of the requests to each domain to the 95th percentile
+// of its recent solve durations plus a margin, instead of the client timeout,
+// so stuck solves of fast sites are abandoned early. The client timeout stays the upper bound,
+// and is used until a few solves of the domain have been observed.
+func WithAdaptiveTimeout() Option {
+	return func(c *client) {
+		c.adaptive = newAdaptiveTimeout()
+	}
+}
+
+// WithStrictDecoding rejects the responses with fields not modeled by the client, using
+// encoding/json's DisallowUnknownFields, to surface changes of the FlareSolverr API early.
+// With WithJSONCodec, the codec decodes the responses and unknown top-level fields are rejected;
+// the nested ones only if the codec is configured to, e.g. jsoniter's DisallowUnknownFields.
+// By default, unknown top-level fields are kept in Response.Extra.
+func WithStrictDecoding() Option {
+	return func(c *client) {
+		c.strictDecoding = true
+	}
+}
+
+// WithRestartDetection detects FlareSolverr restarts, from a change of the version reported by the server
+// or a session used through the client which does not exist anymore. On restart, the sessions tracked
+// by the client and the cached clearances are forgotten, then onRestart is called if not nil.
+func WithRestartDetection(onRestart func(ServerRestart)) Option {
+	return func(c *client) {
+		c.restarts = &restartDetector{onRestart: onRestart, clock: systemClock}
+	}
+}
+
+// WithHooks calls hooks during the commands lifecycle.
+func WithHooks(hooks Hooks) Option {
+	return func(c *client) {
+		c.hooks = &hooks
+	}
+}
+
+// WithRateLimitRetry waits and retries the commands rate limited by the target website,
+// see ErrTargetRateLimited, as long as the total time waited for a command stays within budget.
+// The wait is the Retry-After of the target, 10s when it has none. Disabled by default.
+func WithRateLimitRetry(budget time.Duration) Option {
+	return func(c *client) {
+		c.rateLimitBudget = budget
+	}
+}
+
+// FailOnHTTPError returns a *HTTPStatusError, see ErrTargetHTTPStatus, when the target website
+// answers with a 4xx or 5xx status. By default, the solution is returned whatever its status.
+func FailOnHTTPError() Option {
+	return func(c *client) {
+		c.failOnHTTPError = true
+	}
+}
+
+// WithChallengeRetry retries up to retries times the requests whose solution is still
+// the challenge page, see ErrChallengePageReturned. The session of the request is destroyed
+// and created again with the same ID before each retry, so the browser starts afresh.
+func WithChallengeRetry(retries int) Option {
+	return func(c *client) {
+		c.challengeRetries = retries
+	}
+}
+
+// WithProxyRotation sends the requests made outside of a session, and the sessions created,
+// through proxies in turn, when no proxy is set with WithProxy. Every proxy is scored
+// on its latest requests, and quarantined for a cooldown when unhealthy following policy.
+// Commands fail with ErrNoHealthyProxy while every proxy is quarantined,
+// and with ErrInvalidProxy if one of the proxies is invalid, see NormalizeProxy.
+// The scores are reported to Hooks.OnProxyScored.
+func WithProxyRotation(proxies []string, policy ProxyHealthPolicy) Option {
+	return func(c *client) {
+		if len(proxies) > 0 {
+			c.proxies = newProxyRotation(proxies, policy)
+		}
+	}
+}
+
+// WithClock makes the client tell the time and wait with clock, rather than the system clock:
+// the timeouts, the response and clearance caches, the sessions, the retries and the other
+// delays of the client. Tests use a fake clock to run them without real sleeps.
+func WithClock(clock Clock) Option {
+	return func(c *client) {
+		c.clock = clock
+	}
+}
+
+// WithUUIDSource generates the IDs of the sessions the client creates on its own with source,
+// such as the temporary sessions of WithCancelInFlight and the sessions of WithSessionPerDomain,
+// for reproducible tests or to follow an external ID scheme. Defaults to uuid.New.
+// source must be safe for concurrent use and return unique IDs.
+func WithUUIDSource(source func() uuid.UUID) Option {
+	return func(c *client) {
+		c.uuidSource = source
+	}
+}
+
+// WithAuditLog appends a JSON line per command to w, see AuditRecord.
+// Full URLs, post data and cookies are never written. Writes to w are serialized.
+func WithAuditLog(w io.Writer) Option {
+	return func(c *client) {
+		c.auditLog = newAuditLog(w)
+	}
+}
+
+// WithRedaction sets the sensitive data allowed in the logs and errors, see RedactionPolicy.
+// The post data and the cookie values are redacted by default.
+func WithRedaction(policy RedactionPolicy) Option {
+	return func(c *client) {
+		c.redaction = policy
+	}
+}
+
+// WithJSONCodec marshals the commands and unmarshals the responses with codec,
+// e.g. a faster JSON library for deployments decoding large pages at high volume.
+// Defaults to encoding/json.
+func WithJSONCodec(codec JSONCodec) Option {
+	return func(c *client) {
+		c.codec = codec
+	}
+}
+
+// WithRetryPolicy retries the commands failing because of a FlareSolverr timeout or a network error.
+// Commands are not retried by default, and POST requests only with RetryPolicy.RetryPOST.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *client) {
+		c.retry = policy
+	}
+}
+
+// WithBodySpill moves solution bodies larger than threshold bytes to temporary files in dir,
+// or the default temporary directory if empty, so the responses kept by the caller do not hold them.
+// The whole response is still read and decoded in memory first, it does not bound the peak memory
+// of a command. Read spilled bodies with ResponseSolution.Body, and Close the solution to remove the file.
+// The responses shared through WithResponseCache or WithSingleflight are kept in memory.
+func WithBodySpill(threshold int64, dir string) Option {
+	return func(c *client) {
+		c.spillThreshold = threshold
+		c.spillDir = dir
+	}
+}
+
+// WithErrorSuppression collapses identical errors logged within window:
+// the first one is logged, the repeats are summarized with their count
+// and first and last timestamps once the window ends.
+func WithErrorSuppression(window time.Duration) Option {
+	return func(c *client) {
+		c.errorSuppressor = newSuppressor(window, c.logSuppressed)
+	}
+}
+
+// WithRawResponses keeps the raw FlareSolverr response body in Response.Raw,
+// to extract fields the client does not model or debug protocol mismatches.
+func WithRawResponses() Option {
+	return func(c *client) {
+		c.rawResponses = true
+	}
+}
+
+// WithDownloadBandwidth limits the bandwidth used by direct downloads to bytesPerSecond.
+// The limit is shared by all the downloads made with the client. Zero or less means unlimited.
+func WithDownloadBandwidth(bytesPerSecond int) Option {
+	return func(c *client) {
+		if bytesPerSecond <= 0 {
+			c.downloadLimiter = nil
+			return
+		}
+		c.downloadLimiter = rate.NewLimiter(rate.Limit(bytesPerSecond), bytesPerSecond)
+	}
+}
+
+// WithMaxConcurrentDownloads limits the number of direct downloads running at the same time.
+// Downloads wait for a slot, or until their context is done. Zero or less means unlimited.
+func WithMaxConcurrentDownloads(n int) Option {
+	return func(c *client) {
+		if n <= 0 {
+			c.downloadSlots = nil
+			return
+		}
+		c.downloadSlots = make(chan struct{}, n)
+	}
+}
+
+// WithPoliteness enforces a minimum delay between the requests made to the same hostname,
+// to avoid triggering stricter protections during bulk jobs.
+// perDomain overrides delay for some domains and their subdomains, e.g. "example.com".
+func WithPoliteness(delay time.Duration, perDomain map[string]time.Duration) Option {
+	return func(c *client) {
+		c.politeness = newPoliteness(delay, perDomain)
+	}
+}
+
+// WithSingleflight collapses concurrent identical requests into a single FlareSolverr command,
+// and concurrent challenge solves of the same domain made by Download and HTTPClientFor into a single solve.
+// The callers share the same result, which must not be modified.
+func WithSingleflight() Option {
+	return func(c *client) {
+		c.flight = new(singleflight.Group)
+	}
+}
+
+// WithResponseCache serves identical requests, with the same command, URL and post data,
+// from a cache for ttl. The cache holds up to maxEntries responses, unlimited if zero,
+// evicting the least recently used ones. Cached responses are shared and must not be modified.
+func WithResponseCache(ttl time.Duration, maxEntries int) Option {
+	return func(c *client) {
+		c.cache = newResponseCache(ttl, maxEntries)
+	}
+}
+
+// WithRevalidation extends the response cache: on a cache miss, requests to a hostname
+// whose clearance cookies are still valid are first made directly with these cookies and
+// the user agent they were issued for. FlareSolverr is only used when a new challenge is detected,
+// or for requests within a session or through a proxy. Requires WithResponseCache.
+func WithRevalidation() Option {
+	return func(c *client) {
+		c.clearances = newClearanceCache()
+	}
+}
+
+// RequestOption customizes a single request made through the client.
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	session   uuid.UUID
+	sessionID SessionID
+	proxy     string
+	requestID string
+	extra     map[string]any
+	cookies   []*http.Cookie
+	userAgent string
+	labels    map[string]string
+	priority  Priority
+
+	maxTimeout   time.Duration
+	maxRedirects int
+
+	// sessionPolicy and cacheTTL are set by the domain configuration, see WithDomainConfig.
+	sessionPolicy SessionPolicy
+	cacheTTL      time.Duration
+
+	postLoadWait    time.Duration
+	waitForSelector string
+	screenshot      bool
+	base64          bool
+	onlyCookies     bool
+
+	// recreated marks the commands replacing a session by the client, see sessionRegistry.recreation.
+	recreated bool
+}
+
+// newRequestOptions applies the options carried by ctx, then opts.
+func newRequestOptions(ctx context.Context, opts []RequestOption) *requestOptions {
+	o := new(requestOptions)
+	for _, opt := range contextRequestOptions(ctx) {
+		opt(o)
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// sessionFor returns the session of a request: session, or the session set by
+// WithSessionID or WithSession if uuid.Nil.
+func (o *requestOptions) sessionFor(session uuid.UUID) string {
+	if session == uuid.Nil && o.sessionID == "" {
+		session = o.session
+	}
+
+	return o.sessionIDFor(session)
+}
+
+// sessionIDFor returns session, or the session set by WithSessionID if uuid.Nil.
+func (o *requestOptions) sessionIDFor(session uuid.UUID) string {
+	if session != uuid.Nil {
+		return session.String()
+	}
+
+	return o.sessionID.String()
+}
+
+// WithSession makes the request using the given session.
+// The session passed as argument to Get or Post takes precedence.
+func WithSession(session uuid.UUID) RequestOption {
+	return func(o *requestOptions) {
+		o.session = session
+	}
+}
+
+// WithSessionID makes the request within the session with the given ID, for session IDs
+// which are not google/uuid UUIDs, see SessionID. It applies to CreateSession and DestroySession
+// called with uuid.Nil too. The session passed as argument takes precedence.
+// The request fails with ErrInvalidSessionID if id is not valid, see ParseSessionID.
+// Only the UUID sessions are tracked by the client, see Client.Session.
+func WithSessionID(id SessionID) RequestOption {
+	return func(o *requestOptions) {
+		o.sessionID = id
+	}
+}
+
+// WithMaxTimeout gives FlareSolverr d to solve the challenge of the request,
+// instead of the client timeout or the one of WithAdaptiveTimeout.
+func WithMaxTimeout(d time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.maxTimeout = d
+	}
+}
+
+// WithProxy makes the request through the given proxy, normalized with NormalizeProxy.
+// The request fails with ErrInvalidProxy if it is invalid.
+// Requests within a session use the proxy the session was created with,
+// they fail with ErrSessionProxyMismatch when given another one.
+func WithProxy(proxy string) RequestOption {
+	return func(o *requestOptions) {
+		o.proxy = proxy
+	}
+}
+
+// WithRequestID attaches a correlation ID to the request.
+// It is sent to FlareSolverr in the X-Request-Id header,
+// and included in the client logs, execution traces and returned errors.
+func WithRequestID(id string) RequestOption {
+	return func(o *requestOptions) {
+		o.requestID = id
+	}
+}
+
+// WithCookies seeds the browser of a session created with CreateSession with cookies,
+// e.g. to start from a logged-in identity.
+func WithCookies(cookies []*http.Cookie) RequestOption {
+	return func(o *requestOptions) {
+		o.cookies = append(o.cookies, cookies...)
+	}
+}
+
+// WithLabel attaches a label to a session created with CreateSession.
+// Labels are only kept by the client, see FindSessionByLabel.
+func WithLabel(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		if o.labels == nil {
+			o.labels = make(map[string]string)
+		}
+		o.labels[key] = value
+	}
+}
+
+// WithPriority sets the priority of the request when waiting for a slot of WithMaxInFlight,
+// so latency-sensitive requests jump ahead of bulk traffic sharing the client.
+func WithPriority(priority Priority) RequestOption {
+	return func(o *requestOptions) {
+		o.priority = priority
+	}
+}
+
+// WithUserAgent overrides the browser user agent for the request.
+// Only some FlareSolverr forks support it, see ServerProfile.UserAgent:
+// the request fails with ErrUnsupportedByServer otherwise.
+func WithUserAgent(userAgent string) RequestOption {
+	return func(o *requestOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithPostLoadWait waits d after the challenge is solved before returning the page,
+// so the content loaded by JavaScript is present in the solution. It is sent as waitInSeconds,
+// rounded up to the second. Only some servers support it, see ServerProfile.PostLoadWait:
+// the request fails with ErrUnsupportedByServer otherwise.
+func WithPostLoadWait(d time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.postLoadWait = d
+	}
+}
+
+// WithWaitForSelector waits for an element matching the CSS selector after the challenge
+// is solved before returning the page. Only some FlareSolverr forks support it,
+// see ServerProfile.WaitForSelector: the request fails with ErrUnsupportedByServer otherwise.
+func WithWaitForSelector(css string) RequestOption {
+	return func(o *requestOptions) {
+		o.waitForSelector = css
+	}
+}
+
+// WithScreenshot asks the server for a screenshot of the page, to see why a solve fails.
+// Read it with ResponseSolution.Screenshot. Servers without screenshot support ignore it.
+func WithScreenshot() RequestOption {
+	return func(o *requestOptions) {
+		o.screenshot = true
+	}
+}
+
+// WithBase64Response asks the server for the page body encoded in base64, decoded by the client,
+// so binary or non UTF-8 content reaches ResponseSolution.Response unaltered.
+// Only some FlareSolverr forks support it, see ServerProfile.Base64Response:
+// the request fails with ErrUnsupportedByServer otherwise.
+func WithBase64Response() RequestOption {
+	return func(o *requestOptions) {
+		o.base64 = true
+	}
+}
+
+// WithReturnOnlyCookies asks the server for the cookies and user agent of the solution only,
+// without the page content, for Get and Post.
+func WithReturnOnlyCookies() RequestOption {
+	return func(o *requestOptions) {
+		o.onlyCookies = true
+	}
+}
+
+// waitInSeconds returns the wait of WithPostLoadWait, rounded up to the second.
+func (o *requestOptions) waitInSeconds() int {
+	return int((o.postLoadWait + time.Second - 1) / time.Second)
+}
+
+// WithExtraFields adds fields to the command sent to FlareSolverr, to reach features
+// the client does not model, such as options of newer versions or forks, e.g. "disableMedia".
+// The fields set by the client take precedence.
+func WithExtraFields(fields map[string]any) RequestOption {
+	return func(o *requestOptions) {
+		if o.extra == nil {
+			o.extra = make(map[string]any, len(fields))
+		}
+		for key, value := range fields {
+			o.extra[key] = value
+		}
+	}
+}
+
+// WithFollowRedirects follows up to maxHops meta refresh and JavaScript redirects found
+// in the solved page, for sites bouncing through an interstitial after the challenge.
+// The JavaScript redirects are the top-level assignments of the location, such as
+// window.location.href = "/next", the conditional ones are ignored.
+// The follow-up requests are GET requests in the same session, or carry the cookies of
+// the previous solution without session. The last solution is returned when maxHops is reached.
+func WithFollowRedirects(maxHops int) RequestOption {
+	return func(o *requestOptions) {
+		o.maxRedirects = maxHops
+	}
+}
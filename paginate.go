@@ -0,0 +1,134 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrStopPagination is returned by a NextPageFunc or a PageHandler to stop Paginate without error.
+var ErrStopPagination = errors.New("stop pagination")
+
+// NextPageFunc returns the URL of the page following resp, or an empty string on the last page.
+type NextPageFunc func(resp *Response) (string, error)
+
+// PageHandler processes a page. page is the index of the page, starting at 0.
+type PageHandler func(page int, resp *Response) error
+
+// PaginateOption customizes Paginate.
+type PaginateOption func(*paginateOptions)
+
+type paginateOptions struct {
+	delay    time.Duration
+	maxPages int
+	retry    RetryPolicy
+	session  uuid.UUID
+	request  []RequestOption
+}
+
+// WithPageDelay waits d between two pages.
+func WithPageDelay(d time.Duration) PaginateOption {
+	return func(o *paginateOptions) {
+		o.delay = d
+	}
+}
+
+// WithMaxPages stops Paginate after n pages. Values below 1 mean no limit.
+func WithMaxPages(n int) PaginateOption {
+	return func(o *paginateOptions) {
+		o.maxPages = n
+	}
+}
+
+// WithPageRetry retries the pages which failed with a FlareSolverr timeout or a network error.
+func WithPageRetry(p RetryPolicy) PaginateOption {
+	return func(o *paginateOptions) {
+		o.retry = p
+	}
+}
+
+// WithPageSession walks the pages within the given session, which is not destroyed.
+// A session is created and destroyed by Paginate otherwise.
+func WithPageSession(session uuid.UUID) PaginateOption {
+	return func(o *paginateOptions) {
+		o.session = session
+	}
+}
+
+// WithPageRequestOptions applies opts to the requests of every page,
+// and to the session created by Paginate.
+func WithPageRequestOptions(opts ...RequestOption) PaginateOption {
+	return func(o *paginateOptions) {
+		o.request = append(o.request, opts...)
+	}
+}
+
+// Paginate walks a paginated listing starting at firstURL, passing every page to handle
+// and asking next for the URL of the following one. Every page is requested within
+// the same session, so the challenge is solved once.
+// It stops without error on the last page, or when next or handle return ErrStopPagination.
+//
+//	err := flaresolverr.Paginate(ctx, c, "https://example.com/list?page=1", next, handle, flaresolverr.WithPageDelay(time.Second))
+func Paginate(ctx context.Context, c Client, firstURL string, next NextPageFunc, handle PageHandler, opts ...PaginateOption) error {
+	o := &paginateOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	session := o.session
+	if session == uuid.Nil {
+		session = uuid.New()
+		if _, err := c.CreateSessionWithOptions(ctx, session, o.request...); err != nil {
+			return fmt.Errorf("cannot create pagination session: %w", err)
+		}
+
+		// ctx may be done already
+		defer func() {
+			_ = c.DestroySession(context.Background(), session)
+		}()
+	}
+
+	u := firstURL
+	for page := 0; u != "" && (o.maxPages < 1 || page < o.maxPages); page++ {
+		if page > 0 && o.delay > 0 {
+			timer := time.NewTimer(o.delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		var resp *Response
+		err := o.retry.retry(ctx, systemClock, func(int) error {
+			var err error
+			resp, err = c.GetWithOptions(ctx, u, session, o.request...)
+			return err
+		}, retryable)
+		if err != nil {
+			return fmt.Errorf("page %d (%s): %w", page, u, err)
+		}
+
+		if err := handle(page, resp); err != nil {
+			if errors.Is(err, ErrStopPagination) {
+				return nil
+			}
+			return fmt.Errorf("page %d (%s): %w", page, u, err)
+		}
+
+		nextURL, err := next(resp)
+		if err != nil {
+			if errors.Is(err, ErrStopPagination) {
+				return nil
+			}
+			return fmt.Errorf("page %d (%s): %w", page, u, err)
+		}
+		u = nextURL
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func TestPaginate(t *testing.T) {
+	var mu sync.Mutex
+	var commands []*flaresolverrCommand
+	var timeouts int
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, cmd)
+
+		if cmd.Cmd == CommandRequestget && strings.HasSuffix(cmd.URL, "page=2") && timeouts > 0 {
+			timeouts--
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: maximum timeout reached"}
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Response: cmd.URL}}
+	})
+
+	next := func(resp *Response) (string, error) {
+		var page int
+		if _, err := fmt.Sscanf(resp.Solution.URL, "https://example.com/list?page=%d", &page); err != nil {
+			return "", err
+		}
+		if page == 3 {
+			return "", nil
+		}
+		return fmt.Sprintf("https://example.com/list?page=%d", page+1), nil
+	}
+
+	tests := []struct {
+		name     string
+		opts     []PaginateOption
+		timeouts int
+		stop     bool
+		want     []string
+	}{
+		{
+			name:     "all pages",
+			opts:     []PaginateOption{WithPageRetry(RetryPolicy{MaxAttempts: 2}), WithPageDelay(time.Millisecond)},
+			timeouts: 1,
+			want:     []string{"https://example.com/list?page=1", "https://example.com/list?page=2", "https://example.com/list?page=3"},
+		},
+		{
+			name: "max pages",
+			opts: []PaginateOption{WithMaxPages(2)},
+			want: []string{"https://example.com/list?page=1", "https://example.com/list?page=2"},
+		},
+		{
+			name: "stopped by the handler",
+			stop: true,
+			want: []string{"https://example.com/list?page=1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			commands, timeouts = nil, tt.timeouts
+			mu.Unlock()
+
+			var got []string
+			handle := func(_ int, resp *Response) error {
+				got = append(got, resp.Solution.Response)
+				if tt.stop {
+					return ErrStopPagination
+				}
+				return nil
+			}
+
+			if err := Paginate(context.Background(), c, "https://example.com/list?page=1", next, handle, tt.opts...); err != nil {
+				t.Fatalf("Paginate() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Paginate() pages mismatch (-want +got):\n%s", diff)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			first, last := commands[0], commands[len(commands)-1]
+			if first.Cmd != CommandSessionscreate || last.Cmd != CommandSessionsdestroy || first.Session != last.Session {
+				t.Fatalf("Paginate() commands = %s ... %s, want a session created then destroyed", first.Cmd, last.Cmd)
+			}
+			for _, cmd := range commands[1 : len(commands)-1] {
+				if cmd.Session != first.Session {
+					t.Errorf("Paginate() requested %s in session %q, want %q", cmd.URL, cmd.Session, first.Session)
+				}
+			}
+		})
+	}
+}
+
+func TestPaginate_errors(t *testing.T) {
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		if strings.HasSuffix(cmd.URL, "/blocked") {
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: Captcha detected but no automatic solver is configured."}
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+
+	session := uuid.New()
+	next := func(*Response) (string, error) { return "https://example.com/blocked", nil }
+	handle := func(int, *Response) error { return nil }
+
+	err := Paginate(context.Background(), c, "https://example.com/", next, handle, WithPageSession(session), WithPageRetry(RetryPolicy{MaxAttempts: 3}))
+	if !errors.Is(err, ErrCaptchaDetected) {
+		t.Errorf("Paginate() error = %v, want %v", err, ErrCaptchaDetected)
+	}
+
+	errNext := errors.New("no next link")
+	err = Paginate(context.Background(), c, "https://example.com/", func(*Response) (string, error) { return "", errNext }, handle, WithPageSession(session))
+	if !errors.Is(err, errNext) {
+		t.Errorf("Paginate() error = %v, want %v", err, errNext)
+	}
+}
@@ -0,0 +1,84 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// politeness spaces the requests made to the same hostname.
+type politeness struct {
+	delay     time.Duration
+	perDomain map[string]time.Duration
+	clock     Clock
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+func newPoliteness(delay time.Duration, perDomain map[string]time.Duration) *politeness {
+	domains := make(map[string]time.Duration, len(perDomain))
+	for domain, d := range perDomain {
+		domains[strings.ToLower(domain)] = d
+	}
+
+	return &politeness{delay: delay, perDomain: domains, clock: systemClock, next: make(map[string]time.Time)}
+}
+
+// delayFor returns the delay configured for host, or its closest parent domain.
+func (p *politeness) delayFor(host string) time.Duration {
+	for domain := host; domain != ""; {
+		if d, ok := p.perDomain[domain]; ok {
+			return d
+		}
+
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			break
+		}
+		domain = parent
+	}
+
+	return p.delay
+}
+
+// wait blocks until a request can be made to u, and reserves its slot.
+func (p *politeness) wait(ctx context.Context, u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Hostname() == "" {
+		return nil
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	delay := p.delayFor(host)
+	if delay <= 0 {
+		return nil
+	}
+
+	p.mu.Lock()
+	now := p.clock.Now()
+	for h, at := range p.next {
+		// the passed slots do not delay anything, only the recent hosts are kept
+		if !at.After(now) {
+			delete(p.next, h)
+		}
+	}
+	at := p.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	p.next[host] = at.Add(delay)
+	p.mu.Unlock()
+
+	timer := p.clock.NewTimer(at.Sub(now))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
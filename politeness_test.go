@@ -0,0 +1,72 @@
+package flaresolverr
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_politeness_delayFor(t *testing.T) {
+	p := newPoliteness(time.Second, map[string]time.Duration{"Example.com": 5 * time.Second, "api.example.com": 0})
+
+	tests := []struct {
+		host string
+		want time.Duration
+	}{
+		{host: "example.com", want: 5 * time.Second},
+		{host: "www.example.com", want: 5 * time.Second},
+		{host: "api.example.com", want: 0},
+		{host: "example.org", want: time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.delayFor(tt.host); got != tt.want {
+			t.Errorf("delayFor(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+func Test_politeness_wait(t *testing.T) {
+	p := newPoliteness(50*time.Millisecond, nil)
+	ctx := context.Background()
+
+	start := time.Now()
+	for _, u := range []string{"https://example.com/1", "https://example.org", "https://EXAMPLE.com/2"} {
+		if err := p.wait(ctx, u); err != nil {
+			t.Fatalf("wait(%q) error = %v", u, err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > 100*time.Millisecond {
+		t.Errorf("wait() took %v, want a single delay", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := p.wait(ctx, "https://example.com/3"); err != context.Canceled {
+		t.Errorf("wait() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func Test_politeness_wait_prune(t *testing.T) {
+	clock := newFakeClock()
+	p := newPoliteness(time.Second, nil)
+	p.clock = clock
+	ctx := context.Background()
+
+	for _, u := range []string{"https://a.example.com", "https://b.example.com", "https://c.example.com"} {
+		if err := p.wait(ctx, u); err != nil {
+			t.Fatalf("wait(%q) error = %v", u, err)
+		}
+	}
+	if len(p.next) != 3 {
+		t.Fatalf("next holds %d hosts, want 3", len(p.next))
+	}
+
+	clock.Advance(time.Second)
+	if err := p.wait(ctx, "https://d.example.com"); err != nil {
+		t.Fatalf("wait() error = %v", err)
+	}
+	if _, ok := p.next["d.example.com"]; !ok || len(p.next) != 1 {
+		t.Errorf("next = %v, want d.example.com only", p.next)
+	}
+}
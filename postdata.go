@@ -0,0 +1,87 @@
+package flaresolverr
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrMissingValue when a post data template placeholder has no value.
+var ErrMissingValue = errors.New("missing value for placeholder")
+
+// PostDataTemplate builds application/x-www-form-urlencoded post data
+// from a template with named placeholders, such as "q={query}&page={page}".
+// It can be reused to submit many similar forms.
+type PostDataTemplate struct {
+	parts        []string // literal text, placeholders at odd indexes
+	placeholders []string
+}
+
+// ParsePostDataTemplate parses a post data template.
+// Placeholders are surrounded by braces and must not be empty or nested.
+func ParsePostDataTemplate(tmpl string) (*PostDataTemplate, error) {
+	t := new(PostDataTemplate)
+	for rest := tmpl; ; {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			if strings.IndexByte(rest, '}') >= 0 {
+				return nil, fmt.Errorf("invalid post data template %q: unexpected }", tmpl)
+			}
+			t.parts = append(t.parts, rest)
+			break
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("invalid post data template %q: unclosed {", tmpl)
+		}
+
+		name := rest[start+1 : start+end]
+		if name == "" || strings.ContainsRune(name, '{') || strings.ContainsRune(rest[:start], '}') {
+			return nil, fmt.Errorf("invalid post data template %q: invalid placeholder %q", tmpl, name)
+		}
+
+		t.parts = append(t.parts, rest[:start], name)
+		t.placeholders = append(t.placeholders, name)
+		rest = rest[start+end+1:]
+	}
+
+	return t, nil
+}
+
+// MustParsePostDataTemplate is like ParsePostDataTemplate but panics if the template is invalid.
+func MustParsePostDataTemplate(tmpl string) *PostDataTemplate {
+	t, err := ParsePostDataTemplate(tmpl)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// Placeholders returns the placeholder names in order of appearance.
+func (t *PostDataTemplate) Placeholders() []string {
+	return append([]string(nil), t.placeholders...)
+}
+
+// Execute fills the placeholders with the URL encoded values.
+// Every placeholder must have a value, extra values are ignored.
+func (t *PostDataTemplate) Execute(values map[string]string) (string, error) {
+	var b strings.Builder
+	for i, part := range t.parts {
+		if i%2 == 0 {
+			b.WriteString(part)
+			continue
+		}
+
+		value, ok := values[part]
+		if !ok {
+			return "", fmt.Errorf("%w %q", ErrMissingValue, part)
+		}
+
+		b.WriteString(url.QueryEscape(value))
+	}
+
+	return b.String(), nil
+}
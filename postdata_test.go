@@ -0,0 +1,105 @@
+package flaresolverr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsePostDataTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Without placeholder",
+			tmpl: "foo=bar",
+			want: nil,
+		},
+		{
+			name: "With placeholders",
+			tmpl: "q={query}&page={page}&{key}=1",
+			want: []string{"query", "page", "key"},
+		},
+		{
+			name:    "Unclosed placeholder",
+			tmpl:    "q={query",
+			wantErr: true,
+		},
+		{
+			name:    "Unopened placeholder",
+			tmpl:    "q=query}",
+			wantErr: true,
+		},
+		{
+			name:    "Empty placeholder",
+			tmpl:    "q={}",
+			wantErr: true,
+		},
+		{
+			name:    "Nested placeholder",
+			tmpl:    "q={{query}}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePostDataTemplate(tt.tmpl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePostDataTemplate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got.Placeholders()); diff != "" {
+				t.Errorf("Placeholders() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPostDataTemplate_Execute(t *testing.T) {
+	tmpl := MustParsePostDataTemplate("q={query}&page={page}&sort=desc")
+
+	tests := []struct {
+		name    string
+		values  map[string]string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "Values are encoded",
+			values: map[string]string{"query": "foo & bar=baz", "page": "2"},
+			want:   "q=foo+%26+bar%3Dbaz&page=2&sort=desc",
+		},
+		{
+			name:   "Extra values are ignored",
+			values: map[string]string{"query": "foo", "page": "1", "other": "bar"},
+			want:   "q=foo&page=1&sort=desc",
+		},
+		{
+			name:    "Missing value",
+			values:  map[string]string{"query": "foo"},
+			wantErr: ErrMissingValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tmpl.Execute(tt.values)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("Execute() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package flaresolverr
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// ErrUnsupportedByServer when the FlareSolverr server does not support a command or feature.
+var ErrUnsupportedByServer = errors.New("unsupported by the FlareSolverr server")
+
+// ServerProfile describes the quirks of a FlareSolverr implementation,
+// so the client degrades gracefully across forks.
+type ServerProfile struct {
+	Name string
+
+	// NoSessions when the server does not implement the sessions commands:
+	// they fail with ErrUnsupportedByServer, and requests are made without session.
+	NoSessions bool
+
+	// Errors maps error messages to the errors they mean,
+	// checked before the FlareSolverr error messages.
+	Errors []ProfileError
+
+	// UserAgent when the server accepts a userAgent field overriding the browser user agent,
+	// see WithUserAgent. Requests with a user agent fail with ErrUnsupportedByServer otherwise.
+	UserAgent bool
+
+	// PostLoadWait when the server accepts a waitInSeconds field, see WithPostLoadWait.
+	// Requests with a wait fail with ErrUnsupportedByServer otherwise.
+	PostLoadWait bool
+
+	// WaitForSelector when the server accepts a waitForSelector field, see WithWaitForSelector.
+	// Requests with a selector fail with ErrUnsupportedByServer otherwise.
+	WaitForSelector bool
+
+	// Base64Response when the server accepts a returnBase64 field, see WithBase64Response.
+	// Requests asking for a base64 body fail with ErrUnsupportedByServer otherwise.
+	Base64Response bool
+
+	// ExtraFields are added to every command, see WithExtraFields.
+	ExtraFields map[string]any
+}
+
+// ProfileError maps the error messages containing Match, case-insensitively, to Err.
+type ProfileError struct {
+	Match string
+	Err   error
+}
+
+var (
+	// ProfileFlareSolverr is the reference FlareSolverr implementation.
+	ProfileFlareSolverr = ServerProfile{Name: "FlareSolverr"}
+
+	// ProfileByparr is Byparr, a FlareSolverr compatible server without sessions.
+	ProfileByparr = ServerProfile{Name: "Byparr", NoSessions: true}
+
+	// ProfileFlareSolverrReloaded is flaresolverr-reloaded, which keeps the FlareSolverr API.
+	ProfileFlareSolverrReloaded = ServerProfile{Name: "flaresolverr-reloaded"}
+)
+
+// adapt adapts the command to the server, or fails with ErrUnsupportedByServer.
+// A nil profile is the reference FlareSolverr implementation.
+func (p *ServerProfile) adapt(cmd *flaresolverrCommand) error {
+	if cmd.UserAgent != "" && (p == nil || !p.UserAgent) {
+		return fmt.Errorf("%w: %s does not support custom user agents", ErrUnsupportedByServer, p.name())
+	}
+
+	if cmd.WaitInSeconds > 0 && (p == nil || !p.PostLoadWait) {
+		return fmt.Errorf("%w: %s does not support post-load waits", ErrUnsupportedByServer, p.name())
+	}
+
+	if cmd.WaitForSelector != "" && (p == nil || !p.WaitForSelector) {
+		return fmt.Errorf("%w: %s does not support waiting for a selector", ErrUnsupportedByServer, p.name())
+	}
+
+	if cmd.ReturnBase64 && (p == nil || !p.Base64Response) {
+		return fmt.Errorf("%w: %s does not support base64 responses", ErrUnsupportedByServer, p.name())
+	}
+
+	if p == nil || !p.NoSessions {
+		return nil
+	}
+
+	switch cmd.Cmd {
+	case CommandSessionscreate, CommandSessionslist, CommandSessionsdestroy:
+		return fmt.Errorf("%w: %s does not support sessions", ErrUnsupportedByServer, p.Name)
+	default:
+		cmd.Session = ""
+		return nil
+	}
+}
+
+func (p *ServerProfile) name() string {
+	if p == nil {
+		return ProfileFlareSolverr.Name
+	}
+
+	return p.Name
+}
+
+// extraFields returns the profile fields, overridden by the request ones.
+func (p *ServerProfile) extraFields(request map[string]any) map[string]any {
+	if p == nil || len(p.ExtraFields) == 0 {
+		return request
+	}
+
+	fields := maps.Clone(p.ExtraFields)
+	maps.Copy(fields, request)
+	return fields
+}
+
+// handleError maps an error response using the profile errors, then the FlareSolverr ones.
+func (p *ServerProfile) handleError(resp *Response) error {
+	if p != nil {
+		message := strings.ToLower(resp.Message)
+		for _, e := range p.Errors {
+			if strings.Contains(message, strings.ToLower(e.Match)) {
+				return fmt.Errorf("%w: %s", e.Err, resp.Message)
+			}
+		}
+	}
+
+	return handleError(resp)
+}
@@ -0,0 +1,137 @@
+package flaresolverr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_client_WithProfile(t *testing.T) {
+	errBanned := errors.New("banned")
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		_ = json.NewDecoder(r.Body).Decode(&got)
+
+		if got["url"] == "https://example.com/banned" {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(&Response{Status: "error", Message: "IP banned by target"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&Response{Status: "ok"})
+	}))
+	defer srv.Close()
+
+	profile := ProfileByparr
+	profile.Errors = []ProfileError{{Match: "ip banned", Err: errBanned}}
+	profile.ExtraFields = map[string]any{"disableMedia": true}
+	c := New(srv.URL, time.Second, srv.Client(), WithProfile(profile))
+	ctx := context.Background()
+
+	if _, err := c.CreateSession(ctx, uuid.New()); !errors.Is(err, ErrUnsupportedByServer) {
+		t.Errorf("CreateSession() error = %v, want %v", err, ErrUnsupportedByServer)
+	}
+
+	if _, err := c.GetWithOptions(ctx, "https://example.com", uuid.New(), WithExtraFields(map[string]any{"foo": "bar"})); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if _, ok := got["session"]; ok {
+		t.Errorf("Get() sent session %v, want none", got["session"])
+	}
+
+	if got["disableMedia"] != true || got["foo"] != "bar" {
+		t.Errorf("Get() sent %v, want the profile and request extra fields", got)
+	}
+
+	if _, err := c.Get(ctx, "https://example.com/banned", uuid.Nil); !errors.Is(err, errBanned) {
+		t.Errorf("Get() error = %v, want %v", err, errBanned)
+	}
+}
+
+func Test_client_WithUserAgent(t *testing.T) {
+	var got string
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		got = cmd.UserAgent
+		return http.StatusOK, &Response{Status: "ok"}
+	})
+
+	const userAgent = "Mozilla/5.0 (compatible; example/1.0)"
+	profile := ProfileFlareSolverr
+	profile.UserAgent = true
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+		want    string
+	}{
+		{name: "Default server", wantErr: ErrUnsupportedByServer},
+		{name: "Unsupported", opts: []Option{WithProfile(ProfileByparr)}, wantErr: ErrUnsupportedByServer},
+		{name: "Supported", opts: []Option{WithProfile(profile)}, want: userAgent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = ""
+			c := New(srv.baseURL, time.Second, srv.httpClient, tt.opts...)
+
+			_, err := c.GetWithOptions(context.Background(), "https://example.com", uuid.Nil, WithUserAgent(userAgent))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Get() sent user agent %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_client_WithPostLoadWait(t *testing.T) {
+	var got *flaresolverrCommand
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		got = cmd
+		return http.StatusOK, &Response{Status: "ok"}
+	})
+
+	profile := ProfileFlareSolverr
+	profile.PostLoadWait = true
+	profile.WaitForSelector = true
+	tests := []struct {
+		name         string
+		opts         []Option
+		requestOpts  []RequestOption
+		wantErr      error
+		wantSeconds  int
+		wantSelector string
+	}{
+		{name: "No wait", requestOpts: nil},
+		{name: "Unsupported wait", requestOpts: []RequestOption{WithPostLoadWait(time.Second)}, wantErr: ErrUnsupportedByServer},
+		{name: "Unsupported selector", requestOpts: []RequestOption{WithWaitForSelector("#content")}, wantErr: ErrUnsupportedByServer},
+		{name: "Wait rounded up", opts: []Option{WithProfile(profile)}, requestOpts: []RequestOption{WithPostLoadWait(1500 * time.Millisecond)}, wantSeconds: 2},
+		{name: "Selector", opts: []Option{WithProfile(profile)}, requestOpts: []RequestOption{WithWaitForSelector("#content")}, wantSelector: "#content"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			c := New(srv.baseURL, time.Second, srv.httpClient, tt.opts...)
+
+			_, err := c.GetWithOptions(context.Background(), "https://example.com", uuid.Nil, tt.requestOpts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.WaitInSeconds != tt.wantSeconds || got.WaitForSelector != tt.wantSelector {
+				t.Errorf("Get() sent waitInSeconds %d and waitForSelector %q, want %d and %q", got.WaitInSeconds, got.WaitForSelector, tt.wantSeconds, tt.wantSelector)
+			}
+		})
+	}
+}
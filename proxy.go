@@ -0,0 +1,65 @@
+package flaresolverr
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidProxy when a proxy is not a valid proxy URL.
+var ErrInvalidProxy = errors.New("invalid proxy")
+
+// NormalizeProxy checks proxy is a proxy URL and normalizes it, as done by the client
+// for the proxies of WithProxy and WithProxyRotation. The scheme is one of http, https,
+// socks4 and socks5, http when missing, e.g. "127.0.0.1:3128" becomes "http://127.0.0.1:3128".
+// The host and the port are required, the credentials are optional. Errors wrap ErrInvalidProxy.
+func NormalizeProxy(proxy string) (string, error) {
+	raw := strings.TrimSpace(proxy)
+	if raw == "" {
+		return "", fmt.Errorf("%w: empty", ErrInvalidProxy)
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		// the parse error quotes the URL, which may hold credentials
+		return "", fmt.Errorf("%w: malformed URL", ErrInvalidProxy)
+	}
+
+	// the errors quote the proxy without its password
+	redacted := u.Redacted()
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	case "socks4":
+		if _, ok := u.User.Password(); ok {
+			return "", fmt.Errorf("%w: %q: socks4 does not support passwords", ErrInvalidProxy, redacted)
+		}
+	default:
+		return "", fmt.Errorf("%w: %q: scheme must be http, https, socks4 or socks5", ErrInvalidProxy, redacted)
+	}
+
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("%w: %q: missing host", ErrInvalidProxy, redacted)
+	}
+
+	port, err := strconv.Atoi(u.Port())
+	if err != nil || port < 1 || port > 65535 {
+		return "", fmt.Errorf("%w: %q: missing or invalid port", ErrInvalidProxy, redacted)
+	}
+
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("%w: %q: unexpected path", ErrInvalidProxy, redacted)
+	}
+
+	u.Host = net.JoinHostPort(strings.ToLower(u.Hostname()), u.Port())
+	u.Path, u.RawPath = "", ""
+
+	return u.String(), nil
+}
@@ -0,0 +1,245 @@
+package flaresolverr
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNoHealthyProxy when every proxy of WithProxyRotation is quarantined.
+var ErrNoHealthyProxy = errors.New("no healthy proxy")
+
+// ProxyHealthPolicy decides when a proxy of WithProxyRotation is quarantined.
+// The zero value uses the defaults.
+type ProxyHealthPolicy struct {
+	// Window is the number of latest requests a proxy is scored on. Defaults to 20.
+	Window int
+
+	// MinRequests is the number of requests scored before a proxy can be quarantined. Defaults to 5.
+	MinRequests int
+
+	// MaxFailureRate is the rate of failed requests above which a proxy is quarantined. Defaults to 0.5.
+	MaxFailureRate float64
+
+	// MaxBlockRate is the rate of requests blocked by a captcha, an access denied or
+	// an unsolved challenge above which a proxy is quarantined. Defaults to 0.3.
+	MaxBlockRate float64
+
+	// Cooldown is how long a proxy is quarantined. Its score is reset afterwards. Defaults to 5m.
+	Cooldown time.Duration
+}
+
+func (p ProxyHealthPolicy) withDefaults() ProxyHealthPolicy {
+	if p.Window <= 0 {
+		p.Window = 20
+	}
+	if p.MinRequests <= 0 {
+		p.MinRequests = 5
+	}
+	if p.MaxFailureRate <= 0 {
+		p.MaxFailureRate = 0.5
+	}
+	if p.MaxBlockRate <= 0 {
+		p.MaxBlockRate = 0.3
+	}
+	if p.Cooldown <= 0 {
+		p.Cooldown = 5 * time.Minute
+	}
+
+	return p
+}
+
+// ProxyScore is the health of a proxy of WithProxyRotation, over the latest requests.
+type ProxyScore struct {
+	Proxy string
+
+	// Requests is the number of requests scored, out of which Failures failed.
+	// Blocks counts the failures caused by a captcha, an access denied or an unsolved challenge.
+	Requests int
+	Failures int
+	Blocks   int
+
+	// QuarantinedUntil is the end of the quarantine of the proxy, zero when healthy.
+	QuarantinedUntil time.Time
+}
+
+// FailureRate returns the rate of failed requests.
+func (s ProxyScore) FailureRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+
+	return float64(s.Failures) / float64(s.Requests)
+}
+
+// BlockRate returns the rate of blocked requests.
+func (s ProxyScore) BlockRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+
+	return float64(s.Blocks) / float64(s.Requests)
+}
+
+// proxyOutcome is the outcome of a request through a proxy.
+type proxyOutcome uint8
+
+const (
+	proxySucceeded proxyOutcome = iota
+	proxyFailed
+	proxyBlocked
+)
+
+// outcomeOf returns the outcome of a request which returned err.
+func outcomeOf(err error) proxyOutcome {
+	switch {
+	case err == nil, errors.Is(err, ErrTargetHTTPStatus): // the target failed, not the proxy
+		return proxySucceeded
+	case errors.Is(err, ErrCaptchaDetected), errors.Is(err, ErrCustomCaptchaDetected),
+		errors.Is(err, ErrAccessDenied), errors.Is(err, ErrChallengePageReturned),
+		errors.Is(err, ErrTargetRateLimited), errors.Is(err, ErrDDoSGuardDetected):
+		return proxyBlocked
+	default:
+		return proxyFailed
+	}
+}
+
+type proxyHealth struct {
+	proxy            string
+	outcomes         []proxyOutcome // ring of the latest outcomes
+	next             int
+	quarantinedUntil time.Time
+}
+
+func (h *proxyHealth) score() ProxyScore {
+	s := ProxyScore{Proxy: h.proxy, Requests: len(h.outcomes), QuarantinedUntil: h.quarantinedUntil}
+	for _, outcome := range h.outcomes {
+		switch outcome {
+		case proxyFailed:
+			s.Failures++
+		case proxyBlocked:
+			s.Failures++
+			s.Blocks++
+		}
+	}
+
+	return s
+}
+
+// proxyRotation rotates the requests over proxies, skipping the quarantined ones.
+type proxyRotation struct {
+	policy ProxyHealthPolicy
+	clock  Clock
+
+	// err is the error of an invalid proxy, returned by pick.
+	err error
+
+	mu      sync.Mutex
+	proxies []*proxyHealth
+	byProxy map[string]*proxyHealth
+	next    int
+}
+
+func newProxyRotation(proxies []string, policy ProxyHealthPolicy) *proxyRotation {
+	r := &proxyRotation{policy: policy.withDefaults(), clock: systemClock, byProxy: make(map[string]*proxyHealth, len(proxies))}
+	for _, proxy := range proxies {
+		proxy, err := NormalizeProxy(proxy)
+		if err != nil {
+			r.err = err
+			continue
+		}
+
+		if _, ok := r.byProxy[proxy]; ok {
+			continue
+		}
+
+		h := &proxyHealth{proxy: proxy}
+		r.proxies = append(r.proxies, h)
+		r.byProxy[proxy] = h
+	}
+
+	return r
+}
+
+// pick returns the next healthy proxy, in turn.
+// It fails with ErrInvalidProxy if one of the proxies is invalid.
+func (r *proxyRotation) pick() (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	for range r.proxies {
+		h := r.proxies[r.next%len(r.proxies)]
+		r.next++
+
+		if h.quarantinedUntil.IsZero() {
+			return h.proxy, nil
+		}
+
+		if !now.Before(h.quarantinedUntil) {
+			// the cooldown is over, start afresh
+			h.outcomes, h.next, h.quarantinedUntil = h.outcomes[:0], 0, time.Time{}
+			return h.proxy, nil
+		}
+	}
+
+	return "", ErrNoHealthyProxy
+}
+
+// observe scores the outcome of a request through proxy, quarantining it if unhealthy.
+// It returns the updated score, and false if proxy is not rotated.
+func (r *proxyRotation) observe(proxy string, err error) (ProxyScore, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, ok := r.byProxy[proxy]
+	if !ok {
+		return ProxyScore{}, false
+	}
+
+	if len(h.outcomes) < r.policy.Window {
+		h.outcomes = append(h.outcomes, outcomeOf(err))
+	} else {
+		h.outcomes[h.next] = outcomeOf(err)
+		h.next = (h.next + 1) % r.policy.Window
+	}
+
+	s := h.score()
+	if h.quarantinedUntil.IsZero() && s.Requests >= r.policy.MinRequests &&
+		(s.FailureRate() > r.policy.MaxFailureRate || s.BlockRate() > r.policy.MaxBlockRate) {
+		h.quarantinedUntil = r.clock.Now().Add(r.policy.Cooldown)
+		s.QuarantinedUntil = h.quarantinedUntil
+	}
+
+	return s, true
+}
+
+// scores returns the score of every proxy, in the rotation order.
+func (r *proxyRotation) scores() []ProxyScore {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	scores := make([]ProxyScore, 0, len(r.proxies))
+	for _, h := range r.proxies {
+		scores = append(scores, h.score())
+	}
+
+	return scores
+}
+
+// rotated reports whether the command can be sent through a proxy of the rotation:
+// the requests outside of a session and the session creations, without proxy.
+func rotated(cmd *flaresolverrCommand) bool {
+	switch cmd.Cmd {
+	case CommandRequestget, CommandRequestpost:
+		return cmd.Session == "" && cmd.Proxy == ""
+	case CommandSessionscreate:
+		return cmd.Proxy == ""
+	default:
+		return false
+	}
+}
@@ -0,0 +1,128 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_outcomeOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want proxyOutcome
+	}{
+		{err: nil, want: proxySucceeded},
+		{err: &HTTPStatusError{StatusCode: http.StatusNotFound}, want: proxySucceeded},
+		{err: fmt.Errorf("solving: %w", ErrCaptchaDetected), want: proxyBlocked},
+		{err: &ChallengePageError{Vendor: VendorCloudflare}, want: proxyBlocked},
+		{err: ErrRequestTimeout, want: proxyFailed},
+		{err: errors.New("net::ERR_PROXY_CONNECTION_FAILED"), want: proxyFailed},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.err), func(t *testing.T) {
+			if got := outcomeOf(tt.err); got != tt.want {
+				t.Errorf("outcomeOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_proxyRotation(t *testing.T) {
+	r := newProxyRotation([]string{"http://a:8080", "http://b:8080", "http://a:8080"}, ProxyHealthPolicy{Window: 4, MinRequests: 2, Cooldown: 50 * time.Millisecond})
+
+	var picked []string
+	for i := 0; i < 4; i++ {
+		proxy, err := r.pick()
+		if err != nil {
+			t.Fatalf("pick() error = %v", err)
+		}
+		picked = append(picked, proxy)
+	}
+	if diff := cmp.Diff([]string{"http://a:8080", "http://b:8080", "http://a:8080", "http://b:8080"}, picked); diff != "" {
+		t.Errorf("pick() mismatch (-want +got):\n%s", diff)
+	}
+
+	// a is blocked, b times out once out of two requests, at the failure rate limit
+	r.observe("http://a:8080", ErrAccessDenied)
+	r.observe("http://a:8080", nil)
+	r.observe("http://b:8080", ErrRequestTimeout)
+	r.observe("http://b:8080", nil)
+	if _, ok := r.observe("http://c:8080", nil); ok {
+		t.Errorf("observe() scored a proxy out of the rotation")
+	}
+
+	scores := r.scores()
+	if scores[0].QuarantinedUntil.IsZero() || scores[0].BlockRate() != 0.5 {
+		t.Errorf("scores()[0] = %+v, want a quarantined proxy", scores[0])
+	}
+	if !scores[1].QuarantinedUntil.IsZero() || scores[1].FailureRate() != 0.5 {
+		t.Errorf("scores()[1] = %+v, want a healthy proxy", scores[1])
+	}
+
+	for i := 0; i < 3; i++ {
+		if proxy, _ := r.pick(); proxy != "http://b:8080" {
+			t.Fatalf("pick() = %q during the quarantine of a, want b", proxy)
+		}
+	}
+
+	r.observe("http://b:8080", ErrRequestTimeout)
+	if _, err := r.pick(); !errors.Is(err, ErrNoHealthyProxy) {
+		t.Fatalf("pick() error = %v, want ErrNoHealthyProxy", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if proxy, err := r.pick(); err != nil || proxy != "http://a:8080" {
+		t.Fatalf("pick() = %q, %v after the cooldown, want a", proxy, err)
+	}
+	if s := r.scores()[0]; s.Requests != 0 || !s.QuarantinedUntil.IsZero() {
+		t.Errorf("scores()[0] = %+v after the cooldown, want a reset score", s)
+	}
+}
+
+func Test_client_WithProxyRotation(t *testing.T) {
+	var mu sync.Mutex
+	var proxies []string
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		proxies = append(proxies, cmd.Proxy)
+		mu.Unlock()
+
+		if cmd.Proxy == "http://bad:8080" {
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: Captcha detected but no automatic solver is configured."}
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Status: http.StatusOK}}
+	})
+
+	var scored []ProxyScore
+	hooks := Hooks{OnProxyScored: func(_ context.Context, score ProxyScore) {
+		mu.Lock()
+		defer mu.Unlock()
+		scored = append(scored, score)
+	}}
+	c := New(srv.baseURL, time.Second, srv.httpClient,
+		WithHooks(hooks),
+		WithProxyRotation([]string{"http://bad:8080", "http://good:8080"}, ProxyHealthPolicy{MinRequests: 1}),
+	)
+
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		_, _ = c.Get(ctx, "https://example.com", uuid.Nil)
+	}
+	_, _ = c.GetWithOptions(ctx, "https://example.com", uuid.Nil, WithProxy("http://explicit:8080"))
+
+	want := []string{"http://bad:8080", "http://good:8080", "http://good:8080", "http://good:8080", "http://explicit:8080"}
+	if diff := cmp.Diff(want, proxies); diff != "" {
+		t.Errorf("proxies mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(scored) != 4 || scored[0].Proxy != "http://bad:8080" || scored[0].QuarantinedUntil.IsZero() || scored[0].Blocks != 1 {
+		t.Errorf("OnProxyScored() got %+v, want 4 scores starting with bad quarantined", scored)
+	}
+}
@@ -0,0 +1,224 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+)
+
+// ErrQueueClosed when a job is enqueued after the queue has been closed.
+var ErrQueueClosed = errors.New("queue closed")
+
+// Job is a request solved by a Queue.
+type Job struct {
+	// ID identifies the job in its result, it is not used by the queue.
+	ID string
+
+	// URL is the requested URL.
+	URL string
+
+	// PostData makes a POST request when set,
+	// it must be an application/x-www-form-urlencoded string.
+	PostData string
+
+	// Options are applied to the request.
+	Options []RequestOption
+}
+
+// Result is the outcome of a Job.
+type Result struct {
+	Job      Job
+	Response *Response
+	Err      error
+}
+
+// QueueOption configures a Queue.
+type QueueOption func(*Queue)
+
+// WithQueueWorkers sets the number of jobs solved at the same time. Defaults to 1.
+func WithQueueWorkers(n int) QueueOption {
+	return func(q *Queue) {
+		if n > 0 {
+			q.workers = n
+		}
+	}
+}
+
+// WithQueueCapacity sets the number of jobs waiting for a worker before Enqueue blocks.
+// Defaults to the number of workers.
+func WithQueueCapacity(n int) QueueOption {
+	return func(q *Queue) {
+		q.capacity = n
+	}
+}
+
+// WithQueueSessionPool gives every worker its own FlareSolverr session,
+// created on its first job and destroyed when the queue is closed.
+// Jobs requesting a session with WithSession keep it.
+func WithQueueSessionPool() QueueOption {
+	return func(q *Queue) {
+		q.sessionPool = true
+	}
+}
+
+// WithQueueRateLimit limits the number of jobs started per second, across all workers.
+func WithQueueRateLimit(limit rate.Limit, burst int) QueueOption {
+	return func(q *Queue) {
+		q.limiter = rate.NewLimiter(limit, burst)
+	}
+}
+
+// WithQueueRetryPolicy retries the jobs failing because of a FlareSolverr timeout or a network error.
+// POST jobs are only retried with RetryPolicy.RetryPOST.
+func WithQueueRetryPolicy(policy RetryPolicy) QueueOption {
+	return func(q *Queue) {
+		q.retry = policy
+	}
+}
+
+// Queue solves jobs in the background with a pool of workers,
+// and reports their results to a callback.
+type Queue struct {
+	client   Client
+	onResult func(*Result)
+
+	workers     int
+	capacity    int
+	sessionPool bool
+	limiter     *rate.Limiter
+	retry       RetryPolicy
+
+	jobs   chan Job
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewQueue starts a queue solving jobs with c.
+// onResult is called with the result of every job, from the worker goroutines.
+func NewQueue(c Client, onResult func(*Result), opts ...QueueOption) *Queue {
+	q := &Queue{client: c, onResult: onResult, workers: 1, capacity: -1}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	if q.capacity < 0 {
+		q.capacity = q.workers
+	}
+
+	q.jobs = make(chan Job, q.capacity)
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+
+	return q
+}
+
+// Enqueue adds a job to the queue, waiting for room if the queue is full.
+func (q *Queue) Enqueue(ctx context.Context, job Job) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case q.jobs <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting jobs and waits for the queued ones to be solved.
+// When ctx is done first, the remaining jobs are canceled and Close returns the context error.
+// The sessions of the pool are destroyed in both cases.
+func (q *Queue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+
+	var session uuid.UUID
+	defer func() {
+		if session != uuid.Nil {
+			_ = q.client.DestroySession(context.Background(), session)
+		}
+	}()
+
+	for job := range q.jobs {
+		result := &Result{Job: job}
+		jobSession := newRequestOptions(q.ctx, job.Options).session
+		if jobSession == uuid.Nil && q.sessionPool {
+			if session == uuid.Nil {
+				id := uuid.New()
+				if _, result.Err = q.client.CreateSessionWithOptions(q.ctx, id, job.Options...); result.Err == nil {
+					session = id
+				}
+			}
+			jobSession = session
+		}
+
+		if result.Err == nil {
+			result.Response, result.Err = q.solve(job, jobSession)
+		}
+		q.onResult(result)
+	}
+}
+
+func (q *Queue) solve(job Job, session uuid.UUID) (*Response, error) {
+	if q.limiter != nil {
+		if err := q.limiter.Wait(q.ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	policy := q.retry.forCommand(CommandRequestget)
+	if job.PostData != "" {
+		policy = q.retry.forCommand(CommandRequestpost)
+	}
+
+	var response *Response
+	err := policy.retry(q.ctx, systemClock, func(int) error {
+		var err error
+		if job.PostData != "" {
+			response, err = q.client.PostWithOptions(q.ctx, job.URL, session, job.PostData, job.Options...)
+		} else {
+			response, err = q.client.GetWithOptions(q.ctx, job.URL, session, job.Options...)
+		}
+		return err
+	}, retryable)
+
+	return response, err
+}
@@ -0,0 +1,99 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	var mu sync.Mutex
+	created, destroyed := 0, 0
+	attempts := make(map[string]int)
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch cmd.Cmd {
+		case CommandSessionscreate:
+			created++
+			return http.StatusOK, &Response{Status: "ok", Session: cmd.Session}
+		case CommandSessionsdestroy:
+			destroyed++
+			return http.StatusOK, &Response{Status: "ok"}
+		}
+
+		if cmd.Session == "" {
+			t.Errorf("job %s sent without a pooled session", cmd.URL)
+		}
+
+		// every URL times out once
+		attempts[cmd.URL]++
+		if attempts[cmd.URL] == 1 {
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: maximum timeout reached"}
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Response: cmd.PostData}}
+	})
+
+	var results sync.Map
+	q := NewQueue(c, func(r *Result) { results.Store(r.Job.ID, r) },
+		WithQueueWorkers(2),
+		WithQueueSessionPool(),
+		WithQueueRetryPolicy(RetryPolicy{MaxAttempts: 2, RetryPOST: true}),
+	)
+
+	jobs := []Job{
+		{ID: "1", URL: "https://example.com/1"},
+		{ID: "2", URL: "https://example.com/2", PostData: "foo=bar"},
+		{ID: "3", URL: "https://example.com/3"},
+	}
+	for _, job := range jobs {
+		if err := q.Enqueue(context.Background(), job); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	for _, job := range jobs {
+		v, ok := results.Load(job.ID)
+		if !ok {
+			t.Errorf("job %s has no result", job.ID)
+			continue
+		}
+
+		r := v.(*Result)
+		if r.Err != nil || r.Response.Solution.URL != job.URL || r.Response.Solution.Response != job.PostData {
+			t.Errorf("job %s result = %v, %v", job.ID, r.Response, r.Err)
+		}
+	}
+
+	if created == 0 || created > 2 || destroyed != created {
+		t.Errorf("sessions created = %d, destroyed = %d, want at most one per worker, all destroyed", created, destroyed)
+	}
+
+	if err := q.Enqueue(context.Background(), Job{URL: "https://example.com"}); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Enqueue() after Close() error = %v, want %v", err, ErrQueueClosed)
+	}
+}
+
+func TestQueue_CloseTimeout(t *testing.T) {
+	c := New("http://127.0.0.1:1/v1", time.Second, nil, WithRetryPolicy(RetryPolicy{MaxAttempts: 100, InitialBackoff: time.Second}))
+
+	q := NewQueue(c, func(*Result) {})
+	if err := q.Enqueue(context.Background(), Job{URL: "https://example.com"}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := q.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
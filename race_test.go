@@ -0,0 +1,155 @@
+package flaresolverr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Test_client_concurrent exercises a client shared between goroutines, with its stateful
+// subsystems enabled, so that the race detector catches unsynchronized accesses:
+//
+//	go test -race -run Test_client_concurrent
+func Test_client_concurrent(t *testing.T) {
+	var mu sync.Mutex
+	sessions := make(map[string]bool)
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch cmd.Cmd {
+		case CommandSessionscreate:
+			sessions[cmd.Session] = true
+			return http.StatusOK, &Response{Status: "ok", Session: cmd.Session, Version: "3.3.21"}
+		case CommandSessionsdestroy:
+			if !sessions[cmd.Session] {
+				return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: The session doesn't exist."}
+			}
+			delete(sessions, cmd.Session)
+			return http.StatusOK, &Response{Status: "ok", Version: "3.3.21"}
+		case CommandSessionslist:
+			ids := make([]uuid.UUID, 0, len(sessions))
+			for id := range sessions {
+				ids = append(ids, uuid.MustParse(id))
+			}
+			return http.StatusOK, &Response{Status: "ok", Sessions: ids, Version: "3.3.21"}
+		default:
+			if cmd.Session != "" && !sessions[cmd.Session] {
+				return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: The session doesn't exist."}
+			}
+			return http.StatusOK, &Response{Status: "ok", Message: "Challenge solved!", Version: "3.3.21", Solution: &ResponseSolution{
+				URL:       cmd.URL,
+				Status:    http.StatusOK,
+				UserAgent: "Mozilla/5.0",
+				Cookies:   []Cookie{{Name: "cf_clearance", Value: "solved", Expires: float64(time.Now().Add(time.Hour).Unix())}},
+			}}
+		}
+	})
+
+	c := New(srv.baseURL, time.Second, srv.httpClient,
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2}),
+		WithSingleflight(),
+		WithResponseCache(time.Minute, 8),
+		WithSessionPerDomain(time.Minute),
+		WithMaxInFlight(4, -1),
+		WithAdaptiveTimeout(),
+		WithRestartDetection(nil),
+		WithErrorSuppression(time.Minute),
+		WithAuditLog(io.Discard),
+		WithSessionKeepAlive(5*time.Millisecond, ""),
+		WithHooks(Hooks{OnResponse: func(context.Context, HookEvent, *Response) {}}),
+	)
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 10; j++ {
+				u := fmt.Sprintf("https://site%d.example.com/page/%d", i%3, j%4)
+				if _, err := c.Get(ctx, u, uuid.Nil); err != nil {
+					t.Errorf("Get() error = %v", err)
+				}
+
+				session := uuid.New()
+				if _, err := c.CreateSessionWithOptions(ctx, session, WithLabel("worker", fmt.Sprint(i))); err != nil {
+					t.Errorf("CreateSession() error = %v", err)
+					continue
+				}
+
+				if _, err := c.Post(ctx, u, session, "foo=bar"); err != nil {
+					t.Errorf("Post() error = %v", err)
+				}
+
+				target, _ := url.Parse(u)
+				_ = c.Session(session).Jar().Cookies(target)
+				_ = c.FindSessionByLabel("worker", fmt.Sprint(i))
+				if _, err := c.ListSessionInfo(ctx); err != nil {
+					t.Errorf("ListSessionInfo() error = %v", err)
+				}
+
+				if err := c.DestroySession(ctx, session); err != nil {
+					t.Errorf("DestroySession() error = %v", err)
+				}
+			}
+
+			_, errs := c.GetBatch(ctx, []string{"https://example.com/1", "https://example.com/2"}, WithBatchConcurrency(2))
+			for _, err := range errs {
+				if err != nil {
+					t.Errorf("GetBatch() error = %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestHybridClient_concurrent(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("cf_clearance"); err != nil {
+			w.Header().Set("cf-mitigated", "challenge")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("protected"))
+	}))
+	defer target.Close()
+
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{
+			URL:     cmd.URL,
+			Cookies: []Cookie{{Name: "cf_clearance", Value: "solved", Expires: float64(time.Now().Add(time.Hour).Unix())}},
+		}}
+	})
+	hybrid := NewHybridClient(c, target.Client())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 10; j++ {
+				resp, err := hybrid.Get(target.URL)
+				if err != nil {
+					t.Errorf("Get() error = %v", err)
+					continue
+				}
+				_, _ = io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,141 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrTargetRateLimited when the target website rate limits the requests,
+// with a 429 status or a Cloudflare rate limiting page. The error is a *RateLimitError.
+var ErrTargetRateLimited = errors.New("rate limited by the target")
+
+// defaultRateLimitWait is waited before retrying a rate limited request without Retry-After.
+const defaultRateLimitWait = 10 * time.Second
+
+// rateLimitMarkers are found in the Cloudflare rate limiting pages, error 1015.
+var rateLimitMarkers = []string{
+	"you are being rate limited",
+	"error code: 1015",
+	"<span class=\"cf-error-code\">1015</span>",
+}
+
+// RateLimitError is returned when the target website rate limits the requests.
+type RateLimitError struct {
+	// RetryAfter is how long the target asks to wait, parsed from its Retry-After header.
+	// Zero when unknown.
+	RetryAfter time.Duration
+
+	// Response is the response holding the rate limiting page.
+	Response *Response
+}
+
+// Error returns a message such as "rate limited by the target: HTTP 429, retry after 30s".
+func (e *RateLimitError) Error() string {
+	msg := ErrTargetRateLimited.Error()
+	if e.Response != nil && e.Response.Solution != nil {
+		msg += fmt.Sprintf(": HTTP %d", e.Response.Solution.Status)
+	}
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(", retry after %s", e.RetryAfter)
+	}
+
+	return msg
+}
+
+// Unwrap returns ErrTargetRateLimited.
+func (e *RateLimitError) Unwrap() error {
+	return ErrTargetRateLimited
+}
+
+// IsRateLimited reports whether the target rate limited the request,
+// with a 429 status or a Cloudflare rate limiting page.
+func (s *ResponseSolution) IsRateLimited() bool {
+	if s.Status == http.StatusTooManyRequests {
+		return true
+	}
+
+	if s.Status < http.StatusBadRequest {
+		return false
+	}
+
+	body := strings.ToLower(s.Response[:min(len(s.Response), challengeSniffLen)])
+	for _, marker := range rateLimitMarkers {
+		if strings.Contains(body, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RetryAfter returns the delay asked by the Retry-After header of the target, or zero.
+// An HTTP date is relative to the clock of the client, see WithClock.
+func (s *ResponseSolution) RetryAfter() time.Duration {
+	clock := s.clock
+	if clock == nil {
+		clock = systemClock
+	}
+
+	return parseRetryAfter(s.Headers.RetryAfter, clock.Now())
+}
+
+// parseRetryAfter parses a Retry-After header, in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+
+	return 0
+}
+
+// rateLimitError returns a *RateLimitError if the target rate limited the request.
+func rateLimitError(response *Response, now time.Time) error {
+	if response.Solution == nil || !response.Solution.IsRateLimited() {
+		return nil
+	}
+
+	return &RateLimitError{RetryAfter: parseRetryAfter(response.Solution.Headers.RetryAfter, now), Response: response}
+}
+
+// waitRateLimit waits before retrying a rate limited command, as long as the total
+// waited stays within the budget of WithRateLimitRetry. It reports whether to retry.
+func (c *client) waitRateLimit(ctx context.Context, err error, waited *time.Duration) bool {
+	var rle *RateLimitError
+	if c.rateLimitBudget <= 0 || !errors.As(err, &rle) {
+		return false
+	}
+
+	wait := rle.RetryAfter
+	if wait <= 0 {
+		wait = defaultRateLimitWait
+	}
+
+	if *waited+wait > c.rateLimitBudget {
+		return false
+	}
+	*waited += wait
+
+	timer := c.clock.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C():
+		return true
+	}
+}
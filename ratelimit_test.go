@@ -0,0 +1,119 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "empty", value: "", want: 0},
+		{name: "seconds", value: "30", want: 30 * time.Second},
+		{name: "negative seconds", value: "-5", want: 0},
+		{name: "http date", value: "Mon, 01 Jan 2024 12:01:00 GMT", want: time.Minute},
+		{name: "past http date", value: "Mon, 01 Jan 2024 11:00:00 GMT", want: 0},
+		{name: "invalid", value: "soon", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseSolution_IsRateLimited(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		want     bool
+	}{
+		{name: "ok", status: http.StatusOK, response: "<html>you are being rate limited</html>", want: false},
+		{name: "429", status: http.StatusTooManyRequests, response: "<html></html>", want: true},
+		{name: "cloudflare 1015", status: http.StatusForbidden, response: "<html><title>Access denied | You are being rate limited</title></html>", want: true},
+		{name: "error code", status: http.StatusForbidden, response: "error code: 1015", want: true},
+		{name: "forbidden", status: http.StatusForbidden, response: "<html>Access denied</html>", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ResponseSolution{Status: tt.status, Response: tt.response}
+			if got := s.IsRateLimited(); got != tt.want {
+				t.Errorf("IsRateLimited() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_client_rateLimited(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		solution := &ResponseSolution{URL: cmd.URL, Status: http.StatusTooManyRequests}
+		solution.Headers.RetryAfter = "1"
+		if calls.Add(1) > 1 {
+			solution = &ResponseSolution{URL: cmd.URL, Status: http.StatusOK}
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: solution}
+	})
+
+	c := New(srv.baseURL, time.Second, srv.httpClient)
+	_, err := c.Get(context.Background(), "https://example.com", uuid.Nil)
+	if !errors.Is(err, ErrTargetRateLimited) {
+		t.Fatalf("Get() error = %v, want ErrTargetRateLimited", err)
+	}
+
+	var rle *RateLimitError
+	if !errors.As(err, &rle) || rle.RetryAfter != time.Second || rle.Response.Solution.Status != http.StatusTooManyRequests {
+		t.Errorf("Get() error = %#v, want a *RateLimitError retrying after 1s", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("got %d calls, want 1 without WithRateLimitRetry", got)
+	}
+}
+
+func Test_client_WithRateLimitRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		budget    time.Duration
+		wantErr   bool
+		wantCalls int32
+	}{
+		{name: "within budget", budget: 2 * time.Second, wantErr: false, wantCalls: 2},
+		{name: "over budget", budget: 500 * time.Millisecond, wantErr: true, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+				if calls.Add(1) > 1 {
+					return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Status: http.StatusOK}}
+				}
+				solution := &ResponseSolution{URL: cmd.URL, Status: http.StatusTooManyRequests}
+				solution.Headers.RetryAfter = "1"
+				return http.StatusOK, &Response{Status: "ok", Solution: solution}
+			})
+
+			c := New(srv.baseURL, time.Second, srv.httpClient, WithRateLimitRetry(tt.budget))
+			_, err := c.Get(context.Background(), "https://example.com", uuid.Nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// recreatable reports whether the command runs within a session which can be recreated.
+func recreatable(cmd *flaresolverrCommand) bool {
+	return (cmd.Cmd == CommandRequestget || cmd.Cmd == CommandRequestpost) && cmd.Session != ""
+}
+
+// runRecreatingSession runs the command, recreating its session with the same ID
+// and retrying once if it does not exist on the server anymore.
+func (c *client) runRecreatingSession(ctx context.Context, cmd *flaresolverrCommand, o *requestOptions) (*Response, error) {
+	response, err := c.exec(ctx, cmd, o)
+	if !errors.Is(err, ErrSessionNotFound) {
+		return response, err
+	}
+
+	c.log(ctx, slog.LevelInfo, "flaresolverr session lost, recreating it", slog.String("session", cmd.Session))
+	create, createOptions := c.sessions.recreation(cmd, o)
+	if _, createErr := c.exec(ctx, create, createOptions); createErr != nil {
+		return nil, err
+	}
+
+	return c.exec(ctx, cmd, o)
+}
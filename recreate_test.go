@@ -0,0 +1,140 @@
+package flaresolverr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_client_WithSessionRecreate(t *testing.T) {
+	var mu sync.Mutex
+	sessions := make(map[string]bool)
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch cmd.Cmd {
+		case CommandSessionscreate:
+			sessions[cmd.Session] = true
+			return http.StatusOK, &Response{Status: "ok", Session: cmd.Session}
+		default:
+			if !sessions[cmd.Session] {
+				return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: This session does not exist."}
+			}
+			return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}}
+		}
+	})
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{name: "Session lost", wantErr: ErrSessionNotFound},
+		{name: "Session recreated", opts: []Option{WithSessionRecreate()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(srv.baseURL, time.Second, srv.httpClient, tt.opts...)
+			session := uuid.New()
+
+			_, err := c.Get(context.Background(), "https://example.com", session)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if sessions[session.String()] != (tt.wantErr == nil) {
+				t.Errorf("session recreated = %v, want %v", sessions[session.String()], tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func Test_client_recreatedSessionCreation(t *testing.T) {
+	const challenge = `<html><head><title>Just a moment...</title></head></html>`
+
+	tests := []struct {
+		name string
+		opts []Option
+		// lost answers the first request as if the session was lost, else with a challenge page
+		lost bool
+	}{
+		{name: "Session recreated", opts: []Option{WithSessionRecreate()}, lost: true},
+		{name: "Challenge retried", opts: []Option{WithChallengeRetry(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var creates []map[string]any
+			requests := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				var cmd map[string]any
+				_ = json.NewDecoder(r.Body).Decode(&cmd)
+				switch cmd["cmd"] {
+				case CommandSessionscreate.String():
+					delete(cmd, "maxTimeout")
+					creates = append(creates, cmd)
+				case CommandRequestget.String():
+					requests++
+					if requests == 1 && tt.lost {
+						w.WriteHeader(http.StatusInternalServerError)
+						_ = json.NewEncoder(w).Encode(&Response{Status: "error", Message: "Error: This session does not exist."})
+						return
+					}
+					body := "<html><head><title>Home</title></head></html>"
+					if requests == 1 {
+						body = challenge
+					}
+					_ = json.NewEncoder(w).Encode(&Response{Status: "ok", Solution: &ResponseSolution{URL: "https://example.com", Status: http.StatusOK, Response: body}})
+					return
+				}
+				_ = json.NewEncoder(w).Encode(&Response{Status: "ok"})
+			}))
+			defer srv.Close()
+
+			c := New(srv.URL, time.Second, srv.Client(), tt.opts...).(*client)
+			ctx := context.Background()
+			session := uuid.New()
+			cookies := []*http.Cookie{{Name: "cf_clearance", Value: "abc", Domain: "example.com"}}
+			if _, err := c.CreateSessionWithOptions(ctx, session,
+				WithProxy("http://proxy:8080"),
+				WithCookies(cookies),
+				WithExtraFields(map[string]any{"tabs": "1"}),
+				WithLabel("site", "example.com"),
+			); err != nil {
+				t.Fatalf("CreateSession() error = %v", err)
+			}
+			createdAt := c.Session(session).CreatedAt()
+
+			if _, err := c.Get(ctx, "https://example.com", session); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(creates) != 2 {
+				t.Fatalf("sessions created %d times, want 2", len(creates))
+			}
+			if diff := cmp.Diff(creates[0], creates[1]); diff != "" {
+				t.Errorf("recreated session mismatch (-created +recreated):\n%s", diff)
+			}
+
+			if s := c.FindSessionByLabel("site", "example.com"); s == nil || !s.CreatedAt().Equal(createdAt) {
+				t.Errorf("FindSessionByLabel() = %v, want the session created at %v", s, createdAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package flaresolverr
+
+import (
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces the sensitive data removed from logs and errors.
+const redacted = "[redacted]"
+
+// minRedactedValue is the length from which the post data values are redacted on their own,
+// shorter values such as "1" would redact unrelated parts of the messages.
+const minRedactedValue = 4
+
+// cookieValuePattern matches the JSON cookie values, including one cut by the truncation of a body.
+var cookieValuePattern = regexp.MustCompile(`("value"\s*:\s*)"(?:[^"\\]|\\.)*(?:"|$)`)
+
+// RedactionPolicy lists the sensitive data allowed in the client logs and errors.
+// The zero value, used by default, redacts the post data and the cookie values,
+// which may hold credentials.
+type RedactionPolicy struct {
+	// PostData keeps the post data in the debug logs and in the error messages.
+	PostData bool
+
+	// CookieValues keeps the cookie values in the debug logs
+	// and in the FlareSolverr response bodies kept in errors.
+	CookieValues bool
+}
+
+// message removes the post data, and its values, from an error message.
+func (p RedactionPolicy) message(msg, postData string) string {
+	if p.PostData || postData == "" {
+		return msg
+	}
+
+	msg = strings.ReplaceAll(msg, postData, redacted)
+	values, _ := url.ParseQuery(postData)
+	for _, vs := range values {
+		for _, v := range vs {
+			if len(v) >= minRedactedValue {
+				msg = strings.ReplaceAll(msg, v, redacted)
+			}
+		}
+	}
+
+	return msg
+}
+
+// body removes the cookie values from a FlareSolverr response body.
+func (p RedactionPolicy) body(body string) string {
+	if p.CookieValues {
+		return body
+	}
+
+	return cookieValuePattern.ReplaceAllString(body, `${1}"`+redacted+`"`)
+}
+
+// attrs returns the sensitive command fields allowed in the debug logs.
+func (p RedactionPolicy) attrs(cmd *flaresolverrCommand) []slog.Attr {
+	var attrs []slog.Attr
+	if p.PostData && cmd.PostData != "" {
+		attrs = append(attrs, slog.String("post_data", cmd.PostData))
+	}
+
+	if p.CookieValues && len(cmd.Cookies) > 0 {
+		cookies := make([]string, 0, len(cmd.Cookies))
+		for _, cookie := range cmd.Cookies {
+			cookies = append(cookies, cookie.Name+"="+cookie.Value)
+		}
+		attrs = append(attrs, slog.String("cookies", strings.Join(cookies, "; ")))
+	}
+
+	return attrs
+}
+
+// commandError returns the error of a failed command, redacted following the client policy.
+func (c *client) commandError(cmd *flaresolverrCommand, o *requestOptions, attempt, maxAttempts int, err error) *Error {
+	e := newError(cmd, o, attempt, maxAttempts, err)
+	e.Body = c.redaction.message(c.redaction.body(e.Body), cmd.PostData)
+	if postData := cmd.PostData; !c.redaction.PostData && postData != "" {
+		e.redact = func(msg string) string { return c.redaction.message(msg, postData) }
+	}
+
+	return e
+}
@@ -0,0 +1,92 @@
+package flaresolverr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_client_WithRedaction(t *testing.T) {
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		switch cmd.Cmd {
+		case CommandSessionscreate:
+			// an error response echoing the cookies
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: boom", Solution: &ResponseSolution{Cookies: []Cookie{{Name: "auth", Value: cmd.Cookies[0].Value}}}}
+		default:
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: invalid postData " + cmd.PostData}
+		}
+	})
+
+	tests := []struct {
+		name   string
+		policy RedactionPolicy
+		want   bool
+	}{
+		{name: "Redacted by default", want: false},
+		{name: "Kept", policy: RedactionPolicy{PostData: true, CookieValues: true}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			c := New(srv.baseURL, time.Second, srv.httpClient, WithLogger(logger), WithRedaction(tt.policy))
+			ctx := context.Background()
+
+			_, postErr := c.Post(ctx, "https://example.com/login", uuid.Nil, "user=foo&password=hunter2")
+			_, cookieErr := c.CreateSessionWithOptions(ctx, uuid.New(), WithCookies([]*http.Cookie{{Name: "auth", Value: "s3cr3t-token"}}))
+
+			var e *Error
+			if !errors.As(postErr, &e) || !errors.Is(postErr, ErrUnexpectedError) {
+				t.Fatalf("Post() error = %v, want an *Error wrapping %v", postErr, ErrUnexpectedError)
+			}
+
+			checks := []struct {
+				where, text, secret string
+			}{
+				{where: "Post() error", text: postErr.Error(), secret: "hunter2"},
+				{where: "Post() error body", text: e.Body, secret: "hunter2"},
+				{where: "logs", text: logs.String(), secret: "hunter2"},
+				{where: "logs", text: logs.String(), secret: "s3cr3t-token"},
+			}
+			if errors.As(cookieErr, &e) {
+				checks = append(checks, struct{ where, text, secret string }{where: "CreateSession() error body", text: e.Body, secret: "s3cr3t-token"})
+			} else {
+				t.Errorf("CreateSession() error = %v, want an *Error", cookieErr)
+			}
+
+			for _, check := range checks {
+				if got := strings.Contains(check.text, check.secret); got != tt.want {
+					t.Errorf("%s contains %q = %v, want %v:\n%s", check.where, check.secret, got, tt.want, check.text)
+				}
+			}
+		})
+	}
+}
+
+func TestRedactionPolicy_body(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{
+			body: `{"cookies":[{"name":"cf_clearance","value":"abc\"def","domain":".example.com"}]}`,
+			want: `{"cookies":[{"name":"cf_clearance","value":"[redacted]","domain":".example.com"}]}`,
+		},
+		{
+			body: `{"cookies":[{"name":"cf_clearance","value": "truncated`,
+			want: `{"cookies":[{"name":"cf_clearance","value": "[redacted]"`,
+		},
+	}
+	for _, tt := range tests {
+		if got := (RedactionPolicy{}).body(tt.body); got != tt.want {
+			t.Errorf("body() = %s, want %s", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package flaresolverr
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// scriptRedirectPattern matches the JavaScript redirects of interstitial pages, a statement
+// assigning a string to the location, e.g. window.location.href = "/next", see scriptRedirect.
+var scriptRedirectPattern = regexp.MustCompile(`^(?:window\.)?location(?:\.href)?\s*=\s*(?:"([^"]+)"|'([^']+)')$`)
+
+// follow runs cmd, then follows the redirects of the solution, see WithFollowRedirects.
+func (c *client) follow(ctx context.Context, cmd *flaresolverrCommand, o *requestOptions) (*Response, error) {
+	response, err := c.do(ctx, cmd, o)
+	for hop := 0; err == nil && hop < o.maxRedirects; hop++ {
+		target := redirectTarget(response.Solution)
+		if target == "" {
+			break
+		}
+
+		c.log(ctx, slog.LevelDebug, "following redirect of the solved page", slog.String("from", response.Solution.URL), slog.String("to", target), slog.Int("hop", hop+1))
+		next := &flaresolverrCommand{
+			Cmd:              CommandRequestget,
+			URL:              target,
+			Session:          cmd.Session,
+			Proxy:            cmd.Proxy,
+			UserAgent:        cmd.UserAgent,
+			WaitInSeconds:    cmd.WaitInSeconds,
+			WaitForSelector:  cmd.WaitForSelector,
+			ReturnScreenshot: cmd.ReturnScreenshot,
+			ReturnBase64:     cmd.ReturnBase64,
+		}
+		if next.Session == "" {
+			next.Cookies = response.Solution.seleniumCookies()
+		}
+
+		response, err = c.do(ctx, next, o)
+	}
+
+	return response, err
+}
+
+// redirectTarget returns the absolute URL the solved page redirects to with a meta refresh
+// or a JavaScript redirect, or an empty string.
+func redirectTarget(s *ResponseSolution) string {
+	if s == nil {
+		return ""
+	}
+
+	base, err := url.Parse(s.URL)
+	if err != nil {
+		return ""
+	}
+
+	doc, err := s.Document()
+	if err != nil {
+		return ""
+	}
+
+	var targets []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch {
+			case n.DataAtom == atom.Meta && strings.EqualFold(attr(n, "http-equiv"), "refresh"):
+				if _, ref, ok := parseRefresh(attr(n, "content")); ok && ref != "" {
+					targets = append(targets, ref)
+				}
+			case n.DataAtom == atom.Script && !hasAttr(n, "src"):
+				if target := scriptRedirect(text(n)); target != "" {
+					targets = append(targets, target)
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	for _, target := range targets {
+		u, err := base.Parse(strings.TrimSpace(target))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+
+		u.Fragment, u.RawFragment = "", ""
+		if u.String() == s.URL {
+			continue
+		}
+
+		return u.String()
+	}
+
+	return ""
+}
+
+// scriptRedirect returns the target of the first top-level statement of script matching
+// scriptRedirectPattern, or an empty string. The assignments within blocks, calls or
+// conditions are not redirects the page always makes, they are ignored.
+func scriptRedirect(script string) string {
+	for _, statement := range topLevelStatements(script) {
+		if m := scriptRedirectPattern.FindStringSubmatch(statement); m != nil {
+			return m[1] + m[2]
+		}
+	}
+
+	return ""
+}
+
+// topLevelStatements splits script into its top-level statements, at the semicolons and
+// line breaks outside of brackets, strings and comments. It is not a JavaScript parser:
+// the statements holding brackets, such as conditions and calls, are returned whole.
+func topLevelStatements(script string) []string {
+	var statements []string
+	var depth, start int
+	flush := func(end int) {
+		if statement := strings.TrimSpace(script[start:end]); statement != "" {
+			statements = append(statements, statement)
+		}
+		start = end + 1
+	}
+
+	for i := 0; i < len(script); i++ {
+		switch c := script[i]; {
+		case c == '"' || c == '\'' || c == '`':
+			// skip the string, up to its unescaped closing quote
+			for i++; i < len(script) && script[i] != c; i++ {
+				if script[i] == '\\' {
+					i++
+				}
+			}
+		case strings.HasPrefix(script[i:], "//"):
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				end = len(script) - i
+			}
+			script = script[:i] + strings.Repeat(" ", end) + script[i+end:]
+			i += end - 1
+		case strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				end = len(script) - i - 2
+			} else {
+				end += 2
+			}
+			script = script[:i] + strings.Repeat(" ", end+2) + script[i+end+2:]
+			i += end + 1
+		case c == '{' || c == '(' || c == '[':
+			depth++
+		case c == '}' || c == ')' || c == ']':
+			if depth > 0 {
+				depth--
+			}
+			if depth == 0 && c == '}' {
+				flush(i)
+			}
+		case depth == 0 && (c == ';' || c == '\n'):
+			flush(i)
+		}
+	}
+	if start < len(script) {
+		flush(len(script))
+	}
+
+	return statements
+}
@@ -0,0 +1,108 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_redirectTarget(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "none", body: `<html><body>hello</body></html>`, want: ""},
+		{name: "meta refresh", body: `<html><head><meta http-equiv="Refresh" content="0; URL='/next?a=1'"></head></html>`, want: "https://example.com/next?a=1"},
+		{name: "meta refresh without url prefix", body: `<meta http-equiv="refresh" content="3;https://other.example.com/">`, want: "https://other.example.com/"},
+		{name: "self refresh", body: `<meta http-equiv="refresh" content="30">`, want: ""},
+		{name: "refresh to same page", body: `<meta http-equiv="refresh" content="0; url=/start#top">`, want: ""},
+		{name: "location href", body: `<script>window.location.href = "/landing";</script>`, want: "https://example.com/landing"},
+		{name: "location", body: "<script>// leave\nvar delay = 0;\nlocation = '/home'</script>", want: "https://example.com/home"},
+		{name: "after a block", body: `<script>function go() { location = "/a" }; window.location = "/b";</script>`, want: "https://example.com/b"},
+		{name: "javascript scheme", body: `<script>location = "javascript:void(0)"</script>`, want: ""},
+		{name: "in a function", body: `<script>setTimeout(function () { location.href = '/home' }, 100)</script>`, want: ""},
+		{name: "in a condition", body: `<script>if (!document.cookie) location.href = "/login"</script>`, want: ""},
+		{name: "in a string", body: `<script>var help = "set location = '/x'; to leave";</script>`, want: ""},
+		{name: "in a comment", body: `<script>/* location = "/x"; */ var a = 1</script>`, want: ""},
+		{name: "location replace", body: `<script>location.replace('/home')</script>`, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ResponseSolution{URL: "https://example.com/start", Response: tt.body}
+			if got := redirectTarget(s); got != tt.want {
+				t.Errorf("redirectTarget() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_client_WithFollowRedirects(t *testing.T) {
+	pages := map[string]string{
+		"https://example.com/":    `<meta http-equiv="refresh" content="0; url=/one">`,
+		"https://example.com/one": `<script>location.href = "/two"</script>`,
+		"https://example.com/two": `<html>content</html>`,
+	}
+
+	var mu sync.Mutex
+	var got []*flaresolverrCommand
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		got = append(got, cmd)
+		mu.Unlock()
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{
+			URL:      cmd.URL,
+			Status:   http.StatusOK,
+			Response: pages[cmd.URL],
+			Cookies:  []Cookie{{Name: "cf_clearance", Value: "token", Domain: ".example.com", Path: "/"}},
+		}}
+	})
+
+	tests := []struct {
+		name    string
+		session uuid.UUID
+		hops    int
+		wantURL string
+		want    []string
+	}{
+		{name: "disabled", hops: 0, wantURL: "https://example.com/", want: []string{"https://example.com/"}},
+		{name: "hop limit", hops: 1, wantURL: "https://example.com/one", want: []string{"https://example.com/", "https://example.com/one"}},
+		{name: "all hops", hops: 5, wantURL: "https://example.com/two", want: []string{"https://example.com/", "https://example.com/one", "https://example.com/two"}},
+		{name: "session", session: uuid.MustParse("d8b6a2c4-5a1e-4b7a-9b2e-1c3d4e5f6a7b"), hops: 5, wantURL: "https://example.com/two", want: []string{"https://example.com/", "https://example.com/one", "https://example.com/two"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			c := New(srv.baseURL, time.Second, srv.httpClient)
+			resp, err := c.GetWithOptions(context.Background(), "https://example.com/", tt.session, WithFollowRedirects(tt.hops))
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if resp.Solution.URL != tt.wantURL {
+				t.Errorf("Get() solution URL = %q, want %q", resp.Solution.URL, tt.wantURL)
+			}
+
+			var urls []string
+			for i, cmd := range got {
+				urls = append(urls, cmd.URL)
+				if i == 0 {
+					continue
+				}
+				if cmd.Cmd != CommandRequestget || cmd.Session != handleSession(tt.session) {
+					t.Errorf("follow-up command = %s in session %q, want a GET in the same session", cmd.Cmd, cmd.Session)
+				}
+				if wantCookies := tt.session == uuid.Nil; (len(cmd.Cookies) > 0) != wantCookies {
+					t.Errorf("follow-up command cookies = %v, want cookies %v", cmd.Cookies, wantCookies)
+				}
+			}
+			if diff := cmp.Diff(tt.want, urls); diff != "" {
+				t.Errorf("commands mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package flaresolverr
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// refreshRetryDelay is the delay before solving again after a failed refresh.
+const refreshRetryDelay = 30 * time.Second
+
+// Refresher keeps the clearance of a website fresh: it solves the challenge again
+// shortly before the clearance cookies expire, in the background,
+// so hot paths never block on a browser solve.
+type Refresher struct {
+	// URL is the URL the challenge is solved for.
+	URL string
+
+	// Margin is how long before the cookies expire the challenge is solved again.
+	// Defaults to 5 minutes.
+	Margin time.Duration
+
+	// Jitter is the maximum random delay added to Margin,
+	// so refreshers started together do not solve at the same time.
+	Jitter time.Duration
+
+	// Interval is the refresh interval when the cookies do not expire.
+	// Defaults to 30 minutes.
+	Interval time.Duration
+
+	// Options are applied to the requests solving the challenge,
+	// made with PriorityLow unless overridden.
+	Options []RequestOption
+
+	// OnError is called when a background refresh fails, it is retried after 30 seconds.
+	OnError func(error)
+
+	// Clock is the clock of the refresher, the system one if nil.
+	Clock Clock
+
+	mu        sync.RWMutex
+	clearance *Clearance
+}
+
+// Start solves the challenge using c, then keeps refreshing it in the background until ctx is done.
+func (r *Refresher) Start(ctx context.Context, c Client) error {
+	if err := r.refresh(ctx, c); err != nil {
+		return err
+	}
+
+	go r.run(ctx, c)
+	return nil
+}
+
+// Clearance returns the last solved clearance.
+func (r *Refresher) Clearance() *Clearance {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.clearance
+}
+
+func (r *Refresher) refresh(ctx context.Context, c Client) error {
+	opts := append([]RequestOption{WithPriority(PriorityLow)}, r.Options...)
+	resp, err := c.GetWithOptions(ctx, r.URL, uuid.Nil, opts...)
+	if err != nil {
+		return err
+	}
+
+	clearance := &Clearance{URL: r.URL}
+	if resp.Solution != nil {
+		clearance = resp.Solution.Clearance()
+	}
+
+	r.mu.Lock()
+	r.clearance = clearance
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *Refresher) clock() Clock {
+	if r.Clock == nil {
+		return systemClock
+	}
+
+	return r.Clock
+}
+
+func (r *Refresher) run(ctx context.Context, c Client) {
+	clock := r.clock()
+	delay := r.next(clock.Now())
+	for {
+		timer := clock.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+
+		if err := r.refresh(ctx, c); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if r.OnError != nil {
+				r.OnError(err)
+			}
+			delay = refreshRetryDelay
+			continue
+		}
+
+		delay = r.next(clock.Now())
+	}
+}
+
+// next returns the delay before the next refresh: before the first cookie expires,
+// preferring cf_clearance, minus the margin and jitter.
+func (r *Refresher) next(now time.Time) time.Duration {
+	margin, interval := r.Margin, r.Interval
+	if margin == 0 {
+		margin = 5 * time.Minute
+	}
+	if interval == 0 {
+		interval = 30 * time.Minute
+	}
+	if r.Jitter > 0 {
+		margin += time.Duration(rand.Int63n(int64(r.Jitter)))
+	}
+
+	var expires time.Time
+	for _, cookie := range r.Clearance().Cookies {
+		if cookie.Expires.IsZero() {
+			continue
+		}
+
+		if cookie.Name == "cf_clearance" {
+			expires = cookie.Expires
+			break
+		}
+
+		if expires.IsZero() || cookie.Expires.Before(expires) {
+			expires = cookie.Expires
+		}
+	}
+
+	if expires.IsZero() {
+		return interval
+	}
+
+	return max(expires.Sub(now)-margin, 0)
+}
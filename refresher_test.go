@@ -0,0 +1,97 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRefresher(t *testing.T) {
+	var solves atomic.Int32
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		solves.Add(1)
+		expires := time.Now().Add(200 * time.Millisecond)
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{
+			URL:     cmd.URL,
+			Cookies: []Cookie{{Name: "cf_clearance", Value: "solved", Expires: float64(expires.UnixNano()) / float64(time.Second)}},
+		}}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := &Refresher{URL: "https://example.com", Margin: 150 * time.Millisecond}
+	if err := r.Start(ctx, c); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	if r.Clearance() == nil || len(r.Clearance().Cookies) != 1 {
+		t.Fatalf("Clearance() = %v, want the solved clearance", r.Clearance())
+	}
+
+	time.Sleep(175 * time.Millisecond)
+	if got := solves.Load(); got < 3 {
+		t.Errorf("solved %d times, want the clearance refreshed before expiring", got)
+	}
+
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	got := solves.Load()
+	time.Sleep(100 * time.Millisecond)
+	if after := solves.Load(); after != got {
+		t.Errorf("solved %d times after the context is done", after-got)
+	}
+}
+
+func TestRefresher_next(t *testing.T) {
+	now := time.Now()
+	r := &Refresher{Margin: time.Minute}
+	r.clearance = &Clearance{Cookies: []*http.Cookie{
+		{Name: "session", Expires: now.Add(time.Hour)},
+		{Name: "cf_clearance", Expires: now.Add(2 * time.Hour)},
+	}}
+
+	if got, want := r.next(now), 2*time.Hour-time.Minute; got != want {
+		t.Errorf("next() = %v, want %v", got, want)
+	}
+
+	r.clearance = &Clearance{}
+	if got, want := r.next(now), 30*time.Minute; got != want {
+		t.Errorf("next() = %v, want %v", got, want)
+	}
+}
+
+func TestRefresher_Clock(t *testing.T) {
+	clock := newFakeClock()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var solves atomic.Int32
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		if solves.Add(1) == 3 {
+			cancel()
+		}
+		expires := clock.Now().Add(time.Hour)
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{
+			URL:     cmd.URL,
+			Cookies: []Cookie{{Name: "cf_clearance", Value: "solved", Expires: float64(expires.Unix())}},
+		}}
+	})
+
+	r := &Refresher{URL: "https://example.com", Margin: 10 * time.Minute, Clock: clock}
+	if err := r.Start(ctx, c); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	<-ctx.Done()
+	for _, waited := range clock.Waited() {
+		if waited != 50*time.Minute {
+			t.Errorf("waited %v, want the refresh scheduled on the fake clock", waited)
+		}
+	}
+	if len(clock.Waited()) < 2 {
+		t.Errorf("waited %v, want at least two refreshes", clock.Waited())
+	}
+}
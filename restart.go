@@ -0,0 +1,79 @@
+package flaresolverr
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServerRestart describes a detected FlareSolverr restart.
+type ServerRestart struct {
+	// At is when the restart has been detected.
+	At time.Time
+
+	// PreviousVersion and Version are the server versions before and after the restart,
+	// they are equal when the restart has been detected from a lost session.
+	PreviousVersion string
+	Version         string
+
+	// LostSession is the session found missing, if the restart has been detected this way.
+	LostSession uuid.UUID
+}
+
+// restartDetector detects FlareSolverr restarts from the version reported by the responses
+// and from the sessions tracked by the client which do not exist anymore.
+type restartDetector struct {
+	onRestart func(ServerRestart)
+	clock     Clock
+
+	mu      sync.Mutex
+	version string
+}
+
+// observe checks the command outcome for a restart.
+func (d *restartDetector) observe(c *client, cmd *flaresolverrCommand, response *Response, err error) {
+	d.mu.Lock()
+	restart := ServerRestart{At: d.clock.Now(), PreviousVersion: d.version, Version: d.version}
+	switch {
+	case err == nil && response.Version != "":
+		restarted := d.version != "" && d.version != response.Version
+		d.version = response.Version
+		restart.Version = response.Version
+		if !restarted {
+			d.mu.Unlock()
+			return
+		}
+	case errors.Is(err, ErrSessionNotFound):
+		id, parseErr := uuid.Parse(cmd.Session)
+		if parseErr != nil || c.sessions.get(id) == nil {
+			d.mu.Unlock()
+			return
+		}
+		restart.LostSession = id
+	default:
+		d.mu.Unlock()
+		return
+	}
+	d.mu.Unlock()
+
+	c.invalidate()
+	if d.onRestart != nil {
+		d.onRestart(restart)
+	}
+}
+
+// invalidate forgets the local state which does not survive a FlareSolverr restart.
+func (c *client) invalidate() {
+	c.sessions.reset()
+	if c.clearances != nil {
+		c.clearances.reset()
+	}
+	if c.keepAlive != nil {
+		c.keepAlive.reset()
+	}
+	if c.domainSessions != nil {
+		c.domainSessions.reset()
+	}
+}
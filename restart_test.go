@@ -0,0 +1,70 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_client_WithRestartDetection(t *testing.T) {
+	var mu sync.Mutex
+	version, alive := "3.3.20", true
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if cmd.Session != "" && cmd.Cmd != CommandSessionscreate && !alive {
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: This session does not exist.", Version: version}
+		}
+		return http.StatusOK, &Response{Status: "ok", Version: version, Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+
+	var restarts []ServerRestart
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithRestartDetection(func(r ServerRestart) {
+		restarts = append(restarts, r)
+	}))
+	ctx := context.Background()
+
+	session := uuid.New()
+	if _, err := c.CreateSession(ctx, session); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	// the server is upgraded
+	mu.Lock()
+	version = "3.3.21"
+	mu.Unlock()
+
+	if _, err := c.Get(ctx, "https://example.com", uuid.Nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if len(restarts) != 1 || restarts[0].PreviousVersion != "3.3.20" || restarts[0].Version != "3.3.21" {
+		t.Fatalf("restarts = %+v, want an upgrade to 3.3.21", restarts)
+	}
+
+	if c.Session(session) != nil {
+		t.Errorf("Session() = %v, want the session forgotten", c.Session(session))
+	}
+
+	// the server is restarted, losing the sessions
+	if _, err := c.CreateSession(ctx, session); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	mu.Lock()
+	alive = false
+	mu.Unlock()
+
+	if _, err := c.Get(ctx, "https://example.com", session); err == nil {
+		t.Fatal("Get() error = nil, want the session not found")
+	}
+
+	if len(restarts) != 2 || restarts[1].LostSession != session {
+		t.Errorf("restarts = %+v, want the session %s lost", restarts, session)
+	}
+}
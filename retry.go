@@ -0,0 +1,74 @@
+package flaresolverr
+
+import (
+	"context"
+	"time"
+)
+
+// RetryPolicy controls how failed operations are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// Zero means a single attempt.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry.
+	// It doubles after every attempt, up to MaxBackoff when set.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// RetryPOST retries the POST requests too. They are not retried by default:
+	// the target may have processed a request whose response was lost.
+	RetryPOST bool
+}
+
+// forCommand returns the policy retrying a command, making a single attempt for POST requests
+// unless RetryPOST is set.
+func (p RetryPolicy) forCommand(cmd Command) RetryPolicy {
+	if cmd == CommandRequestpost && !p.RetryPOST {
+		p.MaxAttempts = 1
+	}
+
+	return p
+}
+
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+
+	return p.MaxAttempts
+}
+
+// backoff returns the delay to wait after the given failed attempt, starting at 1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+
+	return d
+}
+
+// retry runs fn until it succeeds, fails with an error retryable rejects,
+// or the attempts are exhausted. It returns the last error.
+func (p RetryPolicy) retry(ctx context.Context, clock Clock, fn func(attempt int) error, retryable func(error) bool) error {
+	var err error
+	for attempt := 1; attempt <= p.attempts(); attempt++ {
+		if err = fn(attempt); err == nil || !retryable(err) || attempt == p.attempts() {
+			return err
+		}
+
+		timer := clock.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C():
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,94 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 3, want: 4 * time.Second},
+		{attempt: 4, want: 5 * time.Second},
+		{attempt: 10, want: 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicy_retry(t *testing.T) {
+	errRetryable := errors.New("retryable")
+	errPermanent := errors.New("permanent")
+
+	tests := []struct {
+		name         string
+		policy       RetryPolicy
+		errs         []error
+		wantErr      error
+		wantAttempts int
+	}{
+		{
+			name:         "Succeeds first",
+			policy:       RetryPolicy{MaxAttempts: 3},
+			errs:         []error{nil},
+			wantAttempts: 1,
+		},
+		{
+			name:         "Succeeds after retry",
+			policy:       RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+			errs:         []error{errRetryable, nil},
+			wantAttempts: 2,
+		},
+		{
+			name:         "Attempts exhausted",
+			policy:       RetryPolicy{MaxAttempts: 2},
+			errs:         []error{errRetryable, errRetryable, nil},
+			wantErr:      errRetryable,
+			wantAttempts: 2,
+		},
+		{
+			name:         "Permanent error",
+			policy:       RetryPolicy{MaxAttempts: 3},
+			errs:         []error{errPermanent, nil},
+			wantErr:      errPermanent,
+			wantAttempts: 1,
+		},
+		{
+			name:         "No retry by default",
+			policy:       RetryPolicy{},
+			errs:         []error{errRetryable, nil},
+			wantErr:      errRetryable,
+			wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := tt.policy.retry(context.Background(), systemClock, func(attempt int) error {
+				attempts = attempt
+				return tt.errs[attempt-1]
+			}, func(err error) bool {
+				return errors.Is(err, errRetryable)
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("retry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("retry() attempts = %v, want %v", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errChallenge when a direct request hits a new challenge.
+var errChallenge = errors.New("challenge detected")
+
+// clearanceCache keeps the last clearance solved for every hostname.
+type clearanceCache struct {
+	clock Clock
+
+	mu      sync.Mutex
+	entries map[string]*Clearance
+}
+
+func newClearanceCache() *clearanceCache {
+	return &clearanceCache{clock: systemClock, entries: make(map[string]*Clearance)}
+}
+
+func (c *clearanceCache) get(u string) *Clearance {
+	host := hostname(u)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clearance, ok := c.entries[host]
+	if !ok {
+		return nil
+	}
+
+	if !clearance.valid(c.clock.Now()) {
+		delete(c.entries, host)
+		return nil
+	}
+
+	return clearance
+}
+
+// reset forgets every clearance.
+func (c *clearanceCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+func (c *clearanceCache) put(u string, clearance *Clearance) {
+	if clearance.UserAgent == "" || len(clearance.Cookies) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[hostname(u)] = clearance
+}
+
+// valid reports whether the clearance cookies are still valid at now.
+func (c *Clearance) valid(now time.Time) bool {
+	for _, cookie := range c.Cookies {
+		if newCookie(cookie).IsExpired(now) {
+			return false
+		}
+	}
+
+	return len(c.Cookies) > 0
+}
+
+func hostname(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return u
+	}
+
+	return strings.ToLower(parsed.Hostname())
+}
+
+// revalidable reports whether the command can be made directly, without FlareSolverr.
+// Requests within a session or through a proxy must be made by the browser.
+func revalidable(cmd *flaresolverrCommand) bool {
+	return cacheable(cmd) && cmd.Session == "" && cmd.Proxy == "" && cmd.UserAgent == ""
+}
+
+// fetchDirect makes the command directly with the clearance cookies and user agent.
+// It fails with errChallenge when the target answers with a new challenge.
+func (c *client) fetchDirect(ctx context.Context, cmd *flaresolverrCommand, clearance *Clearance) (*Response, error) {
+	method, body := http.MethodGet, io.Reader(http.NoBody)
+	if cmd.Cmd == CommandRequestpost {
+		method, body = http.MethodPost, strings.NewReader(cmd.PostData)
+	}
+
+	req, err := clearance.NewRequest(ctx, method, cmd.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot make request: %w", err)
+	}
+
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if IsChallenge(resp) {
+		return nil, errChallenge
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.clock.Now()
+	solution := &ResponseSolution{
+		URL:       resp.Request.URL.String(),
+		Status:    resp.StatusCode,
+		Response:  string(content),
+		UserAgent: clearance.UserAgent,
+		clock:     c.clock,
+	}
+	solution.Headers.Date = resp.Header.Get("Date")
+	solution.Headers.ContentType = resp.Header.Get("Content-Type")
+	solution.Headers.Server = resp.Header.Get("Server")
+	solution.Headers.CfRay = resp.Header.Get("Cf-Ray")
+
+	for _, cookie := range clearance.Cookies {
+		solution.Cookies = append(solution.Cookies, newCookie(cookie))
+	}
+
+	return &Response{
+		Status:         StatusOK,
+		Message:        "Fetched directly with a cached clearance.",
+		StartTimestamp: now.UnixMilli(),
+		EndTimestamp:   now.UnixMilli(),
+		Solution:       solution,
+	}, nil
+}
+
+// newCookie converts a net/http cookie.
+func newCookie(cookie *http.Cookie) Cookie {
+	c := Cookie{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		Domain:   cookie.Domain,
+		Path:     cookie.Path,
+		Size:     len(cookie.Name) + len(cookie.Value),
+		HTTPOnly: cookie.HttpOnly,
+		Secure:   cookie.Secure,
+		Session:  cookie.Expires.IsZero(),
+		Expires:  -1,
+	}
+
+	if !cookie.Expires.IsZero() {
+		c.Expires = float64(cookie.Expires.UnixNano()) / float64(time.Second)
+	}
+
+	switch cookie.SameSite {
+	case http.SameSiteStrictMode:
+		c.SameSite = "Strict"
+	case http.SameSiteLaxMode:
+		c.SameSite = "Lax"
+	case http.SameSiteNoneMode:
+		c.SameSite = "None"
+	}
+
+	return c
+}
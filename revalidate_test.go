@@ -0,0 +1,71 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_client_WithRevalidation(t *testing.T) {
+	var challenge atomic.Bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("cf_clearance")
+		if challenge.Load() || err != nil || cookie.Value != "solved" || r.UserAgent() != "Mozilla/5.0" {
+			w.Header().Set("cf-mitigated", "challenge")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("direct " + r.URL.Path))
+	}))
+	defer target.Close()
+
+	var commands atomic.Int32
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		commands.Add(1)
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{
+			URL:       cmd.URL,
+			Status:    http.StatusOK,
+			Response:  "solved",
+			UserAgent: "Mozilla/5.0",
+			Cookies:   []Cookie{{Name: "cf_clearance", Value: "solved", Expires: float64(time.Now().Add(time.Hour).Unix())}},
+		}}
+	})
+	c := New(srv.baseURL, time.Second, target.Client(), WithResponseCache(time.Minute, 0), WithRevalidation())
+
+	ctx := context.Background()
+	tests := []struct {
+		name         string
+		path         string
+		challenge    bool
+		want         string
+		wantCommands int32
+	}{
+		{name: "Unknown clearance", path: "/a", want: "solved", wantCommands: 1},
+		{name: "Cached response", path: "/a", want: "solved", wantCommands: 1},
+		{name: "Direct request", path: "/b", want: "direct /b", wantCommands: 1},
+		{name: "New challenge", path: "/c", challenge: true, want: "solved", wantCommands: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge.Store(tt.challenge)
+
+			resp, err := c.Get(ctx, target.URL+tt.path, uuid.Nil)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+
+			if resp.Solution.Response != tt.want {
+				t.Errorf("Get() = %q, want %q", resp.Solution.Response, tt.want)
+			}
+
+			if got := commands.Load(); got != tt.wantCommands {
+				t.Errorf("sent %d commands, want %d", got, tt.wantCommands)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package flaresolverr
+
+import (
+	"bufio"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// robots holds the robots.txt rules applying to a crawler.
+type robots struct {
+	rules    []robotsRule
+	delay    time.Duration
+	sitemaps []string
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// allowAll is used when a site has no robots.txt.
+var allowAll = &robots{}
+
+// disallowAll is used when the robots.txt of a site cannot be retrieved.
+var disallowAll = &robots{rules: []robotsRule{{pattern: "/"}}}
+
+// parseRobots parses a robots.txt file, keeping the group matching userAgent,
+// or the * group when none matches.
+func parseRobots(body, userAgent string) *robots {
+	userAgent = strings.ToLower(userAgent)
+
+	type group struct {
+		agents []string
+		rules  []robotsRule
+		delay  time.Duration
+	}
+
+	var groups []*group
+	var current *group
+	var sitemaps []string
+	inAgents := false
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				current = &group{}
+				groups = append(groups, current)
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			inAgents = true
+			continue
+		case "allow", "disallow":
+			// an empty Disallow allows everything
+			if current != nil && value != "" {
+				current.rules = append(current.rules, robotsRule{allow: key == "allow", pattern: value})
+			}
+		case "crawl-delay":
+			if seconds, err := strconv.ParseFloat(value, 64); current != nil && err == nil && seconds > 0 {
+				current.delay = time.Duration(seconds * float64(time.Second))
+			}
+		case "sitemap":
+			sitemaps = append(sitemaps, value)
+		}
+		inAgents = false
+	}
+
+	// the groups of the most specific agent are merged, as are the * groups
+	var best string
+	for _, g := range groups {
+		for _, agent := range g.agents {
+			if agent != "*" && len(agent) > len(best) && strings.Contains(userAgent, agent) {
+				best = agent
+			}
+		}
+	}
+	if best == "" {
+		best = "*"
+	}
+
+	r := &robots{sitemaps: sitemaps}
+	for _, g := range groups {
+		for _, agent := range g.agents {
+			if agent == best {
+				r.rules = append(r.rules, g.rules...)
+				r.delay = max(r.delay, g.delay)
+				break
+			}
+		}
+	}
+
+	return r
+}
+
+// allowed reports whether u can be crawled. The longest matching rule wins,
+// Allow winning over Disallow on a tie.
+func (r *robots) allowed(u *url.URL) bool {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	allow, length := true, -1
+	for _, rule := range r.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+
+		if len(rule.pattern) > length || (len(rule.pattern) == length && rule.allow) {
+			allow, length = rule.allow, len(rule.pattern)
+		}
+	}
+
+	return allow
+}
+
+// robotsMatch matches path against a robots.txt pattern,
+// supporting the * wildcard and the $ end anchor.
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+
+		index := strings.Index(rest, part)
+		if index < 0 {
+			return false
+		}
+		rest = rest[index+len(part):]
+	}
+
+	return !anchored || rest == ""
+}
+
+// plainText returns the text of a plain text file returned by FlareSolverr,
+// which the browser wraps in an HTML document.
+func plainText(body string) string {
+	if !strings.HasPrefix(strings.TrimSpace(body), "<") {
+		return body
+	}
+
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return body
+	}
+
+	var pre *html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.Pre && pre == nil {
+			pre = n
+		}
+		for child := n.FirstChild; child != nil && pre == nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	if pre == nil {
+		return text(doc)
+	}
+
+	return text(pre)
+}
+
+// sitemapLocations returns the page and nested sitemap URLs listed by a sitemap.
+func sitemapLocations(body string) (pages, sitemaps []string) {
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return nil, nil
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "loc" && n.Parent != nil {
+			loc := strings.TrimSpace(text(n))
+			if n.Parent.Data == "sitemap" {
+				sitemaps = append(sitemaps, loc)
+			} else {
+				pages = append(pages, loc)
+			}
+			return
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	return pages, sitemaps
+}
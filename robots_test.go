@@ -0,0 +1,108 @@
+package flaresolverr
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseRobots(t *testing.T) {
+	const body = `# robots.txt
+User-agent: *
+Disallow: /private/
+Allow: /private/public
+Crawl-delay: 2
+
+User-agent: BadBot
+User-agent: EvilBot
+Disallow: /
+
+User-agent: scraper
+Disallow: /*.pdf$
+Disallow: /search?
+Disallow:
+Crawl-delay: 0.5
+
+Sitemap: https://example.com/sitemap.xml
+`
+
+	tests := []struct {
+		userAgent string
+		path      string
+		want      bool
+	}{
+		{userAgent: "*", path: "/", want: true},
+		{userAgent: "*", path: "/private/", want: false},
+		{userAgent: "*", path: "/private/secret", want: false},
+		{userAgent: "*", path: "/private/public/page", want: true},
+		{userAgent: "*", path: "/file.pdf", want: true},
+		{userAgent: "evilbot/1.0", path: "/", want: false},
+		{userAgent: "Mozilla/5.0 (compatible; Scraper/2.1)", path: "/private/", want: true},
+		{userAgent: "scraper", path: "/doc/file.pdf", want: false},
+		{userAgent: "scraper", path: "/doc/file.pdf?download=1", want: true},
+		{userAgent: "scraper", path: "/search?q=foo", want: false},
+		{userAgent: "scraper", path: "/search", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.userAgent+tt.path, func(t *testing.T) {
+			u, err := url.Parse("https://example.com" + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := parseRobots(body, tt.userAgent).allowed(u); got != tt.want {
+				t.Errorf("allowed(%s) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	r := parseRobots(body, "*")
+	if r.delay != 2*time.Second {
+		t.Errorf("parseRobots() delay = %v, want %v", r.delay, 2*time.Second)
+	}
+
+	if diff := cmp.Diff([]string{"https://example.com/sitemap.xml"}, r.sitemaps); diff != "" {
+		t.Errorf("parseRobots() sitemaps mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_robotsMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "/", path: "/anything", want: true},
+		{pattern: "/fish", path: "/fish.html", want: true},
+		{pattern: "/fish", path: "/Fish", want: false},
+		{pattern: "/fish*.php", path: "/fishheads/catfish.php?id=2", want: true},
+		{pattern: "/*.php$", path: "/index.php", want: true},
+		{pattern: "/*.php$", path: "/index.php5", want: false},
+		{pattern: "/fish$", path: "/fish", want: true},
+		{pattern: "/fish$", path: "/fish/", want: false},
+		{pattern: "*/admin", path: "/site/admin/", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			if got := robotsMatch(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("robotsMatch(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_plainText(t *testing.T) {
+	const robots = "User-agent: *\nDisallow: /a&b\n"
+	for _, body := range []string{
+		robots,
+		`<html><head></head><body><pre style="word-wrap: break-word; white-space: pre-wrap;">User-agent: *
+Disallow: /a&amp;b
+</pre></body></html>`,
+	} {
+		if got := plainText(body); got != robots {
+			t.Errorf("plainText() = %q, want %q", got, robots)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrUnscheduledDomain when a domain has not been added to a Scheduler.
+var ErrUnscheduledDomain = errors.New("domain not scheduled")
+
+// schedulerIdleWait is how long the scheduler sleeps when no domain is enabled,
+// it is woken up as soon as one is added or enabled.
+const schedulerIdleWait = time.Hour
+
+// Scheduler solves the challenges of a set of domains again and again on a fixed interval,
+// like a cron job, so the clearances of a high traffic service are always fresh.
+// Challenges are solved with Client.Warmup, see it for what is kept fresh.
+// Domains can be added, removed, enabled and disabled while the scheduler runs.
+type Scheduler struct {
+	// Interval is the interval between two solves of a domain added without interval.
+	// Defaults to 30 minutes.
+	Interval time.Duration
+
+	// Jitter is the maximum random delay added to the interval,
+	// so domains added together are not solved at the same time.
+	Jitter time.Duration
+
+	// Options are applied to the requests solving the challenges,
+	// made with PriorityLow unless overridden.
+	Options []RequestOption
+
+	// OnError is called when solving the challenge of a domain fails, it is retried after 30 seconds.
+	OnError func(domain string, err error)
+
+	// Clock is the clock of the scheduler, the system one if nil.
+	Clock Clock
+
+	mu      sync.Mutex
+	domains map[string]*scheduledDomain
+	wake    chan struct{}
+}
+
+type scheduledDomain struct {
+	interval time.Duration
+	enabled  bool
+	running  bool
+	next     time.Time
+}
+
+// Add schedules domain, solving its challenge every interval, or Scheduler.Interval if zero.
+// The first solve happens as soon as the scheduler runs. If domain is already scheduled,
+// only its interval is updated.
+func (s *Scheduler) Add(domain string, interval time.Duration) {
+	s.mu.Lock()
+	s.init()
+	if d, ok := s.domains[domain]; ok {
+		d.interval = interval
+	} else {
+		s.domains[domain] = &scheduledDomain{interval: interval, enabled: true}
+	}
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// Remove stops solving the challenge of domain.
+func (s *Scheduler) Remove(domain string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.domains, domain)
+}
+
+// Enable resumes solving the challenge of a disabled domain, starting right away.
+func (s *Scheduler) Enable(domain string) error {
+	s.mu.Lock()
+	d, ok := s.domains[domain]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrUnscheduledDomain, domain)
+	}
+
+	if !d.enabled {
+		d.enabled = true
+		d.next = time.Time{}
+	}
+	s.mu.Unlock()
+
+	s.notify()
+	return nil
+}
+
+// Disable pauses solving the challenge of domain, until it is enabled again.
+// A solve already running is not interrupted.
+func (s *Scheduler) Disable(domain string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.domains[domain]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnscheduledDomain, domain)
+	}
+
+	d.enabled = false
+	return nil
+}
+
+// Enabled reports whether the challenge of domain is solved on schedule.
+func (s *Scheduler) Enabled(domain string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.domains[domain]
+	return ok && d.enabled
+}
+
+// Start solves the challenges of the enabled domains using c, in the background, until ctx is done.
+func (s *Scheduler) Start(ctx context.Context, c Client) {
+	s.mu.Lock()
+	s.init()
+	s.mu.Unlock()
+
+	go s.run(ctx, c)
+}
+
+// init prepares the scheduler, s.mu must be held.
+func (s *Scheduler) init() {
+	if s.domains == nil {
+		s.domains = make(map[string]*scheduledDomain)
+	}
+	if s.wake == nil {
+		s.wake = make(chan struct{}, 1)
+	}
+}
+
+// notify wakes the scheduler up, so it looks for due domains again.
+func (s *Scheduler) notify() {
+	s.mu.Lock()
+	wake := s.wake
+	s.mu.Unlock()
+
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) clock() Clock {
+	if s.Clock == nil {
+		return systemClock
+	}
+
+	return s.Clock
+}
+
+func (s *Scheduler) run(ctx context.Context, c Client) {
+	s.mu.Lock()
+	wake := s.wake
+	s.mu.Unlock()
+
+	clock := s.clock()
+	for {
+		due, wait := s.due(clock.Now())
+		for domain, d := range due {
+			go s.solve(ctx, c, domain, d)
+		}
+
+		timer := clock.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-wake:
+		case <-timer.C():
+		}
+		timer.Stop()
+	}
+}
+
+// due marks the enabled domains to solve at now running, and returns them
+// with how long to wait for the next one.
+func (s *Scheduler) due(now time.Time) (map[string]*scheduledDomain, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	due := make(map[string]*scheduledDomain)
+	wait := schedulerIdleWait
+	for domain, d := range s.domains {
+		if !d.enabled || d.running {
+			continue
+		}
+
+		if left := d.next.Sub(now); left > 0 {
+			wait = min(wait, left)
+			continue
+		}
+
+		d.running = true
+		due[domain] = d
+	}
+
+	return due, wait
+}
+
+func (s *Scheduler) solve(ctx context.Context, c Client, domain string, d *scheduledDomain) {
+	opts := append([]RequestOption{WithPriority(PriorityLow)}, s.Options...)
+	err := c.Warmup(ctx, []string{domain}, WithBatchRequestOptions(opts...))
+
+	s.mu.Lock()
+	delay := refreshRetryDelay
+	if err == nil {
+		delay = s.interval(d)
+	}
+	d.running = false
+	d.next = s.clock().Now().Add(delay)
+	s.mu.Unlock()
+
+	if err != nil && ctx.Err() == nil && s.OnError != nil {
+		s.OnError(domain, err)
+	}
+
+	s.notify()
+}
+
+// interval returns the delay before solving d again, s.mu must be held.
+func (s *Scheduler) interval(d *scheduledDomain) time.Duration {
+	interval := d.interval
+	if interval == 0 {
+		interval = s.Interval
+	}
+	if interval == 0 {
+		interval = 30 * time.Minute
+	}
+	if s.Jitter > 0 {
+		interval += time.Duration(rand.Int63n(int64(s.Jitter)))
+	}
+
+	return interval
+}
@@ -0,0 +1,79 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	var mu sync.Mutex
+	solves := make(map[string]int)
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+		solves[hostname(cmd.URL)]++
+		if strings.Contains(cmd.URL, "down.example") {
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error solving the challenge."}
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+	count := func(domain string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return solves[domain]
+	}
+	waitFor := func(cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("condition not met in time, solves = %v", solves)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	errs := make(chan string, 10)
+	s := &Scheduler{
+		Interval: 10 * time.Millisecond,
+		OnError:  func(domain string, err error) { errs <- domain },
+	}
+	s.Add("a.example", 0)
+	s.Add("b.example", time.Hour)
+	s.Add("c.example", 0)
+	s.Add("down.example", 0)
+	if err := s.Disable("c.example"); err != nil {
+		t.Fatalf("Disable() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx, srv)
+
+	// a is solved every interval, b once an hour, c is disabled
+	waitFor(func() bool { return count("a.example") >= 3 })
+	if got := count("b.example"); got != 1 {
+		t.Errorf("b.example solved %d times, want 1", got)
+	}
+	if got := count("c.example"); got != 0 || s.Enabled("c.example") {
+		t.Errorf("c.example solved %d times while disabled", got)
+	}
+	if domain := <-errs; domain != "down.example" {
+		t.Errorf("OnError() domain = %q, want down.example", domain)
+	}
+
+	// c is solved as soon as it is enabled
+	if err := s.Enable("c.example"); err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+	waitFor(func() bool { return count("c.example") >= 1 })
+
+	if err := s.Disable("unknown.example"); !errors.Is(err, ErrUnscheduledDomain) {
+		t.Errorf("Disable() error = %v, want ErrUnscheduledDomain", err)
+	}
+}
@@ -0,0 +1,34 @@
+package flaresolverr
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoScreenshot when the solution has no screenshot, see WithScreenshot.
+var ErrNoScreenshot = errors.New("no screenshot in the solution")
+
+// Screenshot returns the PNG screenshot of the page, requested with WithScreenshot.
+// It fails with ErrNoScreenshot when the server did not return one.
+func (s *ResponseSolution) Screenshot() ([]byte, error) {
+	data := s.ScreenshotBase64
+	if data == "" {
+		return nil, ErrNoScreenshot
+	}
+
+	// some servers return a data URL
+	if strings.HasPrefix(data, "data:") {
+		if _, encoded, ok := strings.Cut(data, ","); ok {
+			data = encoded
+		}
+	}
+
+	screenshot, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid screenshot: %w", err)
+	}
+
+	return screenshot, nil
+}
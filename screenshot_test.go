@@ -0,0 +1,70 @@
+package flaresolverr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestResponseSolution_Screenshot(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+
+	tests := []struct {
+		name       string
+		screenshot string
+		want       []byte
+		wantErr    bool
+	}{
+		{name: "none", screenshot: "", wantErr: true},
+		{name: "base64", screenshot: "iVBORw0KGgo=", want: png},
+		{name: "data URL", screenshot: "data:image/png;base64,iVBORw0KGgo=", want: png},
+		{name: "invalid", screenshot: "not base64!", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ResponseSolution{ScreenshotBase64: tt.screenshot}
+			got, err := s.Screenshot()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Screenshot() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("Screenshot() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_client_WithScreenshot(t *testing.T) {
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		solution := &ResponseSolution{URL: cmd.URL, Status: http.StatusOK}
+		if cmd.ReturnScreenshot {
+			solution.ScreenshotBase64 = "iVBORw0KGgo="
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: solution}
+	})
+
+	c := New(srv.baseURL, time.Second, srv.httpClient)
+	ctx := context.Background()
+
+	resp, err := c.Get(ctx, "https://example.com", uuid.Nil)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, err := resp.Solution.Screenshot(); !errors.Is(err, ErrNoScreenshot) {
+		t.Errorf("Screenshot() error = %v, want ErrNoScreenshot without WithScreenshot", err)
+	}
+
+	resp, err = c.GetWithOptions(ctx, "https://example.com", uuid.Nil, WithScreenshot())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, err := resp.Solution.Screenshot(); err != nil {
+		t.Errorf("Screenshot() error = %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package flaresolverr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// commandFields are the lower-cased fields modeled by flaresolverrCommand.
+var commandFields = jsonFields(reflect.TypeOf(flaresolverrCommand{}))
+
+// SendCommand sends a command with arbitrary parameters through c, which must be created with New.
+// It is meant for the commands and fields only known by some servers, see the betav1 package:
+// cmd does not need to be known by the client, see Command.IsValid. The command is sent as the
+// other ones, with the client headers, transport, timeouts, retries and error handling,
+// but its response is neither cached nor shared. A "maxTimeout" parameter overrides the client timeout.
+func SendCommand(ctx context.Context, c Client, cmd Command, params map[string]any) (*Response, error) {
+	cl, ok := c.(*client)
+	if !ok {
+		return nil, fmt.Errorf("cannot send %s: %T is not created with New", cmd, c)
+	}
+
+	// the modeled parameters are set on the command, the other ones are sent as extra fields
+	o := newRequestOptions(ctx, nil)
+	modeled := make(map[string]any, len(params))
+	for key, value := range params {
+		switch lower := strings.ToLower(key); {
+		case lower == "cmd":
+		case commandFields[lower]:
+			modeled[key] = value
+		default:
+			if o.extra == nil {
+				o.extra = make(map[string]any)
+			}
+			o.extra[key] = value
+		}
+	}
+
+	data, err := json.Marshal(modeled)
+	if err != nil {
+		return nil, fmt.Errorf("invalid command: %w", err)
+	}
+
+	command := new(flaresolverrCommand)
+	if err := json.Unmarshal(data, command); err != nil {
+		return nil, fmt.Errorf("invalid command: %w", err)
+	}
+	command.Cmd = cmd
+	o.maxTimeout = time.Duration(command.MaxTimeout) * time.Millisecond
+
+	return cl.run(ctx, command, o)
+}
@@ -0,0 +1,41 @@
+package flaresolverr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSendCommand(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"status":"ok","message":"","screenshot":"Zm9v"}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, time.Second, srv.Client())
+	resp, err := SendCommand(context.Background(), c, "request.screenshot", map[string]any{
+		"cmd":        "ignored",
+		"url":        "https://example.com",
+		"maxTimeout": 5000,
+		"fullPage":   true,
+	})
+	if err != nil {
+		t.Fatalf("SendCommand() error = %v", err)
+	}
+
+	want := map[string]any{"cmd": "request.screenshot", "url": "https://example.com", "maxTimeout": float64(5000), "fullPage": true}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("sent command mismatch (-want +got):\n%s", diff)
+	}
+
+	if string(resp.Extra["screenshot"]) != `"Zm9v"` {
+		t.Errorf("SendCommand() extra = %v, want the screenshot field", resp.Extra)
+	}
+}
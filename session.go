@@ -0,0 +1,401 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/net/publicsuffix"
+)
+
+var (
+	// ErrUnknownSession when the session has not been used through the client.
+	ErrUnknownSession = errors.New("unknown session")
+
+	// ErrSessionProxyMismatch when a request within a session is made through another proxy
+	// than the one the session was created with, as Cloudflare binds the clearance to the IP.
+	ErrSessionProxyMismatch = errors.New("proxy differs from the session proxy")
+)
+
+// Session is a FlareSolverr session used through the client.
+// It mirrors the browser state so direct requests can be made mid-crawl.
+type Session struct {
+	ID uuid.UUID
+
+	mu        sync.Mutex
+	jar       *cookiejar.Jar
+	userAgent string
+	createdAt time.Time
+	proxy     string
+	labels    map[string]string
+	inFlight  atomic.Int32
+
+	// cookies and extra are the cookies and extra fields the session was created with,
+	// to recreate it alike.
+	cookies []commandCookie
+	extra   map[string]any
+}
+
+func newSession(id uuid.UUID) *Session {
+	// cookiejar.New never fails
+	jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	return &Session{ID: id, jar: jar}
+}
+
+// Jar returns a cookie jar holding every cookie returned by the solutions made within the session.
+func (s *Session) Jar() http.CookieJar {
+	return s.jar
+}
+
+// UserAgent returns the user agent of the browser, as reported by the last solution made within the session.
+func (s *Session) UserAgent() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.userAgent
+}
+
+// CreatedAt returns when the session was created through the client,
+// or the zero time if it was created elsewhere.
+func (s *Session) CreatedAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.createdAt
+}
+
+// Proxy returns the proxy the session was created with through the client.
+func (s *Session) Proxy() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.proxy
+}
+
+// Labels returns a copy of the labels the session was created with, see WithLabel.
+func (s *Session) Labels() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.labels)
+}
+
+// Label returns the value of a session label, and whether it is set.
+func (s *Session) Label(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.labels[key]
+	return value, ok
+}
+
+// InUse reports whether a request is running within the session through the client.
+func (s *Session) InUse() bool {
+	return s.inFlight.Load() > 0
+}
+
+// Clearance returns the session cookies for u with the browser user agent.
+func (s *Session) Clearance(u string) (*Clearance, error) {
+	target, err := url.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Clearance{URL: u, UserAgent: s.UserAgent(), Cookies: s.jar.Cookies(target)}, nil
+}
+
+func (s *Session) record(solution *ResponseSolution) {
+	if u, err := url.Parse(solution.URL); err == nil {
+		s.jar.SetCookies(u, solution.HTTPCookies())
+	}
+
+	if solution.UserAgent != "" {
+		s.mu.Lock()
+		s.userAgent = solution.UserAgent
+		s.mu.Unlock()
+	}
+}
+
+func (s *Session) created(at time.Time, proxy string, labels map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.createdAt = at
+	s.proxy = proxy
+	s.labels = maps.Clone(labels)
+}
+
+func (s *Session) createdWith(cookies []commandCookie, extra map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cookies = cookies
+	s.extra = maps.Clone(extra)
+}
+
+// newUUID returns the ID of a session created by the client on its own.
+func (c *client) newUUID() uuid.UUID {
+	if c.uuidSource == nil {
+		return uuid.New()
+	}
+
+	return c.uuidSource()
+}
+
+// sessionRegistry tracks the sessions used through the client.
+// The zero value is ready to use.
+type sessionRegistry struct {
+	clock Clock
+
+	mu       sync.Mutex
+	sessions map[uuid.UUID]*Session
+}
+
+// now returns the time of the registry clock, the system one by default.
+func (r *sessionRegistry) now() time.Time {
+	if r.clock == nil {
+		return systemClock.Now()
+	}
+
+	return r.clock.Now()
+}
+
+// get returns the tracked session, or nil.
+func (r *sessionRegistry) get(id uuid.UUID) *Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sessions[id]
+}
+
+// getID returns the tracked session, or nil. Only UUID sessions are tracked.
+func (r *sessionRegistry) getID(id SessionID) *Session {
+	parsed, ok := id.UUID()
+	if !ok {
+		return nil
+	}
+
+	return r.get(parsed)
+}
+
+// load returns the tracked session, tracking it if needed.
+func (r *sessionRegistry) load(id uuid.UUID) *Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.sessions == nil {
+		r.sessions = make(map[uuid.UUID]*Session)
+	}
+
+	s, ok := r.sessions[id]
+	if !ok {
+		s = newSession(id)
+		r.sessions[id] = s
+	}
+
+	return s
+}
+
+// count returns the number of tracked sessions.
+func (r *sessionRegistry) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.sessions)
+}
+
+// reset forgets every session.
+func (r *sessionRegistry) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions = nil
+}
+
+func (r *sessionRegistry) delete(id uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, id)
+}
+
+// track updates the registry after a successful command.
+func (r *sessionRegistry) track(cmd *flaresolverrCommand, o *requestOptions, resp *Response) {
+	id, err := uuid.Parse(cmd.Session)
+	if err != nil {
+		return
+	}
+
+	switch cmd.Cmd {
+	case CommandSessionscreate:
+		// a session replaced by the client keeps its creation time and labels
+		if s := r.load(id); !o.recreated || s.CreatedAt().IsZero() {
+			s.created(r.now(), cmd.Proxy, o.labels)
+			s.createdWith(cmd.Cookies, o.extra)
+		}
+	case CommandSessionsdestroy:
+		if !o.recreated {
+			r.delete(id)
+		}
+	case CommandRequestget, CommandRequestpost:
+		if resp.Solution != nil {
+			r.load(id).record(resp.Solution)
+		}
+	}
+}
+
+// withAssignedSession returns cmd, or a copy of it with the session ID generated by FlareSolverr
+// if cmd created a session without ID, so the session is tracked under that ID.
+func withAssignedSession(cmd *flaresolverrCommand, resp *Response) *flaresolverrCommand {
+	if cmd.Cmd != CommandSessionscreate || cmd.Session != "" || resp.Session == "" {
+		return cmd
+	}
+
+	assigned := *cmd
+	assigned.Session = resp.Session
+	return &assigned
+}
+
+// checkProxy fails with ErrSessionProxyMismatch if cmd is a request through a proxy
+// within a session created through the client with another proxy.
+func (r *sessionRegistry) checkProxy(cmd *flaresolverrCommand) error {
+	if (cmd.Cmd != CommandRequestget && cmd.Cmd != CommandRequestpost) || cmd.Proxy == "" {
+		return nil
+	}
+
+	id, err := uuid.Parse(cmd.Session)
+	if err != nil {
+		return nil
+	}
+
+	s := r.get(id)
+	if s == nil || s.CreatedAt().IsZero() {
+		return nil
+	}
+
+	if proxy := s.Proxy(); proxy != cmd.Proxy {
+		return fmt.Errorf("%w: session %s uses %q, not %q", ErrSessionProxyMismatch, cmd.Session, redactURL(proxy), redactURL(cmd.Proxy))
+	}
+
+	return nil
+}
+
+// recreation returns the command and options replacing the session of cmd with a new one
+// of the same ID, created as the session was through the client: with the same proxy, cookies,
+// extra fields and labels, so a recreated session keeps the IP of its clearance.
+// Its tracked state is kept, see requestOptions.recreated.
+func (r *sessionRegistry) recreation(cmd *flaresolverrCommand, o *requestOptions) (*flaresolverrCommand, *requestOptions) {
+	create := &flaresolverrCommand{Cmd: CommandSessionscreate, Session: cmd.Session, Proxy: cmd.Proxy}
+	options := &requestOptions{requestID: o.requestID, priority: o.priority, recreated: true}
+
+	if s := r.getID(SessionID(cmd.Session)); s != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if create.Proxy == "" {
+			create.Proxy = s.proxy
+		}
+		create.Cookies = s.cookies
+		options.extra, options.labels = maps.Clone(s.extra), maps.Clone(s.labels)
+	}
+
+	return create, options
+}
+
+// find returns the oldest tracked session with the label, the smallest ID first on ties, or nil.
+func (r *sessionRegistry) find(key, value string) *Session {
+	r.mu.Lock()
+	candidates := make([]*Session, 0, 1)
+	for _, s := range r.sessions {
+		if v, ok := s.Label(key); ok && v == value {
+			candidates = append(candidates, s)
+		}
+	}
+	r.mu.Unlock()
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i].CreatedAt(), candidates[j].CreatedAt()
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return candidates[i].ID.String() < candidates[j].ID.String()
+	})
+
+	return candidates[0]
+}
+
+// begin marks the tracked session of a request command in use, until the returned function is called.
+func (r *sessionRegistry) begin(cmd *flaresolverrCommand) func() {
+	id, err := uuid.Parse(cmd.Session)
+	if err != nil || (cmd.Cmd != CommandRequestget && cmd.Cmd != CommandRequestpost) {
+		return func() {}
+	}
+
+	s := r.get(id)
+	if s == nil {
+		return func() {}
+	}
+
+	s.inFlight.Add(1)
+	return func() { s.inFlight.Add(-1) }
+}
+
+// SessionInfo describes a session active on the server, with what the client knows about it.
+type SessionInfo struct {
+	ID SessionID
+
+	// Tracked when the session has been used through the client,
+	// the other fields are only known for tracked sessions.
+	Tracked bool
+
+	// CreatedAt is when the session was created through the client,
+	// the zero time if it was created elsewhere.
+	CreatedAt time.Time
+
+	// Proxy is the proxy the session was created with.
+	Proxy string
+
+	// Labels are the labels the session was created with, see WithLabel.
+	Labels map[string]string
+
+	// InUse when a request is running within the session through the client.
+	InUse bool
+}
+
+// ListSessionInfo returns the sessions active on the server, merged with the client state.
+func (c *client) ListSessionInfo(ctx context.Context, opts ...RequestOption) ([]SessionInfo, error) {
+	resp, err := c.ListSessionsWithOptions(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := resp.SessionIDs()
+	infos := make([]SessionInfo, 0, len(ids))
+	for _, id := range ids {
+		info := SessionInfo{ID: id}
+		if s := c.sessions.getID(id); s != nil {
+			info.Tracked = true
+			info.CreatedAt = s.CreatedAt()
+			info.Proxy = s.Proxy()
+			info.Labels = s.Labels()
+			info.InUse = s.InUse()
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// Session returns the session with the given ID if it has been used through the client, or nil.
+// Its cookie jar mirrors every cookie returned within the session.
+func (c *client) Session(id uuid.UUID) *Session {
+	return c.sessions.get(id)
+}
+
+// FindSessionByLabel returns the session created through the client with the label, or nil.
+// When several sessions match, the oldest one is returned.
+func (c *client) FindSessionByLabel(key, value string) *Session {
+	return c.sessions.find(key, value)
+}
@@ -0,0 +1,244 @@
+package flaresolverr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_client_Session(t *testing.T) {
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		resp := &Response{Status: "ok", Session: cmd.Session}
+		if cmd.Cmd == CommandRequestget {
+			resp.Solution = &ResponseSolution{
+				URL:       cmd.URL,
+				UserAgent: "bar",
+				Cookies: []Cookie{
+					{Name: "cf_clearance", Value: cmd.URL, Domain: ".example.com", Path: "/", Session: true},
+				},
+			}
+		}
+
+		return http.StatusOK, resp
+	})
+
+	ctx := context.Background()
+	id := uuid.New()
+	if c.Session(id) != nil {
+		t.Fatalf("Session() expected unknown session")
+	}
+
+	if _, err := c.CreateSession(ctx, id); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	session := c.Session(id)
+	if session == nil {
+		t.Fatalf("Session() expected created session")
+	}
+
+	for _, u := range []string{"https://example.com/", "https://www.example.com/page/2"} {
+		if _, err := c.Get(ctx, u, id); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+
+	// requests made without the session must not leak into it
+	if _, err := c.Get(ctx, "https://example.com/other", uuid.Nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	cookies := session.Jar().Cookies(&url.URL{Scheme: "https", Host: "example.com", Path: "/"})
+	if len(cookies) != 1 || cookies[0].Value != "https://www.example.com/page/2" {
+		t.Errorf("Jar() cookies = %v, want the last cf_clearance", cookies)
+	}
+
+	if got := session.UserAgent(); got != "bar" {
+		t.Errorf("UserAgent() = %v, want %v", got, "bar")
+	}
+
+	if err := c.DestroySession(ctx, id); err != nil {
+		t.Fatalf("DestroySession() error = %v", err)
+	}
+
+	if c.Session(id) != nil {
+		t.Errorf("Session() expected destroyed session to be forgotten")
+	}
+}
+
+func Test_client_ListSessionInfo(t *testing.T) {
+	tracked, foreign := uuid.New(), uuid.New()
+	started, release := make(chan struct{}), make(chan struct{})
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		switch cmd.Cmd {
+		case CommandSessionslist:
+			return http.StatusOK, &Response{Status: "ok", Raw: json.RawMessage(`{"status":"ok","sessions":["` + tracked.String() + `","custom_1","` + foreign.String() + `"]}`)}
+		case CommandRequestget:
+			close(started)
+			<-release
+		}
+		return http.StatusOK, &Response{Status: "ok", Session: cmd.Session}
+	})
+
+	ctx := context.Background()
+	before := time.Now()
+	if _, err := c.CreateSessionWithOptions(ctx, tracked, WithProxy("http://proxy:8080"), WithLabel("site", "example.com")); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = c.Get(ctx, "https://example.com", tracked)
+	}()
+	<-started
+
+	infos, err := c.ListSessionInfo(ctx)
+	close(release)
+	<-done
+	if err != nil {
+		t.Fatalf("ListSessionInfo() error = %v", err)
+	}
+
+	if len(infos) != 3 {
+		t.Fatalf("ListSessionInfo() = %v, want 3 sessions", infos)
+	}
+
+	if got := infos[0]; got.ID != SessionIDFromUUID(tracked) || !got.Tracked || !got.InUse || got.Proxy != "http://proxy:8080" || got.Labels["site"] != "example.com" || got.CreatedAt.Before(before) {
+		t.Errorf("ListSessionInfo()[0] = %+v, want the tracked session in use", got)
+	}
+
+	if diff := cmp.Diff([]SessionInfo{{ID: SessionIDFromUUID(foreign)}, {ID: "custom_1"}}, infos[1:]); diff != "" {
+		t.Errorf("ListSessionInfo()[1:] mismatch (-want +got):\n%s", diff)
+	}
+
+	if c.Session(tracked).InUse() {
+		t.Error("InUse() = true once the request is done")
+	}
+}
+
+func Test_client_FindSessionByLabel(t *testing.T) {
+	c := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		return http.StatusOK, &Response{Status: "ok", Session: cmd.Session}
+	})
+
+	ctx := context.Background()
+	first, second, other := uuid.New(), uuid.New(), uuid.New()
+	for _, session := range []struct {
+		id   uuid.UUID
+		site string
+	}{{first, "example.com"}, {second, "example.com"}, {other, "example.org"}} {
+		if _, err := c.CreateSessionWithOptions(ctx, session.id, WithLabel("site", session.site), WithLabel("tier", "free")); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		want  uuid.UUID
+	}{
+		{name: "Oldest match", key: "site", value: "example.com", want: first},
+		{name: "Single match", key: "site", value: "example.org", want: other},
+		{name: "No match", key: "site", value: "example.net"},
+		{name: "Unknown label", key: "owner", value: "example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.FindSessionByLabel(tt.key, tt.value)
+			if got == nil {
+				if tt.want != uuid.Nil {
+					t.Errorf("FindSessionByLabel() = nil, want %s", tt.want)
+				}
+				return
+			}
+
+			if got.ID != tt.want {
+				t.Errorf("FindSessionByLabel() = %s, want %s", got.ID, tt.want)
+			}
+		})
+	}
+
+	if err := c.DestroySession(ctx, first); err != nil {
+		t.Fatalf("DestroySession() error = %v", err)
+	}
+
+	if got := c.FindSessionByLabel("site", "example.com"); got == nil || got.ID != second {
+		t.Errorf("FindSessionByLabel() = %v after destroy, want %s", got, second)
+	}
+}
+
+func Test_client_sessionProxyAffinity(t *testing.T) {
+	var mu sync.Mutex
+	sessions := make(map[string]string)
+	var created []string
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch cmd.Cmd {
+		case CommandSessionscreate:
+			sessions[cmd.Session] = cmd.Proxy
+			created = append(created, cmd.Proxy)
+			return http.StatusOK, &Response{Status: "ok", Session: cmd.Session}
+		case CommandSessionsdestroy:
+			delete(sessions, cmd.Session)
+			return http.StatusOK, &Response{Status: "ok"}
+		default:
+			if _, ok := sessions[cmd.Session]; !ok {
+				return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: This session does not exist."}
+			}
+			return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}}
+		}
+	})
+
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithSessionRecreate())
+	ctx := context.Background()
+	session := uuid.New()
+	if _, err := c.CreateSessionWithOptions(ctx, session, WithProxy("http://user:secret@a:8080")); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []RequestOption
+		wantErr error
+	}{
+		{name: "Session proxy", opts: nil},
+		{name: "Same proxy", opts: []RequestOption{WithProxy("http://user:secret@a:8080")}},
+		{name: "Other proxy", opts: []RequestOption{WithProxy("http://b:8080")}, wantErr: ErrSessionProxyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.GetWithOptions(ctx, "https://example.com", session, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), "secret") {
+				t.Errorf("Get() error = %v, contains the proxy password", err)
+			}
+		})
+	}
+
+	// the session lost by the server is recreated with its proxy
+	mu.Lock()
+	delete(sessions, session.String())
+	mu.Unlock()
+	if _, err := c.Get(ctx, "https://example.com", session); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"http://user:secret@a:8080", "http://user:secret@a:8080"}, created); diff != "" {
+		t.Errorf("created sessions proxies mismatch (-want +got):\n%s", diff)
+	}
+}
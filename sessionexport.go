@@ -0,0 +1,152 @@
+package flaresolverr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidSessionExport when sessions cannot be imported from the given data.
+var ErrInvalidSessionExport = errors.New("invalid session export")
+
+// sessionExportVersion is the version of the format written by ExportSessions.
+const sessionExportVersion = 1
+
+type sessionExport struct {
+	Version  int               `json:"version"`
+	Sessions []exportedSession `json:"sessions"`
+}
+
+type exportedSession struct {
+	ID        uuid.UUID         `json:"id"`
+	CreatedAt time.Time         `json:"createdAt,omitempty"`
+	Proxy     string            `json:"proxy,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	UserAgent string            `json:"userAgent,omitempty"`
+
+	// Site is the site the session is routed to, see WithSessionPerDomain.
+	Site string `json:"site,omitempty"`
+}
+
+// ExportSessions returns the sessions tracked by the client as JSON, with their proxy, labels
+// and site when WithSessionPerDomain is used, so another process can take them over with ImportSessions
+// instead of destroying them and solving the challenges again.
+// The export holds the proxy credentials, store it accordingly.
+func (c *client) ExportSessions() ([]byte, error) {
+	sites := make(map[uuid.UUID]string)
+	if c.domainSessions != nil {
+		sites = c.domainSessions.sites()
+	}
+
+	c.sessions.mu.Lock()
+	tracked := make([]*Session, 0, len(c.sessions.sessions))
+	for _, s := range c.sessions.sessions {
+		tracked = append(tracked, s)
+	}
+	c.sessions.mu.Unlock()
+
+	export := sessionExport{Version: sessionExportVersion, Sessions: make([]exportedSession, 0, len(tracked))}
+	for _, s := range tracked {
+		s.mu.Lock()
+		export.Sessions = append(export.Sessions, exportedSession{
+			ID:        s.ID,
+			CreatedAt: s.createdAt,
+			Proxy:     s.proxy,
+			Labels:    maps.Clone(s.labels),
+			UserAgent: s.userAgent,
+			Site:      sites[s.ID],
+		})
+		s.mu.Unlock()
+	}
+
+	sort.Slice(export.Sessions, func(i, j int) bool {
+		return export.Sessions[i].ID.String() < export.Sessions[j].ID.String()
+	})
+
+	return json.Marshal(export)
+}
+
+// ImportSessions tracks the sessions exported by ExportSessions, as if they were created through the client.
+// Sessions are routed to their site when WithSessionPerDomain is used, and kept alive when
+// WithSessionKeepAlive is used. They are not checked on the server, use WithSessionRecreate
+// to recreate the ones which do not exist anymore. The cookie jars of the sessions start empty.
+// Errors wrap ErrInvalidSessionExport.
+func (c *client) ImportSessions(data []byte) error {
+	var export sessionExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSessionExport, err)
+	}
+
+	if export.Version != sessionExportVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidSessionExport, export.Version)
+	}
+
+	for _, exported := range export.Sessions {
+		if exported.ID == uuid.Nil {
+			return fmt.Errorf("%w: session without ID", ErrInvalidSessionExport)
+		}
+	}
+
+	for _, exported := range export.Sessions {
+		s := c.sessions.load(exported.ID)
+		s.created(exported.CreatedAt, exported.Proxy, exported.Labels)
+		s.mu.Lock()
+		s.userAgent = exported.UserAgent
+		s.mu.Unlock()
+
+		if c.domainSessions != nil && exported.Site != "" {
+			c.domainSessions.adopt(c, exported.Site, exported.Proxy, exported.ID)
+		}
+
+		if c.keepAlive != nil {
+			c.keepAlive.track(c, &flaresolverrCommand{Cmd: CommandSessionscreate, Session: exported.ID.String()})
+		}
+	}
+
+	return nil
+}
+
+// sites returns the site of every created domain session.
+func (d *domainSessions) sites() map[uuid.UUID]string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sites := make(map[uuid.UUID]string, len(d.sessions))
+	for key, s := range d.sessions {
+		select {
+		case <-s.ready:
+			if s.err == nil {
+				site, _, _ := strings.Cut(key, "\x00")
+				sites[s.id] = site
+			}
+		default:
+		}
+	}
+
+	return sites
+}
+
+// adopt routes the requests to the site through the proxy to an existing session,
+// unless the site already has one. The session is destroyed once idle.
+func (d *domainSessions) adopt(c *client, site, proxy string, id uuid.UUID) {
+	key := site + "\x00" + proxy
+
+	d.mu.Lock()
+	if _, ok := d.sessions[key]; ok {
+		d.mu.Unlock()
+		return
+	}
+
+	ready := make(chan struct{})
+	close(ready)
+	d.sessions[key] = &domainSession{id: id, ready: ready, inFlight: 1}
+	d.mu.Unlock()
+
+	d.release(c, key, id)
+}
@@ -0,0 +1,92 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_client_ExportSessions(t *testing.T) {
+	var mu sync.Mutex
+	var commands []string
+	handler := func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, cmd.Cmd.String()+" "+cmd.Session)
+		return http.StatusOK, &Response{Status: "ok", Session: cmd.Session, Solution: &ResponseSolution{URL: cmd.URL, UserAgent: "Mozilla/5.0"}}
+	}
+
+	ctx := context.Background()
+	labelled := uuid.MustParse("3f0c2a5e-8d1b-4c7a-9e6f-2b4d6a8c0e1f")
+	routed := uuid.MustParse("7b1e4c2a-0f3d-4a5b-8c6d-9e0f1a2b3c4d")
+
+	previous := newTestServer(t, handler)
+	WithSessionPerDomain(time.Hour)(previous)
+	WithUUIDSource(func() uuid.UUID { return routed })(previous)
+	if _, err := previous.CreateSessionWithOptions(ctx, labelled, WithProxy("http://proxy:8080"), WithLabel("site", "other.com")); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if _, err := previous.Get(ctx, "https://www.example.com", uuid.Nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	state, err := previous.ExportSessions()
+	if err != nil {
+		t.Fatalf("ExportSessions() error = %v", err)
+	}
+
+	next := newTestServer(t, handler)
+	WithSessionPerDomain(time.Hour)(next)
+	if err := next.ImportSessions(state); err != nil {
+		t.Fatalf("ImportSessions() error = %v", err)
+	}
+
+	// the imported site session is used, without creating a new one
+	mu.Lock()
+	commands = nil
+	mu.Unlock()
+	if _, err := next.Get(ctx, "https://example.com/page", uuid.Nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"request.get " + routed.String()}, commands); diff != "" {
+		t.Errorf("commands mismatch (-want +got):\n%s", diff)
+	}
+
+	s := next.FindSessionByLabel("site", "other.com")
+	if s == nil || s.ID != labelled {
+		t.Fatalf("FindSessionByLabel() = %v, want session %s", s, labelled)
+	}
+	if s.Proxy() != "http://proxy:8080" || s.CreatedAt().IsZero() {
+		t.Errorf("imported session proxy = %q, created at %v", s.Proxy(), s.CreatedAt())
+	}
+
+	// the proxy binding survives the handoff
+	if _, err := next.GetWithOptions(ctx, "https://other.com", labelled, WithProxy("http://another:8080")); !errors.Is(err, ErrSessionProxyMismatch) {
+		t.Errorf("Get() error = %v, want ErrSessionProxyMismatch", err)
+	}
+}
+
+func Test_client_ImportSessions_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "Not JSON", data: "sessions"},
+		{name: "Unknown version", data: `{"version":2,"sessions":[]}`},
+		{name: "Missing ID", data: `{"version":1,"sessions":[{"proxy":"http://proxy:8080"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New("http://localhost:8191/v1", time.Second, nil)
+			if err := c.ImportSessions([]byte(tt.data)); !errors.Is(err, ErrInvalidSessionExport) {
+				t.Errorf("ImportSessions() error = %v, want ErrInvalidSessionExport", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package flaresolverr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidSessionID when a session ID cannot be sent to FlareSolverr.
+var ErrInvalidSessionID = errors.New("invalid session ID")
+
+// maxSessionIDLen is the maximum length of a session ID.
+const maxSessionIDLen = 256
+
+// SessionID is the ID of a FlareSolverr session, which does not have to be a UUID:
+// FlareSolverr accepts any string, and generates its own IDs when none is given.
+// Use it with WithSessionID when the IDs come from another UUID library, another
+// ID scheme or the server. The zero value is no session.
+type SessionID string
+
+// ParseSessionID checks id can be sent to FlareSolverr: it must not be empty, be at most
+// 256 bytes long, nor hold spaces, control characters or path separators.
+// Errors wrap ErrInvalidSessionID.
+func ParseSessionID(id string) (SessionID, error) {
+	if id == "" {
+		return "", fmt.Errorf("%w: empty", ErrInvalidSessionID)
+	}
+
+	if len(id) > maxSessionIDLen {
+		return "", fmt.Errorf("%w: longer than %d bytes", ErrInvalidSessionID, maxSessionIDLen)
+	}
+
+	if i := strings.IndexFunc(id, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r) || r == '/' || r == '\\' || r == unicode.ReplacementChar
+	}); i >= 0 {
+		return "", fmt.Errorf("%w: %q: invalid character at byte %d", ErrInvalidSessionID, id, i)
+	}
+
+	return SessionID(id), nil
+}
+
+// SessionIDFromUUID returns the session ID of a google/uuid session, the zero value for uuid.Nil.
+func SessionIDFromUUID(id uuid.UUID) SessionID {
+	return SessionID(handleSession(id))
+}
+
+// UUID returns the session ID as a UUID, for the methods taking one,
+// or uuid.Nil and false if it is not a UUID.
+func (id SessionID) UUID() (uuid.UUID, bool) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	return parsed, true
+}
+
+// String returns the session ID.
+func (id SessionID) String() string {
+	return string(id)
+}
+
+// IsZero reports whether id is the zero value, no session.
+func (id SessionID) IsZero() bool {
+	return id == ""
+}
+
+// SessionList is the list of the sessions returned by ListSessions whose ID is a UUID.
+// The other sessions are in Response.OtherSessions, instead of failing the decoding.
+type SessionList []uuid.UUID
+
+// UnmarshalJSON decodes a list of session IDs, keeping the UUIDs.
+func (l *SessionList) UnmarshalJSON(data []byte) error {
+	var ids []SessionID
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return err
+	}
+
+	if ids == nil {
+		*l = nil
+		return nil
+	}
+
+	list := make(SessionList, 0, len(ids))
+	for _, id := range ids {
+		if parsed, ok := id.UUID(); ok {
+			list = append(list, parsed)
+		}
+	}
+
+	*l = list
+	return nil
+}
+
+// SessionIDs returns every session returned by ListSessions: Sessions, then OtherSessions.
+func (r *Response) SessionIDs() []SessionID {
+	ids := make([]SessionID, 0, len(r.Sessions)+len(r.OtherSessions))
+	for _, id := range r.Sessions {
+		ids = append(ids, SessionIDFromUUID(id))
+	}
+
+	return append(ids, r.OtherSessions...)
+}
+
+// otherSessions returns the sessions of the raw sessions field of a response whose ID is not a UUID, or nil.
+func otherSessions(value []byte) []SessionID {
+	var list []SessionID
+	if err := json.Unmarshal(value, &list); err != nil {
+		return nil
+	}
+
+	var others []SessionID
+	for _, id := range list {
+		if _, ok := id.UUID(); !ok {
+			others = append(others, id)
+		}
+	}
+
+	return others
+}
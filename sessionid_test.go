@@ -0,0 +1,184 @@
+package flaresolverr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func TestParseSessionID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{name: "UUID", id: "3f0c2a5e-8d1b-4c7a-9e6f-2b4d6a8c0e1f"},
+		{name: "Server generated", id: "session_1714"},
+		{name: "ULID", id: "01HV6Z3K9Q8M2N4P5R7S9T1V3W"},
+		{name: "Empty", id: "", wantErr: true},
+		{name: "Space", id: "my session", wantErr: true},
+		{name: "Path", id: "../session", wantErr: true},
+		{name: "Control", id: "session\x00", wantErr: true},
+		{name: "Too long", id: strings.Repeat("a", maxSessionIDLen+1), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSessionID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSessionID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSessionID) {
+				t.Errorf("ParseSessionID() error = %v, want ErrInvalidSessionID", err)
+			}
+			if !tt.wantErr && got.String() != tt.id {
+				t.Errorf("ParseSessionID() = %q, want %q", got, tt.id)
+			}
+		})
+	}
+}
+
+func TestSessionID_UUID(t *testing.T) {
+	id := uuid.New()
+	if got, ok := SessionIDFromUUID(id).UUID(); !ok || got != id {
+		t.Errorf("UUID() = %v, %v, want %v", got, ok, id)
+	}
+
+	if !SessionIDFromUUID(uuid.Nil).IsZero() {
+		t.Errorf("SessionIDFromUUID(uuid.Nil) is not the zero value")
+	}
+
+	if _, ok := SessionID("session_1714").UUID(); ok {
+		t.Errorf("UUID() of a non UUID session ID reported ok")
+	}
+}
+
+func Test_client_WithSessionID(t *testing.T) {
+	var mu sync.Mutex
+	var got []string
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, cmd.Cmd.String()+" "+cmd.Session)
+		return http.StatusOK, &Response{Status: "ok", Session: cmd.Session, Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+
+	c := New(srv.baseURL, time.Second, srv.httpClient)
+	ctx := context.Background()
+	id := SessionID("session_1714")
+	explicit := uuid.MustParse("3f0c2a5e-8d1b-4c7a-9e6f-2b4d6a8c0e1f")
+
+	_, _ = c.CreateSessionWithOptions(ctx, uuid.Nil, WithSessionID(id))
+	_, _ = c.GetWithOptions(ctx, "https://example.com", uuid.Nil, WithSessionID(id))
+	_, _ = c.GetWithOptions(ctx, "https://example.com", explicit, WithSessionID(id))
+	_, _ = c.PostWithOptions(ctx, "https://example.com", uuid.Nil, "a=b", WithSession(explicit), WithSessionID(id))
+	_ = c.DestroySessionWithOptions(ctx, uuid.Nil, WithSessionID(id))
+
+	want := []string{
+		"sessions.create session_1714",
+		"request.get session_1714",
+		"request.get " + explicit.String(),
+		"request.post session_1714",
+		"sessions.destroy session_1714",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("commands mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_client_CreateSessionAuto(t *testing.T) {
+	assigned := uuid.MustParse("7b1e4c2a-0f3d-4a5b-8c6d-9e0f1a2b3c4d")
+	tests := []struct {
+		name    string
+		session string
+		want    SessionID
+		wantErr error
+	}{
+		{name: "UUID", session: assigned.String(), want: SessionIDFromUUID(assigned)},
+		{name: "Not a UUID", session: "session_1714", want: "session_1714"},
+		{name: "Missing", session: "", wantErr: ErrInvalidServerResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent *flaresolverrCommand
+			srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+				sent = cmd
+				return http.StatusOK, &Response{Status: "ok", Session: tt.session}
+			})
+
+			c := New(srv.baseURL, time.Second, srv.httpClient)
+			got, err := c.CreateSessionAuto(context.Background(), WithSessionID("ignored"), WithLabel("site", "example.com"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateSessionAuto() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("CreateSessionAuto() = %q, want %q", got, tt.want)
+			}
+			if sent.Session != "" {
+				t.Errorf("CreateSessionAuto() sent session %q, want none", sent.Session)
+			}
+
+			// sessions with a UUID are tracked under the assigned ID
+			tracked := c.FindSessionByLabel("site", "example.com")
+			if id, ok := tt.want.UUID(); ok {
+				if tracked == nil || tracked.ID != id {
+					t.Errorf("FindSessionByLabel() = %v, want session %s", tracked, id)
+				}
+			} else if tracked != nil {
+				t.Errorf("FindSessionByLabel() = %v, want nil", tracked)
+			}
+		})
+	}
+}
+
+func Test_client_ListSessions_nonUUID(t *testing.T) {
+	id := uuid.New()
+	body := `{"status":"ok","sessions":["` + id.String() + `","session_1714"]}`
+	for _, strict := range []bool{false, true} {
+		var opts []Option
+		if strict {
+			opts = append(opts, WithStrictDecoding())
+		}
+
+		srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+			return http.StatusOK, &Response{Raw: json.RawMessage(body)}
+		})
+		c := New(srv.baseURL, time.Second, srv.httpClient, opts...)
+
+		resp, err := c.ListSessions(context.Background())
+		if err != nil {
+			t.Fatalf("ListSessions() strict %v error = %v", strict, err)
+		}
+
+		if diff := cmp.Diff(SessionList{id}, resp.Sessions); diff != "" {
+			t.Errorf("ListSessions() strict %v sessions mismatch (-want +got):\n%s", strict, diff)
+		}
+		if diff := cmp.Diff([]SessionID{SessionIDFromUUID(id), "session_1714"}, resp.SessionIDs()); diff != "" {
+			t.Errorf("SessionIDs() strict %v mismatch (-want +got):\n%s", strict, diff)
+		}
+	}
+}
+
+func Test_client_WithSessionID_invalid(t *testing.T) {
+	var commands int
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		commands++
+		return http.StatusOK, &Response{Status: "ok"}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient)
+
+	_, err := c.GetWithOptions(context.Background(), "https://example.com", uuid.Nil, WithSessionID("../session"))
+	if !errors.Is(err, ErrInvalidSessionID) {
+		t.Errorf("Get() error = %v, want ErrInvalidSessionID", err)
+	}
+	if commands != 0 {
+		t.Errorf("Get() sent %d commands, want none", commands)
+	}
+}
@@ -0,0 +1,57 @@
+package flaresolverr
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+)
+
+// share runs fn once for all the concurrent callers using the same key, and gives them its result.
+// fn runs with a context which is not canceled with ctx, so a caller giving up
+// does not fail the others; it stops waiting and gets its context error.
+func share[T any](ctx context.Context, group *singleflight.Group, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	ch := group.DoChan(key, func() (any, error) {
+		return fn(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case result := <-ch:
+		v, _ := result.Val.(T)
+		return v, result.Err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// flightKey identifies identical request commands.
+func flightKey(cmd *flaresolverrCommand, o *requestOptions) string {
+	return strings.Join([]string{
+		cmd.Cmd.String(),
+		cmd.URL,
+		cmd.Session,
+		cmd.Proxy,
+		cmd.PostData,
+		cmd.UserAgent,
+		strconv.Itoa(cmd.WaitInSeconds),
+		cmd.WaitForSelector,
+		strconv.FormatBool(cmd.ReturnScreenshot),
+		strconv.FormatBool(cmd.ReturnOnlyCookies),
+		strconv.FormatBool(cmd.ReturnBase64),
+		variantKey(cmd, o),
+	}, "\x00")
+}
+
+// clearanceKey identifies the solves of the same domain.
+func clearanceKey(u string, o *requestOptions) string {
+	host := u
+	if parsed, err := url.Parse(u); err == nil {
+		host = strings.ToLower(parsed.Hostname())
+	}
+
+	return strings.Join([]string{"clearance", host, o.sessionFor(uuid.Nil), o.proxy}, "\x00")
+}
@@ -0,0 +1,91 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_client_WithSingleflight(t *testing.T) {
+	var commands atomic.Int32
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		commands.Add(1)
+		time.Sleep(200 * time.Millisecond)
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL}}
+	})
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithSingleflight())
+
+	var wg sync.WaitGroup
+	responses := make([]*Response, 5)
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var err error
+			if responses[i], err = c.Get(context.Background(), "https://example.com", uuid.Nil); err != nil {
+				t.Errorf("Get() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := commands.Load(); got != 1 {
+		t.Errorf("Get() sent %d commands, want 1", got)
+	}
+
+	for _, resp := range responses {
+		if resp != responses[0] {
+			t.Errorf("Get() responses are not shared")
+		}
+	}
+
+	// a caller giving up does not cancel the shared command
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		_, err := c.Get(context.Background(), "https://example.org", uuid.Nil)
+		done <- err
+	}()
+
+	if _, err := c.Get(ctx, "https://example.org", uuid.Nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+}
+
+func Test_clearanceKey(t *testing.T) {
+	o := &requestOptions{}
+	if clearanceKey("https://Example.com/a", o) != clearanceKey("https://example.com/b?c=d", o) {
+		t.Errorf("clearanceKey() differs for the same domain")
+	}
+
+	if clearanceKey("https://example.com", o) == clearanceKey("https://example.com", &requestOptions{proxy: "http://proxy"}) {
+		t.Errorf("clearanceKey() is the same for different proxies")
+	}
+}
+
+func Test_flightKey(t *testing.T) {
+	get := &flaresolverrCommand{Cmd: CommandRequestget, URL: "https://example.com"}
+	withCookie := &flaresolverrCommand{Cmd: CommandRequestget, URL: "https://example.com", Cookies: []commandCookie{{Name: "a", Value: "1"}}}
+	o := &requestOptions{}
+
+	if flightKey(get, o) == flightKey(withCookie, o) {
+		t.Errorf("flightKey() is the same for different cookies")
+	}
+
+	if flightKey(get, o) == flightKey(get, &requestOptions{extra: map[string]any{"tabs": 1}}) {
+		t.Errorf("flightKey() is the same for different extra fields")
+	}
+}
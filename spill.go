@@ -0,0 +1,96 @@
+package flaresolverr
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// spilledBody is a solution body stored in a temporary file.
+type spilledBody struct {
+	path string
+	size int64
+}
+
+// spill moves the decoded solution body to a temporary file in dir when it exceeds threshold bytes.
+func spill(s *ResponseSolution, threshold int64, dir string) error {
+	if s == nil || int64(len(s.Response)) <= threshold {
+		return nil
+	}
+
+	f, err := os.CreateTemp(dir, "flaresolverr-*.html")
+	if err != nil {
+		return fmt.Errorf("cannot spill solution body: %w", err)
+	}
+	defer f.Close()
+
+	n, err := io.WriteString(f, s.Response)
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("cannot spill solution body: %w", err)
+	}
+
+	s.spilled = &spilledBody{path: f.Name(), size: int64(n)}
+	s.Response = ""
+
+	// remove the file if the caller forgets to close the solution
+	runtime.SetFinalizer(s.spilled, func(b *spilledBody) { _ = os.Remove(b.path) })
+
+	return nil
+}
+
+// shared reports whether the response of cmd may be returned to several callers,
+// by the response cache or singleflight. Its body is not spilled: closing the solution
+// would remove the body of the other callers.
+func (c *client) shared(cmd *flaresolverrCommand, o *requestOptions) bool {
+	if c.cache != nil && cacheable(cmd) && o.cacheTTL >= 0 {
+		return true
+	}
+
+	return c.flight != nil && (cmd.Cmd == CommandRequestget || cmd.Cmd == CommandRequestpost)
+}
+
+// Body returns a reader of the solution body, wherever it is stored.
+// Use it instead of Response when the client spills large bodies to disk.
+func (s *ResponseSolution) Body() (io.ReadCloser, error) {
+	if s.spilled == nil {
+		return io.NopCloser(strings.NewReader(s.Response)), nil
+	}
+
+	f, err := os.Open(s.spilled.path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read spilled solution body: %w", err)
+	}
+
+	return f, nil
+}
+
+// BodySize returns the size of the solution body in bytes, wherever it is stored.
+func (s *ResponseSolution) BodySize() int64 {
+	if s.spilled == nil {
+		return int64(len(s.Response))
+	}
+
+	return s.spilled.size
+}
+
+// Spilled reports whether the solution body has been moved to a temporary file.
+func (s *ResponseSolution) Spilled() bool {
+	return s.spilled != nil
+}
+
+// Close removes the temporary file holding a spilled solution body.
+// It does nothing if the body is held in memory.
+func (s *ResponseSolution) Close() error {
+	if s.spilled == nil {
+		return nil
+	}
+
+	err := os.Remove(s.spilled.path)
+	runtime.SetFinalizer(s.spilled, nil)
+	s.spilled = nil
+
+	return err
+}
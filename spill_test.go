@@ -0,0 +1,133 @@
+package flaresolverr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_client_WithBodySpill(t *testing.T) {
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Response: cmd.PostData}}
+	})
+
+	dir := t.TempDir()
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithBodySpill(16, dir))
+
+	tests := []struct {
+		name        string
+		body        string
+		wantSpilled bool
+	}{
+		{
+			name:        "Small body kept in memory",
+			body:        "<p>foo</p>",
+			wantSpilled: false,
+		},
+		{
+			name:        "Large body spilled",
+			body:        "<html><title>foo</title><body>bar</body></html>",
+			wantSpilled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := c.Post(context.Background(), "https://example.com", uuid.Nil, tt.body)
+			if err != nil {
+				t.Fatalf("Post() error = %v", err)
+			}
+
+			solution := resp.Solution
+			if solution.Spilled() != tt.wantSpilled || solution.BodySize() != int64(len(tt.body)) {
+				t.Errorf("Spilled() = %v (%d bytes), want %v", solution.Spilled(), solution.BodySize(), tt.wantSpilled)
+			}
+
+			body, err := solution.Body()
+			if err != nil {
+				t.Fatalf("Body() error = %v", err)
+			}
+
+			got, _ := io.ReadAll(body)
+			body.Close()
+			if string(got) != tt.body {
+				t.Errorf("Body() = %q, want %q", got, tt.body)
+			}
+
+			if _, err := solution.Document(); err != nil {
+				t.Errorf("Document() error = %v", err)
+			}
+
+			if err := solution.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+
+			if files, _ := os.ReadDir(dir); len(files) != 0 {
+				t.Errorf("Close() left %d files", len(files))
+			}
+		})
+	}
+}
+
+func Test_client_WithBodySpill_shared(t *testing.T) {
+	body := "<html><title>foo</title><body>bar</body></html>"
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		time.Sleep(50 * time.Millisecond)
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Response: body}}
+	})
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		sequential bool
+	}{
+		{name: "Response cache", opts: []Option{WithResponseCache(time.Minute, 10)}, sequential: true},
+		{name: "Singleflight", opts: []Option{WithSingleflight()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			c := New(srv.baseURL, time.Second, srv.httpClient, append(tt.opts, WithBodySpill(16, dir))...)
+
+			// the callers share the response, and close their solution once done
+			var wg sync.WaitGroup
+			for i := 0; i < 3; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					resp, err := c.Get(context.Background(), "https://example.com", uuid.Nil)
+					if err != nil {
+						t.Errorf("Get() error = %v", err)
+						return
+					}
+					defer resp.Solution.Close()
+
+					r, err := resp.Solution.Body()
+					if err != nil {
+						t.Errorf("Body() error = %v", err)
+						return
+					}
+					got, _ := io.ReadAll(r)
+					r.Close()
+					if string(got) != body {
+						t.Errorf("Body() = %q, want %q", got, body)
+					}
+				}()
+				if tt.sequential {
+					wg.Wait()
+				}
+			}
+			wg.Wait()
+
+			if files, _ := os.ReadDir(dir); len(files) != 0 {
+				t.Errorf("spilled %d shared bodies, want them kept in memory", len(files))
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxStatsDurations is the number of latest durations kept per command for the percentiles.
+const maxStatsDurations = 1024
+
+// Stats is a snapshot of the client statistics since it was created, see Client.Stats.
+type Stats struct {
+	// Commands are the statistics of every command sent at least once.
+	Commands map[Command]CommandStats
+
+	// ActiveSessions is the number of sessions tracked by the client.
+	ActiveSessions int
+
+	// CacheHits and CacheMisses count the lookups of WithResponseCache.
+	CacheHits   int64
+	CacheMisses int64
+
+	// Proxies are the scores of the proxies of WithProxyRotation.
+	Proxies []ProxyScore
+}
+
+// CacheHitRate returns the rate of the cache lookups served from the cache.
+func (s Stats) CacheHitRate() float64 {
+	if s.CacheHits+s.CacheMisses == 0 {
+		return 0
+	}
+
+	return float64(s.CacheHits) / float64(s.CacheHits+s.CacheMisses)
+}
+
+// CommandStats are the statistics of a command. Retried commands count once.
+type CommandStats struct {
+	Requests  int64
+	Successes int64
+	Failures  int64
+
+	// FailuresByClass counts the failures by class, such as "timeout" or "captcha", see ErrorClass.
+	FailuresByClass map[string]int64
+
+	// MeanDuration and the percentiles are computed over the latest successful commands.
+	MeanDuration time.Duration
+	P50Duration  time.Duration
+	P90Duration  time.Duration
+	P99Duration  time.Duration
+}
+
+// ErrorClass returns the class of an error returned by the client, for metrics:
+// "timeout", "captcha", "access_denied", "session_not_found", "rate_limited", "challenge_page",
+// "http_status", "unsupported", "invalid_response", "canceled", "network" or "other".
+func ErrorClass(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrRequestTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, ErrCaptchaDetected), errors.Is(err, ErrCustomCaptchaDetected), errors.Is(err, ErrDDoSGuardDetected):
+		return "captcha"
+	case errors.Is(err, ErrAccessDenied):
+		return "access_denied"
+	case errors.Is(err, ErrSessionNotFound):
+		return "session_not_found"
+	case errors.Is(err, ErrTargetRateLimited):
+		return "rate_limited"
+	case errors.Is(err, ErrChallengePageReturned):
+		return "challenge_page"
+	case errors.Is(err, ErrTargetHTTPStatus):
+		return "http_status"
+	case errors.Is(err, ErrUnsupportedByServer):
+		return "unsupported"
+	case errors.Is(err, ErrInvalidServerResponse):
+		return "invalid_response"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &netErr):
+		return "network"
+	default:
+		return "other"
+	}
+}
+
+type commandCounters struct {
+	requests, successes, failures int64
+	failuresByClass               map[string]int64
+	durations                     []time.Duration // ring of the latest durations
+	next                          int
+}
+
+// clientStats collects the statistics of the client. The zero value is ready to use.
+type clientStats struct {
+	mu          sync.Mutex
+	commands    map[Command]*commandCounters
+	cacheHits   int64
+	cacheMisses int64
+}
+
+// observe counts a command which took d and returned err.
+func (s *clientStats) observe(cmd Command, d time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.commands == nil {
+		s.commands = make(map[Command]*commandCounters)
+	}
+
+	counters, ok := s.commands[cmd]
+	if !ok {
+		counters = &commandCounters{failuresByClass: make(map[string]int64)}
+		s.commands[cmd] = counters
+	}
+
+	counters.requests++
+	if err != nil {
+		counters.failures++
+		counters.failuresByClass[ErrorClass(err)]++
+		return
+	}
+
+	counters.successes++
+	if len(counters.durations) < maxStatsDurations {
+		counters.durations = append(counters.durations, d)
+	} else {
+		counters.durations[counters.next] = d
+		counters.next = (counters.next + 1) % maxStatsDurations
+	}
+}
+
+// cacheLookup counts a lookup of the response cache.
+func (s *clientStats) cacheLookup(hit bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if hit {
+		s.cacheHits++
+	} else {
+		s.cacheMisses++
+	}
+}
+
+func (s *clientStats) snapshot() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := Stats{
+		Commands:    make(map[Command]CommandStats, len(s.commands)),
+		CacheHits:   s.cacheHits,
+		CacheMisses: s.cacheMisses,
+	}
+
+	for cmd, counters := range s.commands {
+		cs := CommandStats{
+			Requests:        counters.requests,
+			Successes:       counters.successes,
+			Failures:        counters.failures,
+			FailuresByClass: make(map[string]int64, len(counters.failuresByClass)),
+		}
+		for class, n := range counters.failuresByClass {
+			cs.FailuresByClass[class] = n
+		}
+
+		if len(counters.durations) > 0 {
+			durations := slices.Clone(counters.durations)
+			slices.Sort(durations)
+
+			var total time.Duration
+			for _, d := range durations {
+				total += d
+			}
+			cs.MeanDuration = total / time.Duration(len(durations))
+			cs.P50Duration = percentile(durations, 50)
+			cs.P90Duration = percentile(durations, 90)
+			cs.P99Duration = percentile(durations, 99)
+		}
+
+		stats.Commands[cmd] = cs
+	}
+
+	return stats
+}
+
+// percentile returns the nearest-rank percentile p of the sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank-1, 0)]
+}
+
+// Stats returns a snapshot of the client statistics.
+func (c *client) Stats() Stats {
+	stats := c.stats.snapshot()
+	stats.ActiveSessions = c.sessions.count()
+	if c.proxies != nil {
+		stats.Proxies = c.proxies.scores()
+	}
+
+	return stats
+}
@@ -0,0 +1,95 @@
+package flaresolverr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: ErrRequestTimeout, want: "timeout"},
+		{err: fmt.Errorf("solving: %w", ErrCaptchaDetected), want: "captcha"},
+		{err: &RateLimitError{}, want: "rate_limited"},
+		{err: &ChallengePageError{}, want: "challenge_page"},
+		{err: &HTTPStatusError{}, want: "http_status"},
+		{err: context.Canceled, want: "canceled"},
+		{err: fmt.Errorf("boom"), want: "other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := ErrorClass(tt.err); got != tt.want {
+				t.Errorf("ErrorClass() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_percentile(t *testing.T) {
+	durations := make([]time.Duration, 100)
+	for i := range durations {
+		durations[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	for p, want := range map[int]time.Duration{50: 50 * time.Millisecond, 90: 90 * time.Millisecond, 99: 99 * time.Millisecond} {
+		if got := percentile(durations, p); got != want {
+			t.Errorf("percentile(%d) = %v, want %v", p, got, want)
+		}
+	}
+
+	if got := percentile(durations[:1], 99); got != time.Millisecond {
+		t.Errorf("percentile() of a single duration = %v, want 1ms", got)
+	}
+}
+
+func Test_client_Stats(t *testing.T) {
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		switch {
+		case cmd.Cmd == CommandSessionscreate:
+			return http.StatusOK, &Response{Status: "ok", Session: cmd.Session}
+		case strings.HasSuffix(cmd.URL, "/timeout"):
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error: maximum timeout reached"}
+		default:
+			return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Status: http.StatusOK}}
+		}
+	})
+
+	c := New(srv.baseURL, time.Second, srv.httpClient, WithResponseCache(time.Minute, 10))
+	ctx := context.Background()
+
+	_, _ = c.CreateSession(ctx, uuid.New())
+	_, _ = c.Get(ctx, "https://example.com/", uuid.Nil)
+	_, _ = c.Get(ctx, "https://example.com/", uuid.Nil)
+	_, _ = c.Get(ctx, "https://example.com/timeout", uuid.Nil)
+
+	got := c.Stats()
+	get := got.Commands[CommandRequestget]
+	if get.P99Duration <= 0 || get.MeanDuration <= 0 {
+		t.Errorf("Stats() get durations = %+v, want positive durations", get)
+	}
+	get.MeanDuration, get.P50Duration, get.P90Duration, get.P99Duration = 0, 0, 0, 0
+
+	want := CommandStats{Requests: 2, Successes: 1, Failures: 1, FailuresByClass: map[string]int64{"timeout": 1}}
+	if diff := cmp.Diff(want, get); diff != "" {
+		t.Errorf("Stats() get mismatch (-want +got):\n%s", diff)
+	}
+	if create := got.Commands[CommandSessionscreate]; create.Successes != 1 {
+		t.Errorf("Stats() create = %+v, want a success", create)
+	}
+
+	if got.ActiveSessions != 1 {
+		t.Errorf("Stats() active sessions = %d, want 1", got.ActiveSessions)
+	}
+	if got.CacheHits != 1 || got.CacheMisses != 2 || got.CacheHitRate() != 1.0/3 {
+		t.Errorf("Stats() cache = %d hits, %d misses, want 1 hit and 2 misses", got.CacheHits, got.CacheMisses)
+	}
+}
@@ -0,0 +1,47 @@
+package flaresolverr
+
+import "strings"
+
+// Status is the outcome of a command reported by FlareSolverr.
+// Unknown values are kept as is, so newer servers do not break the client.
+type Status string
+
+const (
+	// StatusOK when the command succeeded.
+	StatusOK Status = "ok"
+	// StatusWarning when the command succeeded with a warning in Response.Message.
+	StatusWarning Status = "warning"
+	// StatusError when the command failed, the reason is in Response.Message.
+	StatusError Status = "error"
+)
+
+// String implements the Stringer interface.
+func (s Status) String() string {
+	return string(s)
+}
+
+// IsValid reports whether s is one of the known statuses.
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusOK, StatusWarning, StatusError:
+		return true
+	default:
+		return false
+	}
+}
+
+// UnmarshalText implements the text unmarshaller method, it never fails.
+// Known statuses are matched case-insensitively.
+func (s *Status) UnmarshalText(text []byte) error {
+	*s = Status(text)
+	if status := Status(strings.ToLower(string(text))); status.IsValid() {
+		*s = status
+	}
+
+	return nil
+}
+
+// OK reports whether the command succeeded without warning.
+func (r *Response) OK() bool {
+	return r.Status == StatusOK
+}
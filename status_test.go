@@ -0,0 +1,43 @@
+package flaresolverr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStatus_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		want      Status
+		wantValid bool
+		wantOK    bool
+	}{
+		{name: "OK", data: `{"status":"ok"}`, want: StatusOK, wantValid: true, wantOK: true},
+		{name: "Warning", data: `{"status":"warning"}`, want: StatusWarning, wantValid: true},
+		{name: "Error", data: `{"status":"error"}`, want: StatusError, wantValid: true},
+		{name: "Upper case", data: `{"status":"OK"}`, want: StatusOK, wantValid: true, wantOK: true},
+		{name: "Unknown", data: `{"status":"pending"}`, want: Status("pending")},
+		{name: "Missing", data: `{}`, want: Status("")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp Response
+			if err := json.Unmarshal([]byte(tt.data), &resp); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			if resp.Status != tt.want {
+				t.Errorf("Status = %q, want %q", resp.Status, tt.want)
+			}
+
+			if got := resp.Status.IsValid(); got != tt.wantValid {
+				t.Errorf("IsValid() = %v, want %v", got, tt.wantValid)
+			}
+
+			if got := resp.OK(); got != tt.wantOK {
+				t.Errorf("OK() = %v, want %v", got, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package flaresolverr
+
+import (
+	"sync"
+	"time"
+)
+
+// suppressor collapses identical events happening within a window:
+// the first one goes through, the repeats are counted and reported
+// in a single summary when the window ends.
+type suppressor struct {
+	window  time.Duration
+	summary func(key string, count int, first, last time.Time)
+	clock   Clock
+
+	mu      sync.Mutex
+	entries map[string]*suppressed
+	done    chan struct{} // closed to end every window
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+type suppressed struct {
+	first, last time.Time
+	count       int
+}
+
+func newSuppressor(window time.Duration, summary func(key string, count int, first, last time.Time)) *suppressor {
+	return &suppressor{window: window, summary: summary, clock: systemClock, entries: make(map[string]*suppressed), done: make(chan struct{})}
+}
+
+// allow reports whether the event identified by key should be emitted.
+func (s *suppressor) allow(key string) bool {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok {
+		if entry.count == 0 {
+			entry.first = now
+		}
+		entry.count++
+		entry.last = now
+		return false
+	}
+
+	if s.closed {
+		return true
+	}
+
+	s.entries[key] = new(suppressed)
+	timer := s.clock.NewTimer(s.window)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		select {
+		case <-timer.C():
+		case <-s.done:
+			timer.Stop()
+		}
+		s.flush(key)
+	}()
+	return true
+}
+
+// close ends every window, reporting the suppressed events. The next events are not suppressed.
+func (s *suppressor) close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// flush ends the window of key, reporting the suppressed events if any.
+func (s *suppressor) flush(key string) {
+	s.mu.Lock()
+	entry := s.entries[key]
+	delete(s.entries, key)
+	s.mu.Unlock()
+
+	if entry != nil && entry.count > 0 {
+		s.summary(key, entry.count, entry.first, entry.last)
+	}
+}
@@ -0,0 +1,47 @@
+package flaresolverr
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_suppressor(t *testing.T) {
+	var mu sync.Mutex
+	summaries := make(map[string]int)
+	s := newSuppressor(50*time.Millisecond, func(key string, count int, first, last time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+		summaries[key] = count
+
+		if last.Before(first) {
+			t.Errorf("summary last %v before first %v", last, first)
+		}
+	})
+
+	for i, want := range []bool{true, false, false} {
+		if got := s.allow("foo"); got != want {
+			t.Errorf("allow(foo) #%d = %v, want %v", i, got, want)
+		}
+	}
+
+	if !s.allow("bar") {
+		t.Errorf("allow(bar) = false, want true")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	if summaries["foo"] != 2 {
+		t.Errorf("summary(foo) = %d, want 2", summaries["foo"])
+	}
+	if _, ok := summaries["bar"]; ok {
+		t.Errorf("summary(bar) expected no summary without repeats")
+	}
+	mu.Unlock()
+
+	// a new window starts after the summary
+	if !s.allow("foo") {
+		t.Errorf("allow(foo) after window = false, want true")
+	}
+}
@@ -0,0 +1,41 @@
+package flaresolverr
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWithUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flaresolverr.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets are not supported: %v", err)
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(&Response{Status: "ok", Message: "over unix socket"})
+	}))
+	srv.Listener = listener
+	srv.Start()
+	defer srv.Close()
+
+	c := New("http://flaresolverr/v1", time.Second, nil, WithUnixSocket(path))
+	resp, err := c.ListSessions(context.Background())
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+
+	if resp.Message != "over unix socket" {
+		t.Errorf("ListSessions() = %q, want the unix socket server response", resp.Message)
+	}
+
+	if c.(*client).httpClient != http.DefaultClient {
+		t.Errorf("WithUnixSocket() changed the http client of direct requests")
+	}
+}
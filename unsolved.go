@@ -0,0 +1,107 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// ErrChallengePageReturned when FlareSolverr reports a success but the solution is still
+// the challenge page. The error is a *ChallengePageError.
+var ErrChallengePageReturned = errors.New("challenge page returned as solution")
+
+// unsolvedMarkers are found in the body of challenge pages which have not been solved, by vendor.
+// Unlike challengeMarkers, they are not found in the pages protected by the vendor once solved.
+var unsolvedMarkers = map[Vendor][]string{
+	VendorCloudflare: {
+		"<title>just a moment...</title>",
+		"<title>attention required! | cloudflare</title>",
+		"window._cf_chl_opt",
+		"cf-browser-verification",
+	},
+	VendorDDoSGuard: {
+		"<title>ddos-guard</title>",
+	},
+}
+
+// ChallengePageError is returned when the solution is still the challenge page.
+type ChallengePageError struct {
+	Vendor Vendor
+
+	// Response is the response holding the challenge page.
+	Response *Response
+}
+
+// Error returns a message such as "challenge page returned as solution: cloudflare".
+func (e *ChallengePageError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrChallengePageReturned, e.Vendor)
+}
+
+// Unwrap returns ErrChallengePageReturned.
+func (e *ChallengePageError) Unwrap() error {
+	return ErrChallengePageReturned
+}
+
+// ChallengeVendor returns the vendor of the challenge if the solution is still
+// the challenge page, or an empty vendor. The beginning of the body is inspected.
+func (s *ResponseSolution) ChallengeVendor() Vendor {
+	body, err := s.Body()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	head, err := io.ReadAll(io.LimitReader(body, challengeSniffLen))
+	if err != nil {
+		return ""
+	}
+
+	lower := strings.ToLower(string(head))
+	for _, vendor := range []Vendor{VendorCloudflare, VendorDDoSGuard} {
+		for _, marker := range unsolvedMarkers[vendor] {
+			if strings.Contains(lower, marker) {
+				return vendor
+			}
+		}
+	}
+
+	return ""
+}
+
+// challengePageError returns a *ChallengePageError if the solution of a request is still the challenge page.
+func challengePageError(cmd *flaresolverrCommand, response *Response) error {
+	if !cacheable(cmd) || response.Solution == nil {
+		return nil
+	}
+
+	vendor := response.Solution.ChallengeVendor()
+	if vendor == "" {
+		return nil
+	}
+
+	return &ChallengePageError{Vendor: vendor, Response: response}
+}
+
+// runRetryingChallenge runs the command, retrying it with a fresh session up to
+// the retries of WithChallengeRetry while the challenge page is returned.
+func (c *client) runRetryingChallenge(ctx context.Context, cmd *flaresolverrCommand, o *requestOptions) (*Response, error) {
+	response, err := c.route(ctx, cmd, o)
+	for retry := 0; retry < c.challengeRetries && errors.Is(err, ErrChallengePageReturned); retry++ {
+		c.log(ctx, slog.LevelInfo, "challenge page returned, retrying with a fresh session", slog.String("session", cmd.Session), slog.Int("retry", retry+1))
+		if cmd.Session != "" {
+			// the browser of the session is stuck on the challenge, replace it
+			create, createOptions := c.sessions.recreation(cmd, o)
+			_, _ = c.exec(ctx, &flaresolverrCommand{Cmd: CommandSessionsdestroy, Session: cmd.Session}, createOptions)
+			if _, createErr := c.exec(ctx, create, createOptions); createErr != nil {
+				return nil, err
+			}
+		}
+
+		response, err = c.route(ctx, cmd, o)
+	}
+
+	return response, err
+}
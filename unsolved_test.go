@@ -0,0 +1,109 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func TestResponseSolution_ChallengeVendor(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want Vendor
+	}{
+		{name: "page", body: `<html><head><title>Home</title><script src="/cdn-cgi/challenge-platform/scripts/jsd/main.js"></script></head></html>`, want: ""},
+		{name: "cloudflare", body: `<html><head><title>Just a moment...</title></head></html>`, want: VendorCloudflare},
+		{name: "cloudflare script", body: `<script>window._cf_chl_opt = {cvId: '3'}</script>`, want: VendorCloudflare},
+		{name: "ddos-guard", body: `<html><head><title>DDoS-Guard</title></head></html>`, want: VendorDDoSGuard},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ResponseSolution{Response: tt.body}
+			if got := s.ChallengeVendor(); got != tt.want {
+				t.Errorf("ChallengeVendor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_client_WithChallengeRetry(t *testing.T) {
+	const challenge = `<html><head><title>Just a moment...</title></head></html>`
+	session := uuid.MustParse("3f0c2a5e-8d1b-4c7a-9e6f-2b4d6a8c0e1f")
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		challenges int
+		wantErr    bool
+		want       []Command
+	}{
+		{
+			name:       "Without retry",
+			challenges: 1,
+			wantErr:    true,
+			want:       []Command{CommandRequestget},
+		},
+		{
+			name:       "Solved on retry",
+			opts:       []Option{WithChallengeRetry(2)},
+			challenges: 1,
+			want:       []Command{CommandRequestget, CommandSessionsdestroy, CommandSessionscreate, CommandRequestget},
+		},
+		{
+			name:       "Retries exhausted",
+			opts:       []Option{WithChallengeRetry(1)},
+			challenges: 2,
+			wantErr:    true,
+			want:       []Command{CommandRequestget, CommandSessionsdestroy, CommandSessionscreate, CommandRequestget},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var got []Command
+			challenges := tt.challenges
+			srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+				mu.Lock()
+				defer mu.Unlock()
+				got = append(got, cmd.Cmd)
+
+				if cmd.Cmd != CommandRequestget {
+					return http.StatusOK, &Response{Status: "ok"}
+				}
+
+				body := "<html><head><title>Home</title></head></html>"
+				if challenges > 0 {
+					challenges--
+					body = challenge
+				}
+				return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{URL: cmd.URL, Status: http.StatusOK, Response: body}}
+			})
+
+			c := New(srv.baseURL, time.Second, srv.httpClient, tt.opts...)
+			resp, err := c.Get(context.Background(), "https://example.com", session)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				var cpe *ChallengePageError
+				if !errors.Is(err, ErrChallengePageReturned) || !errors.As(err, &cpe) || cpe.Vendor != VendorCloudflare {
+					t.Errorf("Get() error = %v, want a *ChallengePageError from cloudflare", err)
+				}
+			} else if title := resp.Solution.Title(); title != "Home" {
+				t.Errorf("Get() title = %q, want Home", title)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("commands mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
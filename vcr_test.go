@@ -0,0 +1,199 @@
+package flaresolverr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Tests needing a live FlareSolverr replay the commands recorded in testdata/fixtures,
+// unless FLARESOLVERR_URL or FLARESOLVERR_DOCKER points them to a live server.
+// Set FLARESOLVERR_RECORD to record the fixtures again against the live server.
+
+// cassette holds the commands recorded for a test, and the responses to replay.
+type cassette struct {
+	Interactions []interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  json.RawMessage `json:"request"`
+	Status   int             `json:"status"`
+	Response json.RawMessage `json:"response"`
+}
+
+// uuidPattern matches the session IDs, recorded as placeholders since tests use random ones.
+var uuidPattern = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+
+// errNoInteraction when a command was not recorded.
+var errNoInteraction = errors.New("no recorded interaction")
+
+// recorder is a transport recording the commands sent to FlareSolverr, or replaying them.
+type recorder struct {
+	next   http.RoundTripper // nil when replaying
+	path   string
+	record bool
+
+	mu       sync.Mutex
+	cassette cassette
+	played   int
+
+	// sessions maps the session IDs to their placeholders, in order of appearance.
+	sessions map[string]string
+}
+
+// newRecorder returns the transport for the test, and whether it reaches a live server.
+func newRecorder(t *testing.T) (http.RoundTripper, bool) {
+	t.Helper()
+	r := &recorder{
+		path:     filepath.Join("testdata", "fixtures", t.Name()+".json"),
+		sessions: make(map[string]string),
+	}
+
+	switch {
+	case os.Getenv("FLARESOLVERR_RECORD") != "":
+		r.next, r.record = http.DefaultTransport, true
+		t.Cleanup(func() {
+			if err := r.save(); err != nil {
+				t.Errorf("cannot save fixtures: %v", err)
+			}
+		})
+		return r, true
+	case os.Getenv("FLARESOLVERR_URL") != "" || os.Getenv("FLARESOLVERR_DOCKER") != "":
+		return http.DefaultTransport, true
+	}
+
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return http.DefaultTransport, true
+	}
+
+	if err != nil {
+		t.Fatalf("cannot read fixtures: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		t.Fatalf("invalid fixtures %s: %v", r.path, err)
+	}
+
+	return r, false
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	command := r.anonymize(body)
+	if r.record {
+		return r.recordTrip(req, body, command)
+	}
+
+	if r.played >= len(r.cassette.Interactions) {
+		return nil, fmt.Errorf("%w: %s", errNoInteraction, command)
+	}
+
+	recorded := r.cassette.Interactions[r.played]
+	if !sameCommand(recorded.Request, command) {
+		return nil, fmt.Errorf("%w: %s, recorded %s", errNoInteraction, command, recorded.Request)
+	}
+	r.played++
+
+	return &http.Response{
+		StatusCode:    recorded.Status,
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(r.restore(recorded.Response))),
+		ContentLength: -1,
+		Request:       req,
+	}, nil
+}
+
+func (r *recorder) recordTrip(req *http.Request, body, command []byte) (*http.Response, error) {
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction{
+		Request:  command,
+		Status:   resp.StatusCode,
+		Response: r.anonymize(bytes.TrimSpace(data)),
+	})
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.Header.Del("Content-Encoding")
+	return resp, nil
+}
+
+// anonymize replaces the session IDs with their placeholders.
+func (r *recorder) anonymize(data []byte) []byte {
+	return uuidPattern.ReplaceAllFunc(data, func(id []byte) []byte {
+		name, ok := r.sessions[string(id)]
+		if !ok {
+			name = fmt.Sprintf("session-%d", len(r.sessions))
+			r.sessions[string(id)] = name
+		}
+		return []byte(name)
+	})
+}
+
+// restore replaces the placeholders with the session IDs of the replayed test.
+func (r *recorder) restore(data []byte) []byte {
+	pairs := make([]string, 0, 2*len(r.sessions))
+	for id, name := range r.sessions {
+		pairs = append(pairs, `"`+name+`"`, `"`+id+`"`)
+	}
+
+	return []byte(strings.NewReplacer(pairs...).Replace(string(data)))
+}
+
+func (r *recorder) save() error {
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// sameCommand reports whether two commands target the same action, ignoring their timeouts.
+func sameCommand(a, b []byte) bool {
+	type key struct {
+		Cmd      string `json:"cmd"`
+		URL      string `json:"url"`
+		Session  string `json:"session"`
+		PostData string `json:"postData"`
+	}
+
+	var ka, kb key
+	if json.Unmarshal(a, &ka) != nil || json.Unmarshal(b, &kb) != nil {
+		return false
+	}
+
+	return ka == kb
+}
@@ -0,0 +1,80 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Warmup solves the challenges protecting domains, e.g. at startup, so the first requests to them are fast.
+// Domains are hostnames such as example.com, solved over HTTPS, or URLs.
+// Only the cookies are retrieved: they are kept by the site sessions with WithSessionPerDomain,
+// and used for direct requests with WithRevalidation.
+// A bounded number of challenges are solved at the same time, see WithBatchConcurrency.
+// The returned error joins the error of every domain which could not be warmed up.
+func (c *client) Warmup(ctx context.Context, domains []string, opts ...BatchOption) error {
+	o := &batchOptions{concurrency: 4}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	request := o.request
+	if o.session != uuid.Nil {
+		request = append(request[:len(request):len(request)], WithSession(o.session))
+	}
+
+	errs := make([]error, len(domains))
+	slots := make(chan struct{}, o.concurrency)
+
+	var wg sync.WaitGroup
+	for i, domain := range domains {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			for ; i < len(domains); i++ {
+				errs[i] = fmt.Errorf("warm up %s: %w", domains[i], ctx.Err())
+			}
+			wg.Wait()
+			return errors.Join(errs...)
+		}
+
+		wg.Add(1)
+		go func(i int, domain string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := c.warmup(ctx, warmupURL(domain), newRequestOptions(ctx, request)); err != nil {
+				errs[i] = fmt.Errorf("warm up %s: %w", domain, err)
+			}
+		}(i, domain)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (c *client) warmup(ctx context.Context, u string, o *requestOptions) error {
+	clearance, err := c.solveClearance(ctx, u, o)
+	if err != nil {
+		return err
+	}
+
+	if c.clearances != nil {
+		c.clearances.put(u, clearance)
+	}
+
+	return nil
+}
+
+// warmupURL returns the URL of the home page of domain, or domain if it is already a URL.
+func warmupURL(domain string) string {
+	if strings.Contains(domain, "://") {
+		return domain
+	}
+
+	return "https://" + domain + "/"
+}
@@ -0,0 +1,89 @@
+package flaresolverr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_client_Warmup(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("cf_clearance"); err != nil || cookie.Value != "solved" {
+			w.Header().Set("cf-mitigated", "challenge")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("direct " + r.URL.Path))
+	}))
+	defer target.Close()
+
+	var commands, inFlight, maxInFlight atomic.Int32
+	srv := newTestServer(t, func(cmd *flaresolverrCommand) (int, *Response) {
+		commands.Add(1)
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for m := maxInFlight.Load(); n > m && !maxInFlight.CompareAndSwap(m, n); m = maxInFlight.Load() {
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if !cmd.ReturnOnlyCookies {
+			t.Errorf("Warmup() expected returnOnlyCookies")
+		}
+		if strings.Contains(cmd.URL, "down.example") {
+			return http.StatusInternalServerError, &Response{Status: "error", Message: "Error solving the challenge. Timeout after 60.0 seconds."}
+		}
+		return http.StatusOK, &Response{Status: "ok", Solution: &ResponseSolution{
+			URL:       cmd.URL,
+			Status:    http.StatusOK,
+			UserAgent: "Mozilla/5.0",
+			Cookies:   []Cookie{{Name: "cf_clearance", Value: "solved", Expires: float64(time.Now().Add(time.Hour).Unix())}},
+		}}
+	})
+	c := New(srv.baseURL, time.Second, target.Client(), WithResponseCache(time.Minute, 0), WithRevalidation())
+
+	ctx := context.Background()
+	domains := []string{target.URL, "a.example", "b.example", "down.example"}
+	err := c.Warmup(ctx, domains, WithBatchConcurrency(2))
+	var cmdErr *Error
+	if !errors.As(err, &cmdErr) || !strings.HasPrefix(err.Error(), "warm up down.example: ") {
+		t.Errorf("Warmup() error = %v, want the error of down.example", err)
+	}
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("Warmup() solved %d challenges at the same time, want at most 2", got)
+	}
+
+	// the first request is made directly with the warmed up clearance
+	commands.Store(0)
+	resp, err := c.Get(ctx, target.URL+"/a", uuid.Nil)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if resp.Solution.Response != "direct /a" || commands.Load() != 0 {
+		t.Errorf("Get() = %q with %d commands, want a direct request", resp.Solution.Response, commands.Load())
+	}
+}
+
+func Test_warmupURL(t *testing.T) {
+	tests := []struct {
+		domain string
+		want   string
+	}{
+		{domain: "example.com", want: "https://example.com/"},
+		{domain: "https://example.com/login", want: "https://example.com/login"},
+		{domain: "http://localhost:8080", want: "http://localhost:8080"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			if got := warmupURL(tt.domain); got != tt.want {
+				t.Errorf("warmupURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}